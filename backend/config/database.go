@@ -45,6 +45,32 @@ func InitDB() {
 		&models.Subscription{},
 		&models.Payment{},
 		&models.ActivityLog{},
+		&models.SavedSearch{},
+		&models.SavedSearchMatch{},
+		&models.Notification{},
+		&models.PipelineTemplate{},
+		&models.PipelineStage{},
+		&models.ApplicationStatusChange{},
+		&models.Interview{},
+		&models.InterviewSlot{},
+		&models.SchedulingLink{},
+		&models.AvailabilityWindow{},
+		&models.ScorecardTemplate{},
+		&models.ScorecardCompetency{},
+		&models.Scorecard{},
+		&models.ScorecardRating{},
+		&models.Offer{},
+		&models.OfferApproval{},
+		&models.ApplicationForm{},
+		&models.FormQuestion{},
+		&models.ApplicationAnswer{},
+		&models.ExportJob{},
+		&models.JobTemplate{},
+		&models.JobApproval{},
+		&models.JobMember{},
+		&models.RetentionPolicy{},
+		&models.PrivacyNotice{},
+		&models.CandidateMagicLink{},
 	)
 	if err != nil {
 		// Check if error is just "relation already exists" - this is OK, tables exist
@@ -10,7 +10,7 @@ import (
 	"github.com/google/uuid"
 )
 
-// GetActivityLogs returns activity logs for a company (admin view)
+// GetActivityLogs returns activity logs for a company (admin view, company-wide admins only)
 func GetActivityLogs(c *gin.Context) {
 	companyIDVal, exists := c.Get("company_id")
 	if !exists {
@@ -29,6 +29,10 @@ func GetActivityLogs(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID format"})
 		return
 	}
+	// Entries name candidates of every job, so admins limited to some jobs can't read the log
+	if !requireCompanyWideAccess(c) {
+		return
+	}
 
 	var logs []models.ActivityLog
 	query := config.DB.Where("company_id = ?", companyID).
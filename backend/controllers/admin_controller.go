@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminApproverRequest for giving an admin the requisition approver role or taking it away
+type AdminApproverRequest struct {
+	IsApprover *bool `json:"is_approver" binding:"required"`
+}
+
+// AdminRoleRequest for changing an admin's role
+type AdminRoleRequest struct {
+	Role string `json:"role" binding:"required"` // admin (every job) or member (only their hiring teams)
+}
+
+// GetAdmins returns the admins of the company
+func GetAdmins(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var admins []models.Admin
+	if err := config.DB.Where("company_id = ?", companyID).Order("name ASC").Find(&admins).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch admins"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"admins": admins})
+}
+
+// SetAdminApprover gives an admin the approver role, letting them approve job requisitions, or takes it away.
+// Only admins with the admin role may change it.
+func SetAdminApprover(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req AdminApproverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	var current models.Admin
+	if err := config.DB.Where("id = ? AND company_id = ?", adminUUID, companyID).First(&current).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		return
+	}
+	if !services.IsCompanyWideRole(current.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can change who approves requisitions"})
+		return
+	}
+
+	var target models.Admin
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+
+	if target.IsApprover != *req.IsApprover {
+		if err := config.DB.Model(&target).Update("is_approver", *req.IsApprover).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update admin"})
+			return
+		}
+		target.IsApprover = *req.IsApprover
+		services.LogAdminApproverRoleChanged(target.CompanyID, adminUUID, target.ID, target.Name, target.IsApprover)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Admin updated successfully",
+		"admin":   target,
+	})
+}
+
+// SetAdminRole makes an admin company-wide or limits them to the jobs whose hiring team they're on.
+// Only company-wide admins may change it, and the company always keeps at least one.
+func SetAdminRole(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req AdminRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !services.IsValidAdminRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Must be: admin or member"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	var current models.Admin
+	if err := config.DB.Where("id = ? AND company_id = ?", adminUUID, companyID).First(&current).Error; err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin not found"})
+		return
+	}
+	if !services.IsCompanyWideRole(current.Role) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only admins can change roles"})
+		return
+	}
+
+	var target models.Admin
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&target).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Admin not found"})
+		return
+	}
+	oldRole := target.Role
+	if oldRole == req.Role {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Admin updated successfully",
+			"admin":   target,
+		})
+		return
+	}
+
+	if services.IsCompanyWideRole(oldRole) && !services.IsCompanyWideRole(req.Role) {
+		var remaining int64
+		if err := config.DB.Model(&models.Admin{}).
+			Where("company_id = ? AND role = ? AND id <> ?", companyID, models.AdminRoleAdmin, target.ID).
+			Count(&remaining).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update admin"})
+			return
+		}
+		if remaining == 0 {
+			c.JSON(http.StatusConflict, gin.H{"error": "Your company needs at least one admin"})
+			return
+		}
+	}
+
+	if err := config.DB.Model(&target).Update("role", req.Role).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update admin"})
+		return
+	}
+	target.Role = req.Role
+	services.LogAdminRoleChanged(target.CompanyID, adminUUID, target.ID, target.Name, oldRole, target.Role)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Admin updated successfully",
+		"admin":   target,
+	})
+}
@@ -54,6 +54,9 @@ func AIShortlistApplication(c *gin.Context) {
 		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Build criteria
 	criteria := services.Criteria{
@@ -154,6 +157,9 @@ func BatchAIShortlist(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
 
 	// Get pending applications for this job
 	var applications []models.Application
@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// GetHeadcountAnalytics compares the positions the company's jobs were opened for with those filled,
+// overall and per job. Admins without a company-wide role only see their hiring teams' jobs.
+// Query parameters: status (open, scheduled or closed) to report on those jobs only.
+func GetHeadcountAnalytics(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	status := c.Query("status")
+	jobs := func() *gorm.DB {
+		query := scope.Apply(config.DB.Where("company_id = ?", companyID), "id")
+		if status != "" {
+			query = query.Where("status = ?", status)
+		}
+		return query
+	}
+
+	summary, err := services.SummarizeHeadcount(jobs())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute headcount"})
+		return
+	}
+	perJob, err := services.ListJobHeadcounts(jobs())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to compute headcount"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"summary": summary,
+		"jobs":    perJob,
+	})
+}
@@ -4,24 +4,35 @@ import (
 	"ats-backend/config"
 	"ats-backend/models"
 	"ats-backend/services"
+	"ats-backend/utils"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
+	"strconv"
+	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // SubmitApplication handles job application submission
 func SubmitApplication(c *gin.Context) {
-	var application models.Application
-	if err := c.ShouldBindJSON(&application); err != nil {
+	var req SubmitApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	application := req.toApplication()
+
+	// Apply links from job feeds carry the board as ?source=, e.g. /apply/<job>?source=indeed
+	if strings.TrimSpace(application.ReferralSource) == "" {
+		application.ReferralSource = services.TrackingSource(c.Query("source"))
+	}
 
 	// Check if job exists and is open
 	var job models.Job
@@ -53,18 +64,73 @@ func SubmitApplication(c *gin.Context) {
 		return
 	}
 
+	// Enforce the company's reapplication rules
+	if err := services.CheckReapplication(job.CompanyID, job.ID, application.Email); err != nil {
+		var reapplyErr *services.ReapplicationError
+		if errors.As(err, &reapplyErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":       "Already applied",
+				"message":     reapplyErr.Message,
+				"retry_after": reapplyErr.RetryAfter,
+			})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
+		return
+	}
+
+	// Check the screening answers against the job's application form
+	form, err := services.GetJobApplicationForm(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load application form"})
+		return
+	}
+	answers, answerErrors := services.ValidateFormAnswers(form, req.Answers)
+	if len(answerErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":         "Some answers are missing or invalid",
+			"answer_errors": answerErrors,
+		})
+		return
+	}
+	screening := services.ApplyKnockoutRules(form, answers)
+
+	// Record the privacy notice the candidate accepted and what they opted in to
+	notice, err := services.CurrentPrivacyNotice(job.CompanyID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load privacy notice"})
+		return
+	}
+	if err := services.ApplyConsent(&application, notice, req.Consent, c.ClientIP()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":                  "Privacy notice not accepted",
+			"message":                err.Error(),
+			"privacy_notice_version": notice.Version,
+		})
+		return
+	}
+
 	// Set application timestamp
 	application.AppliedAt = time.Now()
-	application.Status = "pending"
+	application.Status = services.InitialStageKey(job.CompanyID, jobID)
 	// Set company_id from job (for tracking even if job is deleted later)
 	application.CompanyID = job.CompanyID
+	application.Answers = answers
+	if form != nil {
+		application.ScreeningResult = screening.Result
+	}
 
-	// Save to database
-	if err := config.DB.Create(&application).Error; err != nil {
+	// Save to database (with the first status history entry and the answers)
+	if err := services.CreateApplicationWithHistory(&application, services.StatusChangeActor{Type: models.StatusActorCandidate}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit application"})
 		return
 	}
 
+	// Knocked out candidates go straight to the rejected stage; the recruiter decides when to tell them
+	if screening.Result == models.ScreeningKnockedOut {
+		rejectKnockedOutApplication(&application, screening)
+	}
+
 	// Parse and store CV text for future searching (async)
 	go func() {
 		if application.ResumeURL != "" {
@@ -90,9 +156,7 @@ func SubmitApplication(c *gin.Context) {
 			}
 			
 			// Update using the application ID (more reliable than using the model)
-			updateErr := config.DB.Model(&models.Application{}).
-				Where("id = ?", application.ID).
-				Update("parsed_cv_text", cvText).Error
+			updateErr := services.SaveParsedCVText(application.ID, cvText)
 			
 			if updateErr != nil {
 				log.Printf("ERROR: Failed to save parsed CV text to database for %s: %v", 
@@ -152,8 +216,14 @@ func SubmitApplication(c *gin.Context) {
 		analysisJSONStr := string(analysisJSON)
 		application.AnalysisResult = &analysisJSONStr
 		
-		// Save updated application with score
-		if err := config.DB.Save(&application).Error; err != nil {
+		// Save only the analysis columns - a full Save would overwrite the parsed CV text
+		// stored concurrently by the parsing goroutine above
+		if err := config.DB.Model(&models.Application{}).
+			Where("id = ?", application.ID).
+			Updates(map[string]interface{}{
+				"score":           application.Score,
+				"analysis_result": application.AnalysisResult,
+			}).Error; err != nil {
 			log.Printf("ERROR: Failed to save analysis result for application %s: %v", application.ID.String(), err)
 			return
 		}
@@ -178,73 +248,450 @@ func SubmitApplication(c *gin.Context) {
 	})
 }
 
-// GetApplications returns all applications for company's jobs
-func GetApplications(c *gin.Context) {
-	companyIDVal, exists := c.Get("company_id")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+// SubmitApplicationRequest is what a candidate fills in when applying, with the answers to the
+// job's screening questions. Everything else on the application (status, score, screening, trash,
+// consent records) is set by the server.
+type SubmitApplicationRequest struct {
+	JobID             *uuid.UUID                  `json:"job_id"`
+	FullName          string                      `json:"full_name"`
+	Email             string                      `json:"email"`
+	Phone             string                      `json:"phone"`
+	ResumeURL         string                      `json:"resume_url"`
+	CoverLetter       string                      `json:"cover_letter"`
+	YearsOfExperience int                         `json:"years_of_experience"`
+	CurrentPosition   string                      `json:"current_position"`
+	LinkedinURL       string                      `json:"linkedin_url"`
+	PortfolioURL      string                      `json:"portfolio_url"`
+	ReferralSource    string                      `json:"referral_source"`
+	ReferredByName    string                      `json:"referred_by_name"`
+	ReferredByEmail   string                      `json:"referred_by_email"`
+	ReferredByPhone   string                      `json:"referred_by_phone"`
+	Answers           map[string]interface{}      `json:"answers"` // Keyed by question key
+	Consent           services.ApplicationConsent `json:"consent"` // Privacy notice accepted and opt-ins
+}
+
+// toApplication copies the candidate-supplied fields onto a new application
+func (req SubmitApplicationRequest) toApplication() models.Application {
+	return models.Application{
+		JobID:             req.JobID,
+		FullName:          req.FullName,
+		Email:             req.Email,
+		Phone:             req.Phone,
+		ResumeURL:         req.ResumeURL,
+		CoverLetter:       req.CoverLetter,
+		YearsOfExperience: req.YearsOfExperience,
+		CurrentPosition:   req.CurrentPosition,
+		LinkedinURL:       req.LinkedinURL,
+		PortfolioURL:      req.PortfolioURL,
+		ReferralSource:    req.ReferralSource,
+		ReferredByName:    req.ReferredByName,
+		ReferredByEmail:   req.ReferredByEmail,
+		ReferredByPhone:   req.ReferredByPhone,
+	}
+}
+
+// rejectKnockedOutApplication moves an application whose answers hit a "reject" knockout rule
+// to the first rejected stage of its pipeline
+func rejectKnockedOutApplication(application *models.Application, screening services.ScreeningOutcome) {
+	stages, err := services.GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load pipeline to knock out application %s: %v", application.ID, err)
 		return
 	}
-	companyID, ok := companyIDVal.(string)
-	if !ok || companyID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+	rejected, found := services.FirstStageOfType(stages, models.StageTypeRejected)
+	if !found {
 		return
 	}
-	var applications []models.Application
 
-	// Get all applications for this company's ACTIVE jobs only
-	// Exclude applications where job_id IS NULL (deleted jobs) - these should not show in Applications tab
-	// But they will still be available in Find Candidates search
-	// Using INNER JOIN is optimized - automatically excludes NULL job_id and uses indexes
-	query := config.DB.Table("applications").
-		Select("applications.*").
-		Joins("INNER JOIN jobs ON jobs.id = applications.job_id").
-		Where("jobs.company_id = ?", companyID).
-		Preload("Job")
+	fromStage := application.Status
+	now := time.Now()
+	application.Status = rejected.Key
+	application.LastStatusUpdate = &now
+	reason := "Knocked out by screening question: " + strings.Join(screening.Reasons, ", ")
+	if err := services.SaveApplicationStatus(application, fromStage, services.StatusChangeActor{Type: models.StatusActorSystem}, reason, false); err != nil {
+		log.Printf("ERROR: Failed to knock out application %s: %v", application.ID, err)
+		application.Status = fromStage
+		return
+	}
+	log.Printf("Application %s knocked out: %s", application.ID, reason)
+}
 
-	// Filter by job_id if provided
-	if jobID := c.Query("job_id"); jobID != "" {
-		query = query.Where("applications.job_id = ?", jobID)
+// applicationListColumns are the columns returned by the applications listing.
+// The parsed CV text and AI analysis are large, so they are only loaded with include=cv_text,analysis.
+var applicationListColumns = []string{
+	"id", "job_id", "company_id", "full_name", "email", "phone", "resume_url", "cover_letter",
+	"years_of_experience", "current_position", "linkedin_url", "portfolio_url", "status", "score",
+	"cv_parsed_at", "applied_at", "reviewed_at", "reviewed_by", "cv_viewed_at", "cv_viewed_by",
+	"expected_response_date", "last_status_update", "referral_source", "referred_by_name",
+	"referred_by_email", "referred_by_phone", "in_talent_pool", "talent_pool_added_at",
+	"talent_pool_added_by", "tags", "screening_result",
+}
+
+// applicationSortColumns maps the supported sort_by values to the column they sort on
+var applicationSortColumns = map[string]string{
+	"applied_at": "applications.applied_at",
+	"score":      "applications.score",
+	"name":       "LOWER(applications.full_name)",
+}
+
+// applicationsCursor marks the last application of a page: its sort value and ID
+type applicationsCursor struct {
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// applicationListFilters are the filters and sorting of the applications listing, shared with its export
+type applicationListFilters struct {
+	JobID           string
+	Stages          []string
+	Source          string
+	InTalentPool    *bool
+	Tags            []string
+	MinScore        *int
+	MaxScore        *int
+	ScreeningResult string
+	AnswerKey       string
+	AnswerValue     string
+	DateFrom        string // YYYY-MM-DD
+	DateTo          string // YYYY-MM-DD
+	SortBy          string // applied_at, score or name
+	Descending      bool
+	Scope           services.JobScope // Jobs the admin may see
+}
+
+// applicationSortValue returns the sort value of an application as stored in a cursor
+func applicationSortValue(application models.Application, sortBy string) string {
+	switch sortBy {
+	case "score":
+		return strconv.Itoa(application.Score)
+	case "name":
+		return strings.ToLower(application.FullName)
+	default:
+		return application.AppliedAt.Format(time.RFC3339Nano)
+	}
+}
+
+// parseApplicationSortValue converts a cursor's sort value back to the type of the sort column
+func parseApplicationSortValue(value string, sortBy string) (interface{}, error) {
+	switch sortBy {
+	case "score":
+		return strconv.Atoi(value)
+	case "name":
+		return value, nil
+	default:
+		return time.Parse(time.RFC3339Nano, value)
+	}
+}
+
+// splitQueryList splits a comma separated query parameter, dropping empty entries
+func splitQueryList(value string) []string {
+	items := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseApplicationListFilters reads the listing filters from the query string
+func parseApplicationListFilters(c *gin.Context) (applicationListFilters, error) {
+	filters := applicationListFilters{
+		JobID:           c.Query("job_id"),
+		Stages:          append(splitQueryList(c.Query("stage")), splitQueryList(c.Query("status"))...),
+		Source:          strings.TrimSpace(c.Query("source")),
+		ScreeningResult: c.Query("screening_result"),
+		AnswerKey:       c.Query("answer_key"),
+		AnswerValue:     c.Query("answer_value"),
+		SortBy:          strings.ToLower(strings.TrimSpace(c.DefaultQuery("sort_by", "applied_at"))),
+		Descending:      strings.ToLower(c.Query("sort_order")) != "asc",
+	}
+	if _, ok := applicationSortColumns[filters.SortBy]; !ok {
+		return filters, errors.New("Invalid sort_by. Must be: applied_at, score, or name")
 	}
 
-	// Filter by status if provided
-	if status := c.Query("status"); status != "" {
-		query = query.Where("applications.status = ?", status)
+	for _, tag := range splitQueryList(c.Query("tags")) {
+		filters.Tags = append(filters.Tags, strings.ToLower(tag))
 	}
 
-	// Filter by date range (applied_at date)
+	for param, target := range map[string]**int{"min_score": &filters.MinScore, "max_score": &filters.MaxScore} {
+		if value := c.Query(param); value != "" {
+			score, err := strconv.Atoi(value)
+			if err != nil {
+				return filters, errors.New(param + " must be a number")
+			}
+			*target = &score
+		}
+	}
+
+	if value := c.Query("in_talent_pool"); value != "" {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return filters, errors.New("in_talent_pool must be true or false")
+		}
+		filters.InTalentPool = &parsed
+	}
+
+	// Invalid dates are ignored rather than rejected
 	if dateFrom := c.Query("date_from"); dateFrom != "" {
-		// Parse date and filter by date (ignoring time)
-		if dateFromTime, err := time.Parse("2006-01-02", dateFrom); err == nil {
-			// Use PostgreSQL DATE() function to extract date part and compare
-			// This ensures timezone-independent date comparison
-			query = query.Where("DATE(applications.applied_at) >= ?", dateFromTime.Format("2006-01-02"))
-			log.Printf("Filtering applications from date: %s", dateFromTime.Format("2006-01-02"))
+		if _, err := time.Parse("2006-01-02", dateFrom); err == nil {
+			filters.DateFrom = dateFrom
 		} else {
 			log.Printf("Invalid date_from format: %s, error: %v", dateFrom, err)
 		}
 	}
 	if dateTo := c.Query("date_to"); dateTo != "" {
-		// Parse date and filter by date (ignoring time)
-		if dateToTime, err := time.Parse("2006-01-02", dateTo); err == nil {
-			// Use PostgreSQL DATE() function to extract date part and compare
-			query = query.Where("DATE(applications.applied_at) <= ?", dateToTime.Format("2006-01-02"))
-			log.Printf("Filtering applications to date: %s", dateToTime.Format("2006-01-02"))
+		if _, err := time.Parse("2006-01-02", dateTo); err == nil {
+			filters.DateTo = dateTo
 		} else {
 			log.Printf("Invalid date_to format: %s, error: %v", dateTo, err)
 		}
 	}
+	return filters, nil
+}
+
+// applicationListQuery builds a fresh query for the company's applications matching the filters.
+// Only applications of ACTIVE jobs are listed: the INNER JOIN excludes applications whose job
+// was deleted (job_id IS NULL). Those are still available in Find Candidates search.
+func applicationListQuery(companyID string, filters applicationListFilters) *gorm.DB {
+	query := config.DB.Table("applications").
+		Joins("INNER JOIN jobs ON jobs.id = applications.job_id").
+		Where("jobs.company_id = ?", companyID).
+		Where("applications.deleted_at IS NULL AND jobs.deleted_at IS NULL") // Counts don't skip the trash by themselves
+	query = filters.Scope.Apply(query, "applications.job_id")
+
+	if filters.JobID != "" {
+		query = query.Where("applications.job_id = ?", filters.JobID)
+	}
+	if len(filters.Stages) > 0 {
+		query = query.Where("applications.status IN ?", filters.Stages)
+	}
+	if filters.Source != "" {
+		query = query.Where("LOWER(applications.referral_source) = ?", strings.ToLower(filters.Source))
+	}
+	if filters.InTalentPool != nil {
+		query = query.Where("applications.in_talent_pool = ?", *filters.InTalentPool)
+	}
+	// Every requested tag must be present
+	for _, tag := range filters.Tags {
+		query = query.Where("EXISTS (SELECT 1 FROM jsonb_array_elements_text(COALESCE(applications.tags, '[]'::jsonb)) AS tag WHERE tag = ?)", tag)
+	}
+	if filters.MinScore != nil {
+		query = query.Where("applications.score >= ?", *filters.MinScore)
+	}
+	if filters.MaxScore != nil {
+		query = query.Where("applications.score <= ?", *filters.MaxScore)
+	}
+
+	// Filter by screening result (passed, flagged, knocked_out)
+	if filters.ScreeningResult != "" {
+		query = query.Where("applications.screening_result = ?", filters.ScreeningResult)
+	}
+
+	// Filter by a screening answer, e.g. answer_key=work_permit&answer_value=yes
+	if filters.AnswerKey != "" {
+		query = services.ApplyAnswerFilterSQL(query, services.AnswerFilter{
+			QuestionKey: filters.AnswerKey,
+			Equals:      filters.AnswerValue,
+		})
+	}
+
+	// Filter by date range, using DATE() for a timezone-independent comparison
+	if filters.DateFrom != "" {
+		query = query.Where("DATE(applications.applied_at) >= ?", filters.DateFrom)
+	}
+	if filters.DateTo != "" {
+		query = query.Where("DATE(applications.applied_at) <= ?", filters.DateTo)
+	}
+	return query
+}
+
+// applicationListOrder returns the ORDER BY clause of the listing, ties broken by application ID
+func applicationListOrder(filters applicationListFilters) string {
+	direction := "ASC"
+	if filters.Descending {
+		direction = "DESC"
+	}
+	return fmt.Sprintf("%s %s, applications.id %s", applicationSortColumns[filters.SortBy], direction, direction)
+}
+
+// applicationListSelect returns the selected columns, adding the CV text and analysis only when asked for
+func applicationListSelect(includeCVText, includeAnalysis bool) string {
+	columns := make([]string, 0, len(applicationListColumns)+2)
+	for _, column := range applicationListColumns {
+		columns = append(columns, "applications."+column)
+	}
+	if includeCVText {
+		columns = append(columns, "applications.parsed_cv_text")
+	}
+	if includeAnalysis {
+		columns = append(columns, "applications.analysis_result")
+	}
+	return strings.Join(columns, ", ")
+}
+
+// preloadListJob loads the few job fields the listing shows
+func preloadListJob(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "company_id", "title", "location", "status")
+}
+
+// GetApplications lists the applications of a company's active jobs, one page at a time.
+// Admins without a company-wide role only see the jobs whose hiring team they're on.
+// Query parameters:
+//   - limit (default 50, max 100), cursor (next_cursor of the previous page)
+//   - sort_by: applied_at (default), score or name; sort_order: desc (default) or asc
+//   - job_id, stage (or status; comma separated), source, in_talent_pool, tags (comma separated, all must match)
+//   - min_score, max_score, date_from, date_to, screening_result, answer_key/answer_value
+//   - include: cv_text and/or analysis to load the parsed CV text and AI analysis
+func GetApplications(c *gin.Context) {
+	companyIDVal, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	companyID, ok := companyIDVal.(string)
+	if !ok || companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+
+	filters, err := parseApplicationListFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filters.Scope, ok = loadJobScope(c); !ok {
+		return
+	}
+
+	limit := 50
+	if limitStr := c.Query("limit"); limitStr != "" {
+		parsed, err := strconv.Atoi(limitStr)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+			return
+		}
+		if parsed < 100 {
+			limit = parsed
+		} else {
+			limit = 100
+		}
+	}
+
+	var cursorValue interface{}
+	var cursorID string
+	if cursorStr := c.Query("cursor"); cursorStr != "" {
+		var cursor applicationsCursor
+		if err := utils.DecodeCursor(cursorStr, &cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		value, err := parseApplicationSortValue(cursor.Value, filters.SortBy)
+		if _, idErr := uuid.Parse(cursor.ID); err != nil || idErr != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
+		}
+		cursorValue, cursorID = value, cursor.ID
+	}
+
+	var total int64
+	if err := applicationListQuery(companyID, filters).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count applications"})
+		return
+	}
+
+	includes := splitQueryList(c.Query("include"))
+	query := applicationListQuery(companyID, filters).
+		Select(applicationListSelect(containsQueryValue(includes, "cv_text"), containsQueryValue(includes, "analysis"))).
+		Preload("Job", preloadListJob)
+	if cursorValue != nil {
+		comparison := ">"
+		if filters.Descending {
+			comparison = "<"
+		}
+		query = query.Where(fmt.Sprintf("(%s, applications.id) %s (?, ?)", applicationSortColumns[filters.SortBy], comparison), cursorValue, cursorID)
+	}
 
-	if err := query.Find(&applications).Error; err != nil {
+	// Fetch one extra row to know whether there is a next page
+	var applications []models.Application
+	if err := query.Order(applicationListOrder(filters)).Limit(limit + 1).Find(&applications).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"applications": applications})
+	hasMore := len(applications) > limit
+	if hasMore {
+		applications = applications[:limit]
+	}
+	nextCursor := ""
+	if hasMore {
+		last := applications[len(applications)-1]
+		nextCursor, _ = utils.EncodeCursor(applicationsCursor{
+			Value: applicationSortValue(last, filters.SortBy),
+			ID:    last.ID.String(),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"applications": applications,
+		"count":        len(applications),
+		"total":        total,
+		"has_more":     hasMore,
+		"next_cursor":  nextCursor,
+	})
 }
 
-// ShortlistApplication marks an application as shortlisted
+// containsQueryValue reports whether a split query list contains value
+func containsQueryValue(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MoveApplicationStageRequest for moving an application to another pipeline stage
+type MoveApplicationStageRequest struct {
+	Stage  string `json:"stage" binding:"required"` // Stage key, e.g. "tech_interview"
+	Reason string `json:"reason"`                    // Recorded in the status history
+	Reopen bool   `json:"reopen"`                    // Required to move a hired, rejected or withdrawn application
+}
+
+// MoveApplicationStage moves an application to any stage of its job's pipeline
+func MoveApplicationStage(c *gin.Context) {
+	var req MoveApplicationStageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stageKey := services.NormalizeStageKey(req.Stage)
+	moveApplicationToStage(c, func(stages []models.PipelineStage) (*models.PipelineStage, bool) {
+		return services.FindPipelineStage(stages, stageKey)
+	}, "", req.Reason, req.Reopen)
+}
+
+// ShortlistApplication marks an application as shortlisted.
+// Kept for compatibility: equivalent to moving it to the "shortlisted" stage.
 func ShortlistApplication(c *gin.Context) {
+	moveApplicationToStage(c, func(stages []models.PipelineStage) (*models.PipelineStage, bool) {
+		return services.FindPipelineStage(stages, "shortlisted")
+	}, "Application shortlisted successfully", "", false)
+}
+
+// RejectApplication marks an application as rejected.
+// Kept for compatibility: equivalent to moving it to the pipeline's first rejected stage.
+func RejectApplication(c *gin.Context) {
+	moveApplicationToStage(c, func(stages []models.PipelineStage) (*models.PipelineStage, bool) {
+		return services.FirstStageOfType(stages, models.StageTypeRejected)
+	}, "Application rejected", "", false)
+}
+
+// moveApplicationToStage loads the application, picks the target stage from its job's pipeline,
+// checks the transition, saves the change with its history entry, logs it and notifies the candidate.
+// An empty message gets a generic one.
+func moveApplicationToStage(c *gin.Context, pickStage func(stages []models.PipelineStage) (*models.PipelineStage, bool), message, reason string, reopen bool) {
 	applicationID := c.Param("id")
 	companyIDVal, exists := c.Get("company_id")
 	if !exists {
@@ -270,64 +717,120 @@ func ShortlistApplication(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	stages, err := services.GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline"})
+		return
+	}
+	stage, found := pickStage(stages)
+	if !found {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":  "Stage not found in this job's pipeline",
+			"stages": stages,
+		})
+		return
+	}
 
 	// Store old status for logging
 	oldStatus := application.Status
 
+	if err := services.ValidateStageTransition(stages, oldStatus, stage.Key, reopen); err != nil {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":          err.Error(),
+			"current_stage":  oldStatus,
+			"allowed_stages": services.AllowedStageTransitions(stages, oldStatus),
+		})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
 	// Update status
 	now := time.Now()
-	application.Status = "shortlisted"
+	application.Status = stage.Key
 	application.ReviewedAt = &now
 	application.LastStatusUpdate = &now
-	// Set expected response date (5 days from now)
-	expectedDate := now.AddDate(0, 0, 5)
-	application.ExpectedResponseDate = &expectedDate
+	if stage.Type == models.StageTypeActive {
+		// Set expected response date (5 days from now)
+		expectedDate := now.AddDate(0, 0, 5)
+		application.ExpectedResponseDate = &expectedDate
+	}
 
-	if err := config.DB.Save(&application).Error; err != nil {
+	if err := services.SaveApplicationStatus(&application, oldStatus, services.AdminActor(adminUUID), reason, reopen); err != nil {
+		var transitionErr *services.StageTransitionError
+		if errors.As(err, &transitionErr) {
+			c.JSON(http.StatusConflict, gin.H{
+				"error":          err.Error(),
+				"current_stage":  transitionErr.From,
+				"allowed_stages": services.AllowedStageTransitions(stages, transitionErr.From),
+			})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
 		return
 	}
 
-	// Log application shortlisting
+	// Log stage change
 	companyUUID, _ := uuid.Parse(companyID)
-	adminIDVal, _ := c.Get("admin_id")
-	adminIDStr, _ := adminIDVal.(string)
-	adminUUID, _ := uuid.Parse(adminIDStr)
-	applicationUUID, _ := uuid.Parse(applicationID)
 	jobTitle := "Unknown Job (Job Deleted)"
 	if application.JobID != nil && application.Job.ID != uuid.Nil {
 		jobTitle = application.Job.Title
 	}
 
-	services.LogApplicationStatusChanged(companyUUID, adminUUID, applicationUUID, application.FullName, jobTitle, oldStatus, "shortlisted")
+	services.LogApplicationStatusChanged(companyUUID, adminUUID, application.ID, application.FullName, jobTitle, oldStatus, stage.Key)
 
-	// Send shortlist email and SMS (async with error logging)
-	go func() {
-		log.Printf("Sending shortlist email to %s for application %s", application.Email, applicationID)
+	// Send email and SMS for stages candidates are told about (async with error logging)
+	go notifyCandidateOfStage(application, jobTitle, *stage)
+
+	if message == "" {
+		message = fmt.Sprintf("Application moved to %s", stage.Name)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":     message,
+		"application": application,
+		"stage":       stage,
+	})
+}
+
+// notifyCandidateOfStage sends the shortlist or rejection email and the status SMS for a stage change
+func notifyCandidateOfStage(application models.Application, jobTitle string, stage models.PipelineStage) {
+	smsStatus := stage.Key
+	switch {
+	case stage.Type == models.StageTypeRejected:
+		smsStatus = "rejected"
+		log.Printf("Sending rejection email to %s", application.Email)
+		if err := services.SendRejectionEmail(application.Email, application.FullName, jobTitle); err != nil {
+			log.Printf("ERROR: Failed to send rejection email to %s: %v", application.Email, err)
+		} else {
+			log.Printf("SUCCESS: Rejection email sent to %s", application.Email)
+		}
+	case stage.Key == "shortlisted":
+		log.Printf("Sending shortlist email to %s for application %s", application.Email, application.ID)
 		if err := services.SendShortlistEmail(application.Email, application.FullName, jobTitle); err != nil {
 			log.Printf("ERROR: Failed to send shortlist email to %s: %v", application.Email, err)
 		} else {
 			log.Printf("SUCCESS: Shortlist email sent to %s", application.Email)
 		}
-		
-		// Send SMS notification
-		if application.Phone != "" {
-			if err := services.SendStatusUpdateSMS(application.Phone, application.FullName, jobTitle, "shortlisted"); err != nil {
-				log.Printf("ERROR: Failed to send shortlist SMS to %s: %v", application.Phone, err)
-			} else {
-				log.Printf("SUCCESS: Shortlist SMS sent to %s", application.Phone)
-			}
-		}
-	}()
+	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"message":     "Application shortlisted successfully",
-		"application": application,
-	})
+	// Send SMS notification (only sent for statuses it has a message for)
+	if application.Phone != "" {
+		if err := services.SendStatusUpdateSMS(application.Phone, application.FullName, jobTitle, smsStatus); err != nil {
+			log.Printf("ERROR: Failed to send %s SMS to %s: %v", smsStatus, application.Phone, err)
+		} else {
+			log.Printf("SUCCESS: %s SMS sent to %s", smsStatus, application.Phone)
+		}
+	}
 }
 
-// RejectApplication marks an application as rejected
-func RejectApplication(c *gin.Context) {
+// GetApplicationHistory returns an application's stage history and the stages it can move to next
+func GetApplicationHistory(c *gin.Context) {
 	applicationID := c.Param("id")
 	companyIDVal, exists := c.Get("company_id")
 	if !exists {
@@ -346,61 +849,32 @@ func RejectApplication(c *gin.Context) {
 		Select("applications.*").
 		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
 		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", applicationID, companyID, companyID).
-		Preload("Job").
 		First(&application).Error
 
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
-
-	// Store old status for logging
-	oldStatus := application.Status
-
-	now := time.Now()
-	application.Status = "rejected"
-	application.ReviewedAt = &now
-	application.LastStatusUpdate = &now
-
-	if err := config.DB.Save(&application).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
+	if !applicationInJobScope(c, application) {
 		return
 	}
 
-	// Log application rejection
-	companyUUID, _ := uuid.Parse(companyID)
-	adminIDVal, _ := c.Get("admin_id")
-	adminIDStr, _ := adminIDVal.(string)
-	adminUUID, _ := uuid.Parse(adminIDStr)
-	applicationUUID, _ := uuid.Parse(applicationID)
-	jobTitle := "Unknown Job (Job Deleted)"
-	if application.JobID != nil && application.Job.ID != uuid.Nil {
-		jobTitle = application.Job.Title
+	history, err := services.GetApplicationStatusHistory(application.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch status history"})
+		return
+	}
+	stages, err := services.GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline"})
+		return
 	}
-
-	services.LogApplicationStatusChanged(companyUUID, adminUUID, applicationUUID, application.FullName, jobTitle, oldStatus, "rejected")
-
-	// Send rejection email and SMS (async with error logging)
-	go func() {
-		log.Printf("Sending rejection email to %s", application.Email)
-		if err := services.SendRejectionEmail(application.Email, application.FullName, jobTitle); err != nil {
-			log.Printf("ERROR: Failed to send rejection email to %s: %v", application.Email, err)
-		} else {
-			log.Printf("SUCCESS: Rejection email sent to %s", application.Email)
-		}
-		
-		// Send SMS notification
-		if application.Phone != "" {
-			if err := services.SendStatusUpdateSMS(application.Phone, application.FullName, jobTitle, "rejected"); err != nil {
-				log.Printf("ERROR: Failed to send rejection SMS to %s: %v", application.Phone, err)
-			} else {
-				log.Printf("SUCCESS: Rejection SMS sent to %s", application.Phone)
-			}
-		}
-	}()
 
 	c.JSON(http.StatusOK, gin.H{
-		"message": "Application rejected",
+		"current_stage":  application.Status,
+		"history":        history,
+		"stages":         stages,
+		"allowed_stages": services.AllowedStageTransitions(stages, application.Status),
 	})
 }
 
@@ -431,6 +905,9 @@ func DeleteApplication(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Store application details for logging before deletion
 	applicantName := application.FullName
@@ -444,8 +921,8 @@ func DeleteApplication(c *gin.Context) {
 	adminIDStr, _ := adminIDVal.(string)
 	adminUUID, _ := uuid.Parse(adminIDStr)
 
-	// Delete the application
-	if err := config.DB.Delete(&application).Error; err != nil {
+	// Move the application to the trash
+	if err := services.TrashApplication(&application, adminUUID); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete application"})
 		return
 	}
@@ -457,19 +934,20 @@ func DeleteApplication(c *gin.Context) {
 		"application_deleted",
 		"application",
 		&applicationUUID,
-		"Application deleted: "+applicantName+" for job: "+jobTitle,
+		"Application moved to the trash: "+applicantName+" for job: "+jobTitle,
 		map[string]interface{}{
 			"applicant_name": applicantName,
 			"applicant_email": application.Email,
 			"job_title": jobTitle,
 			"status": application.Status,
+			"purge_at": services.PurgeDate(time.Now()),
 		},
 	)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Application deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Application moved to the trash"})
 }
 
-// BulkDeleteApplications deletes multiple applications by status
+// BulkDeleteApplications moves every application in a stage, or in any stage of a type, to the trash
 func BulkDeleteApplications(c *gin.Context) {
 	companyIDVal, exists := c.Get("company_id")
 	if !exists {
@@ -483,42 +961,73 @@ func BulkDeleteApplications(c *gin.Context) {
 	}
 
 	var req struct {
-		Status string `json:"status" binding:"required"` // pending, shortlisted, rejected
+		Status    string `json:"status"`     // Stage key, e.g. pending
+		StageType string `json:"stage_type"` // Or every stage of a type: active, hired, rejected, withdrawn
 	}
 
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
-	// Validate status
-	validStatuses := []string{"pending", "shortlisted", "rejected"}
-	isValid := false
-	for _, s := range validStatuses {
-		if req.Status == s {
-			isValid = true
-			break
-		}
+	if (req.Status == "") == (req.StageType == "") {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Give either a status or a stage_type"})
+		return
 	}
-	if !isValid {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Must be: pending, shortlisted, or rejected"})
+	if req.StageType != "" && !services.IsValidStageType(req.StageType) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid stage_type. Must be: active, hired, rejected, or withdrawn"})
 		return
 	}
 
-	// Get applications to delete
-	var applications []models.Application
-	err := config.DB.Table("applications").
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	// Get the company's applications the admin may see, then keep those in the requested stage of their own job's pipeline
+	query := scope.Apply(config.DB.Table("applications"), "applications.job_id").
 		Select("applications.*").
 		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
-		Where("applications.status = ? AND (applications.company_id = ? OR jobs.company_id = ?)", req.Status, companyID, companyID).
-		Preload("Job").
-		Find(&applications).Error
-
-	if err != nil {
+		Where("applications.deleted_at IS NULL AND (applications.company_id = ? OR jobs.company_id = ?)", companyID, companyID)
+	if req.Status != "" {
+		query = query.Where("applications.status = ?", req.Status)
+	}
+	var candidates []models.Application
+	if err := query.Preload("Job").Find(&candidates).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch applications"})
 		return
 	}
 
+	companyUUID, _ := uuid.Parse(companyID)
+	pipelines := services.PipelineCache{}
+	knownStage := false
+	if req.Status != "" {
+		if stages, err := pipelines.Stages(companyUUID, nil); err == nil {
+			_, knownStage = services.FindPipelineStage(stages, req.Status)
+		}
+	}
+	var applications []models.Application
+	for _, app := range candidates {
+		stages, err := pipelines.Stages(app.CompanyID, app.JobID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline stages"})
+			return
+		}
+		stage, found := services.FindPipelineStage(stages, app.Status)
+		if !found {
+			continue
+		}
+		if req.Status != "" {
+			knownStage = true
+			applications = append(applications, app)
+		} else if stage.Type == req.StageType {
+			applications = append(applications, app)
+		}
+	}
+	if req.Status != "" && !knownStage {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid status: %q is not a stage of the company's pipelines", req.Status)})
+		return
+	}
+
 	if len(applications) == 0 {
 		c.JSON(http.StatusOK, gin.H{
 			"message": "No applications found with the specified status",
@@ -531,12 +1040,11 @@ func BulkDeleteApplications(c *gin.Context) {
 	adminIDVal, _ := c.Get("admin_id")
 	adminIDStr, _ := adminIDVal.(string)
 	adminUUID, _ := uuid.Parse(adminIDStr)
-	companyUUID, _ := uuid.Parse(companyID)
 
-	// Delete applications
+	// Move applications to the trash
 	deletedCount := 0
 	for _, app := range applications {
-		if err := config.DB.Delete(&app).Error; err == nil {
+		if err := services.TrashApplication(&app, adminUUID); err == nil {
 			deletedCount++
 			// Log each deletion
 			appUUID, _ := uuid.Parse(app.ID.String())
@@ -550,7 +1058,7 @@ func BulkDeleteApplications(c *gin.Context) {
 				"application_deleted",
 				"application",
 				&appUUID,
-				"Bulk deleted application: "+app.FullName+" for job: "+jobTitle,
+				"Bulk moved application to the trash: "+app.FullName+" for job: "+jobTitle,
 				map[string]interface{}{
 					"applicant_name": app.FullName,
 					"applicant_email": app.Email,
@@ -562,8 +1070,12 @@ func BulkDeleteApplications(c *gin.Context) {
 		}
 	}
 
+	filter := req.Status
+	if filter == "" {
+		filter = req.StageType + " stages"
+	}
 	c.JSON(http.StatusOK, gin.H{
-		"message": fmt.Sprintf("Deleted %d application(s) with status '%s'", deletedCount, req.Status),
+		"message": fmt.Sprintf("Moved %d application(s) in '%s' to the trash", deletedCount, filter),
 		"deleted_count": deletedCount,
 		"total_found": len(applications),
 	})
@@ -597,6 +1109,9 @@ func TrackCVView(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Get admin ID
 	adminIDVal, _ := c.Get("admin_id")
@@ -614,8 +1129,10 @@ func TrackCVView(c *gin.Context) {
 		statusChanged = true
 	}
 
-	// If status is "pending", update to "cv_viewed"
-	if application.Status == "pending" {
+	// If status is "pending", update to "cv_viewed" (when the job's pipeline has that stage)
+	stages, _ := services.GetPipelineStages(application.CompanyID, application.JobID)
+	_, hasCVViewedStage := services.FindPipelineStage(stages, "cv_viewed")
+	if application.Status == "pending" && hasCVViewedStage {
 		application.Status = "cv_viewed"
 		application.LastStatusUpdate = &now
 		// Set expected response date (5 days from now)
@@ -625,7 +1142,7 @@ func TrackCVView(c *gin.Context) {
 	}
 
 	if statusChanged {
-		if err := config.DB.Save(&application).Error; err != nil {
+		if err := services.SaveApplicationStatus(&application, oldStatus, services.AdminActor(adminUUID), "", false); err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update application"})
 			return
 		}
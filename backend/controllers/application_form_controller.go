@@ -0,0 +1,246 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FormQuestionRequest is one question of an application form request
+type FormQuestionRequest struct {
+	Key            string   `json:"key"` // Defaults to the label, e.g. "Notice period?" -> "notice_period"
+	Label          string   `json:"label" binding:"required"`
+	HelpText       string   `json:"help_text"`
+	Type           string   `json:"type" binding:"required"` // text, number, single_choice, multi_choice, yes_no, date, file
+	Required       bool     `json:"required"`
+	Options        []string `json:"options"`
+	MinValue       *float64 `json:"min_value"`
+	MaxValue       *float64 `json:"max_value"`
+	MaxLength      int      `json:"max_length"`
+	KnockoutAction string   `json:"knockout_action"` // reject, flag or empty
+	KnockoutValues []string `json:"knockout_values"`
+	KnockoutBelow  *float64 `json:"knockout_below"`
+	KnockoutAbove  *float64 `json:"knockout_above"`
+}
+
+// ApplicationFormRequest for creating or replacing a job's application form
+type ApplicationFormRequest struct {
+	Questions []FormQuestionRequest `json:"questions"`
+}
+
+// encodeStringList stores a list of strings as a JSON array, or empty when there are none
+func encodeStringList(values []string) string {
+	cleaned := []string{}
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			cleaned = append(cleaned, value)
+		}
+	}
+	if len(cleaned) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(cleaned)
+	return string(encoded)
+}
+
+// buildFormQuestions converts and validates the requested questions, in the order given
+func buildFormQuestions(requested []FormQuestionRequest) ([]models.FormQuestion, error) {
+	questions := make([]models.FormQuestion, 0, len(requested))
+	for i, question := range requested {
+		key := question.Key
+		if strings.TrimSpace(key) == "" {
+			key = question.Label
+		}
+		questions = append(questions, models.FormQuestion{
+			Key:            services.NormalizeStageKey(key),
+			Label:          strings.TrimSpace(question.Label),
+			HelpText:       strings.TrimSpace(question.HelpText),
+			Type:           strings.ToLower(strings.TrimSpace(question.Type)),
+			Required:       question.Required,
+			Options:        encodeStringList(question.Options),
+			MinValue:       question.MinValue,
+			MaxValue:       question.MaxValue,
+			MaxLength:      question.MaxLength,
+			Position:       i,
+			KnockoutAction: strings.ToLower(strings.TrimSpace(question.KnockoutAction)),
+			KnockoutValues: encodeStringList(question.KnockoutValues),
+			KnockoutBelow:  question.KnockoutBelow,
+			KnockoutAbove:  question.KnockoutAbove,
+		})
+	}
+	if err := services.ValidateFormQuestions(questions); err != nil {
+		return nil, err
+	}
+	return questions, nil
+}
+
+// publicFormQuestions returns the questions as candidates see them, without the knockout rules
+func publicFormQuestions(form *models.ApplicationForm) []gin.H {
+	questions := []gin.H{}
+	if form == nil {
+		return questions
+	}
+	for _, question := range form.Questions {
+		view := gin.H{
+			"key":       question.Key,
+			"label":     question.Label,
+			"help_text": question.HelpText,
+			"type":      question.Type,
+			"required":  question.Required,
+		}
+		if options := services.FormQuestionOptions(question); len(options) > 0 {
+			view["options"] = options
+		}
+		if question.MinValue != nil {
+			view["min_value"] = *question.MinValue
+		}
+		if question.MaxValue != nil {
+			view["max_value"] = *question.MaxValue
+		}
+		if question.MaxLength > 0 {
+			view["max_length"] = question.MaxLength
+		}
+		questions = append(questions, view)
+	}
+	return questions
+}
+
+// GetJobApplicationForm returns the application form of a job, including its knockout rules
+func GetJobApplicationForm(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+
+	form, err := services.GetJobApplicationForm(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load application form"})
+		return
+	}
+	if form == nil {
+		c.JSON(http.StatusOK, gin.H{"form": nil, "questions": []models.FormQuestion{}})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"form": form, "questions": form.Questions})
+}
+
+// SetJobApplicationForm creates or replaces the application form of a job.
+// Existing answers keep the question key and label they were given for.
+func SetJobApplicationForm(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req ApplicationFormRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+
+	questions, err := buildFormQuestions(req.Questions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var form models.ApplicationForm
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		form, err = services.SaveApplicationForm(tx, job, questions)
+		return err
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save application form"})
+		return
+	}
+	form.Questions = questions
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Application form saved successfully",
+		"form":      form,
+		"questions": questions,
+	})
+}
+
+// GetPublicApplicationForm returns the questions candidates answer when applying to an open job (public endpoint)
+func GetPublicApplicationForm(c *gin.Context) {
+	var job models.Job
+	if err := config.DB.Where("id = ? AND status = ?", c.Param("jobId"), "open").First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	form, err := services.GetJobApplicationForm(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load application form"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":    job.ID,
+		"questions": publicFormQuestions(form),
+	})
+}
+
+// GetApplicationAnswers returns a candidate's screening answers
+func GetApplicationAnswers(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var application models.Application
+	// Verify application belongs to company (even if job is deleted)
+	err = config.DB.Table("applications").
+		Select("applications.id, applications.job_id, applications.screening_result").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", c.Param("id"), companyID, companyID).
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	var answers []models.ApplicationAnswer
+	if err := config.DB.Where("application_id = ?", application.ID).Order("created_at ASC").Find(&answers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch answers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"answers":          answers,
+		"screening_result": application.ScreeningResult,
+	})
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -95,6 +96,11 @@ func Register(c *gin.Context) {
 		company.EmbedDomain = &req.EmbedDomain
 	}
 
+	// Careers page URL slug, based on the company name
+	if slug, err := services.UniqueCompanySlug(req.CompanyName, uuid.Nil); err == nil {
+		company.Slug = &slug
+	}
+
 	if err := config.DB.Create(&company).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create company"})
 		return
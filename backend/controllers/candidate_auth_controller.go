@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"ats-backend/services"
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CandidateMagicLinkRequest for a candidate asking for a sign-in link
+type CandidateMagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+// CandidateSessionRequest for exchanging a sign-in link's token for a portal session
+type CandidateSessionRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// candidateEmail returns the email of the signed-in candidate, set by CandidateAuthMiddleware
+func candidateEmail(c *gin.Context) string {
+	return services.NormalizeCandidateEmail(c.GetString("candidate_email"))
+}
+
+// RequestCandidateMagicLink emails a candidate a link to sign in to the portal (public endpoint).
+// The response is the same whether or not the email has applied anywhere.
+func RequestCandidateMagicLink(c *gin.Context) {
+	var req CandidateMagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := services.RequestCandidateMagicLink(req.Email, c.ClientIP()); err != nil {
+		log.Printf("ERROR: Failed to create candidate sign-in link: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send sign-in link. Please try again."})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "If you have applied with this email, we've sent you a link to sign in. It expires in 15 minutes.",
+	})
+}
+
+// CreateCandidateSession exchanges the token of a sign-in link for a portal session (public endpoint).
+// Each link works once.
+func CreateCandidateSession(c *gin.Context) {
+	var req CandidateSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := services.ExchangeCandidateMagicLink(req.Token)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidMagicLink) {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			return
+		}
+		log.Printf("ERROR: Failed to start candidate session: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to sign in. Please try again."})
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
@@ -3,19 +3,24 @@ package controllers
 import (
 	"ats-backend/config"
 	"ats-backend/models"
+	"ats-backend/services"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 // CheckApplicationStatusRequest for candidate portal
 type CheckApplicationStatusRequest struct {
-	Email         string `json:"email" binding:"required,email"`
 	ApplicationID string `json:"application_id" binding:"required"`
 }
 
-// GetApplicationStatus returns application status for candidates (public endpoint)
+// GetApplicationStatus returns the status of one of the signed-in candidate's applications
 func GetApplicationStatus(c *gin.Context) {
 	var req CheckApplicationStatusRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -24,14 +29,12 @@ func GetApplicationStatus(c *gin.Context) {
 	}
 
 	var application models.Application
-	err := config.DB.Where("id = ? AND email = ?", req.ApplicationID, req.Email).
+	err := config.DB.Where("id = ? AND LOWER(email) = ?", req.ApplicationID, candidateEmail(c)).
 		Preload("Job").
 		First(&application).Error
 
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{
-			"error": "Application not found. Please check your email and application ID.",
-		})
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
 
@@ -42,31 +45,53 @@ func GetApplicationStatus(c *gin.Context) {
 		Count(&unreadCount)
 
 	// Get status timeline/history
-	statusHistory := []gin.H{
-		{
-			"status":    "pending",
-			"label":     "Application Submitted",
-			"timestamp": application.AppliedAt,
-			"completed": application.Status != "pending",
-		},
+	statusHistory := buildCandidateStatusHistory(application)
+
+	// Interviews and open self-scheduling links
+	var interviews []models.Interview
+	config.DB.Where("application_id = ? AND status <> ?", application.ID, models.InterviewStatusCancelled).
+		Order("start_time ASC").
+		Find(&interviews)
+	interviewViews := []gin.H{}
+	for _, interview := range interviews {
+		interviewViews = append(interviewViews, candidateInterviewView(interview))
 	}
 
-	if application.CVViewedAt != nil {
-		statusHistory = append(statusHistory, gin.H{
-			"status":    "cv_viewed",
-			"label":     "CV Viewed",
-			"timestamp": application.CVViewedAt,
-			"completed": true,
+	var links []models.SchedulingLink
+	config.DB.Where("application_id = ? AND status = ? AND expires_at > ?", application.ID, models.SchedulingLinkOpen, time.Now()).
+		Order("created_at DESC").
+		Find(&links)
+	schedulingLinks := []gin.H{}
+	for _, link := range links {
+		schedulingLinks = append(schedulingLinks, gin.H{
+			"round_name": link.RoundName,
+			"url":        services.SchedulingLinkURL(link.Token),
+			"expires_at": link.ExpiresAt,
 		})
 	}
 
-	if application.Status == "shortlisted" || application.Status == "rejected" {
-		statusHistory = append(statusHistory, gin.H{
-			"status":    application.Status,
-			"label":     getStatusLabel(application.Status),
-			"timestamp": application.ReviewedAt,
-			"completed": true,
-		})
+	// Offers waiting for (or given) an answer
+	var offers []models.Offer
+	config.DB.Where("application_id = ? AND status IN ?", application.ID,
+		[]string{models.OfferStatusSent, models.OfferStatusAccepted, models.OfferStatusDeclined, models.OfferStatusExpired}).
+		Order("sent_at DESC").
+		Find(&offers)
+	offerViews := []gin.H{}
+	for _, offer := range offers {
+		view := gin.H{
+			"status":     offer.Status,
+			"expires_at": offer.ExpiresAt,
+		}
+		if offer.Token != nil && services.IsOfferOpen(offer) {
+			view["url"] = services.OfferURL(*offer.Token)
+		}
+		offerViews = append(offerViews, view)
+	}
+
+	// Open applications can be withdrawn by the candidate
+	canWithdraw := false
+	if stages, err := services.GetPipelineStages(application.CompanyID, application.JobID); err == nil {
+		canWithdraw = !services.IsClosedStatus(stages, application.Status)
 	}
 
 	// Calculate expected response date
@@ -106,7 +131,17 @@ func GetApplicationStatus(c *gin.Context) {
 			"score":                 application.Score,
 			"unread_messages":       unreadCount,
 			"status_history":        statusHistory,
+			"interviews":            interviewViews,
+			"scheduling_links":      schedulingLinks,
+			"offers":                offerViews,
 			"can_message":           true, // Candidates can always message
+			"can_withdraw":          canWithdraw,
+			"consent": gin.H{
+				"privacy_notice_version": application.PrivacyNoticeVersion,
+				"consented_at":           application.ConsentedAt,
+				"talent_pool":            application.TalentPoolConsent,
+				"marketing":              application.MarketingConsent,
+			},
 			"job": gin.H{
 				"id":    application.Job.ID,
 				"title": application.Job.Title,
@@ -115,16 +150,114 @@ func GetApplicationStatus(c *gin.Context) {
 	})
 }
 
-// GetApplicationStatusByEmail returns all applications for an email (public endpoint)
-func GetApplicationStatusByEmail(c *gin.Context) {
-	email := c.Query("email")
-	if email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Email is required"})
+// UpdateConsentRequest for a candidate changing their opt-ins
+type UpdateConsentRequest struct {
+	ApplicationID string `json:"application_id" binding:"required"`
+	TalentPool    *bool  `json:"talent_pool"` // Omitted to leave unchanged
+	Marketing     *bool  `json:"marketing"`   // Omitted to leave unchanged
+}
+
+// UpdateConsent lets the signed-in candidate give or withdraw their talent pool and marketing opt-ins.
+// The change applies to all their applications to the company.
+func UpdateConsent(c *gin.Context) {
+	var req UpdateConsentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.TalentPool == nil && req.Marketing == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update"})
+		return
+	}
+
+	var application models.Application
+	if err := config.DB.Where("id = ? AND LOWER(email) = ?", req.ApplicationID, candidateEmail(c)).First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+
+	updated, err := services.UpdateCandidateConsent(application.CompanyID, application.Email, req.TalentPool, req.Marketing, c.ClientIP())
+	if err != nil {
+		log.Printf("ERROR: Failed to update consent of application %s: %v", application.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update your preferences"})
+		return
+	}
+
+	services.LogCandidateConsentUpdated(application.CompanyID, application.ID, application.FullName, req.TalentPool, req.Marketing, c.ClientIP())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":              "Your preferences have been updated",
+		"updated_applications": updated,
+	})
+}
+
+// WithdrawApplicationRequest for a candidate withdrawing their application
+type WithdrawApplicationRequest struct {
+	ApplicationID string `json:"application_id" binding:"required"`
+	Reason        string `json:"reason"` // Optional, shared with the recruiter
+}
+
+// WithdrawApplication lets the signed-in candidate withdraw one of their applications
+func WithdrawApplication(c *gin.Context) {
+	var req WithdrawApplicationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var application models.Application
+	err := config.DB.Where("id = ? AND LOWER(email) = ?", req.ApplicationID, candidateEmail(c)).
+		Preload("Job").
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
 
+	reason := strings.TrimSpace(req.Reason)
+	if runes := []rune(reason); len(runes) > 1000 {
+		reason = string(runes[:1000])
+	}
+
+	if err := services.WithdrawApplication(&application, reason); err != nil {
+		if errors.Is(err, services.ErrApplicationClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "This application is already closed and can't be withdrawn."})
+			return
+		}
+		log.Printf("ERROR: Failed to withdraw application %s: %v", application.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw your application. Please try again."})
+		return
+	}
+
+	jobTitle := "Unknown Job (Job Deleted)"
+	if application.JobID != nil && application.Job.ID != uuid.Nil {
+		jobTitle = application.Job.Title
+	}
+	services.LogApplicationWithdrawn(application.CompanyID, application.ID, application.FullName, jobTitle, reason)
+
+	// Let the recruiters know
+	message := fmt.Sprintf("%s withdrew their application for %s", application.FullName, jobTitle)
+	if reason != "" {
+		message += ": " + reason
+	}
+	for _, adminID := range services.ApplicationRecruiters(application) {
+		if err := services.CreateNotification(application.CompanyID, adminID, "application_withdrawn",
+			"Application withdrawn", message, "application", &application.ID); err != nil {
+			log.Printf("ERROR: Failed to notify admin %s about withdrawal: %v", adminID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Your application has been withdrawn. Thank you for your interest.",
+		"status":       application.Status,
+		"status_label": getStatusLabel(application.Status),
+	})
+}
+
+// GetCandidateApplications lists the signed-in candidate's applications at every company
+func GetCandidateApplications(c *gin.Context) {
 	var applications []models.Application
-	err := config.DB.Where("email = ?", email).
+	err := config.DB.Where("LOWER(email) = ?", candidateEmail(c)).
 		Preload("Job").
 		Order("applied_at DESC").
 		Find(&applications).Error
@@ -138,11 +271,12 @@ func GetApplicationStatusByEmail(c *gin.Context) {
 	results := []gin.H{}
 	for _, app := range applications {
 		results = append(results, gin.H{
-			"id":         app.ID,
-			"full_name":  app.FullName,
-			"status":     app.Status,
-			"applied_at": app.AppliedAt,
-			"reviewed_at": app.ReviewedAt,
+			"id":           app.ID,
+			"full_name":    app.FullName,
+			"status":       app.Status,
+			"status_label": getStatusLabel(app.Status),
+			"applied_at":   app.AppliedAt,
+			"reviewed_at":  app.ReviewedAt,
 			"job": gin.H{
 				"id":    app.Job.ID,
 				"title": app.Job.Title,
@@ -152,10 +286,66 @@ func GetApplicationStatusByEmail(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{
 		"applications": results,
-		"count":       len(results),
+		"count":        len(results),
 	})
 }
 
+// buildCandidateStatusHistory returns the stage history shown to candidates.
+// Applications from before history was recorded get it reconstructed from their timestamps.
+func buildCandidateStatusHistory(application models.Application) []gin.H {
+	history, err := services.GetApplicationStatusHistory(application.ID)
+	if err != nil || len(history) == 0 {
+		return legacyCandidateStatusHistory(application)
+	}
+
+	stages, _ := services.GetPipelineStages(application.CompanyID, application.JobID)
+	statusHistory := []gin.H{}
+	for i, change := range history {
+		label := getStatusLabel(change.ToStage)
+		if stage, found := services.FindPipelineStage(stages, change.ToStage); found {
+			label = stage.Name
+		}
+		statusHistory = append(statusHistory, gin.H{
+			"status":    change.ToStage,
+			"label":     label,
+			"timestamp": change.CreatedAt,
+			"completed": i < len(history)-1 || application.Status != change.ToStage,
+		})
+	}
+	return statusHistory
+}
+
+// legacyCandidateStatusHistory reconstructs a status history from the application timestamps
+func legacyCandidateStatusHistory(application models.Application) []gin.H {
+	statusHistory := []gin.H{
+		{
+			"status":    "pending",
+			"label":     "Application Submitted",
+			"timestamp": application.AppliedAt,
+			"completed": application.Status != "pending",
+		},
+	}
+
+	if application.CVViewedAt != nil {
+		statusHistory = append(statusHistory, gin.H{
+			"status":    "cv_viewed",
+			"label":     "CV Viewed",
+			"timestamp": application.CVViewedAt,
+			"completed": true,
+		})
+	}
+
+	if application.Status == "shortlisted" || application.Status == "rejected" {
+		statusHistory = append(statusHistory, gin.H{
+			"status":    application.Status,
+			"label":     getStatusLabel(application.Status),
+			"timestamp": application.ReviewedAt,
+			"completed": true,
+		})
+	}
+	return statusHistory
+}
+
 // getStatusLabel returns a human-readable label for status
 func getStatusLabel(status string) string {
 	labels := map[string]string{
@@ -166,6 +356,7 @@ func getStatusLabel(status string) string {
 		"rejected":           "Not Selected",
 		"interview_scheduled": "Interview Scheduled",
 		"decision_pending":   "Decision Pending",
+		"withdrawn":          "Withdrawn",
 	}
 	if label, ok := labels[status]; ok {
 		return label
@@ -4,110 +4,145 @@ import (
 	"ats-backend/config"
 	"ats-backend/models"
 	"ats-backend/services"
+	"ats-backend/utils"
+	"errors"
 	"fmt"
+	"log"
 	"net/http"
-	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // SearchCandidatesRequest defines the search criteria
 type SearchCandidatesRequest struct {
-	Query            string   `json:"query"`             // General text search
-	Skills           []string `json:"skills"`            // Required skills
-	MinExperience    *int     `json:"min_experience"`    // Minimum years of experience
-	MaxExperience    *int     `json:"max_experience"`    // Maximum years of experience
-	CurrentPosition  string   `json:"current_position"`  // Current position keyword
-	Languages        []string `json:"languages"`         // Required languages
-	HasPortfolio     *bool    `json:"has_portfolio"`     // Has portfolio URL
-	HasLinkedIn      *bool    `json:"has_linkedin"`      // Has LinkedIn URL
-	Status           string   `json:"status"`            // Application status filter
-	Limit            int      `json:"limit"`             // Results limit
+	services.CandidateSearchCriteria
+	Limit     int    `json:"limit"`      // Page size (max 100)
+	Cursor    string `json:"cursor"`     // Opaque cursor from a previous page's next_cursor
+	SortBy    string `json:"sort_by"`    // relevance (default), applied_at, score, experience
+	SortOrder string `json:"sort_order"` // desc (default) or asc
 }
 
 // CandidateSearchResult represents a search result
 type CandidateSearchResult struct {
-	Application    models.Application `json:"application"`
-	MatchScore     int                `json:"match_score"`     // 0-100
-	MatchedSkills  []string           `json:"matched_skills"`  // Skills found
-	MatchedReasons []string           `json:"matched_reasons"` // Why it matched
+	Application    models.Application  `json:"application"`
+	MatchScore     int                 `json:"match_score"`             // 0-100
+	MatchedSkills  []string            `json:"matched_skills"`          // Skills found
+	MatchedReasons []string            `json:"matched_reasons"`         // Why it matched
+	FuzzyMatches   []services.FuzzyHit `json:"fuzzy_matches,omitempty"` // Approximate matches, flagged for review
 }
 
-// SearchCandidates searches through all CVs in the database
-func SearchCandidates(c *gin.Context) {
-	companyIDVal, exists := c.Get("company_id")
-	fmt.Println("companyIDVal", companyIDVal)
-	if !exists {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
-		return
-	}
+// searchCursor marks the last result of a page: its sort key and application ID
+type searchCursor struct {
+	Key int64  `json:"k"`
+	ID  string `json:"id"`
+}
 
-	companyIDStr, ok := companyIDVal.(string)
-	if !ok || companyIDStr == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
-		return
-	}
+// validSearchSorts lists the supported sort_by values
+var validSearchSorts = map[string]bool{
+	"relevance":  true,
+	"applied_at": true,
+	"score":      true,
+	"experience": true,
+}
 
-	companyID, err := uuid.Parse(companyIDStr)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID format"})
-		return
+// searchSortKey returns the value a result is ordered by for the given sort
+func searchSortKey(result CandidateSearchResult, sortBy string) int64 {
+	switch sortBy {
+	case "applied_at":
+		return result.Application.AppliedAt.UnixMilli()
+	case "score":
+		return int64(result.Application.Score)
+	case "experience":
+		return int64(result.Application.YearsOfExperience)
+	default:
+		return int64(result.MatchScore)
 	}
+}
 
-	var req SearchCandidatesRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
+// searchResultBefore orders results by sort key, breaking ties by application ID so pages are stable
+func searchResultBefore(keyA int64, idA string, keyB int64, idB string, descending bool) bool {
+	if keyA != keyB {
+		if descending {
+			return keyA > keyB
+		}
+		return keyA < keyB
 	}
+	return idA < idB
+}
 
-	// Set default limit
-	if req.Limit <= 0 || req.Limit > 100 {
-		req.Limit = 50
+// normalizeSearchSort defaults and validates the sorting of a search request and returns its sort order
+func normalizeSearchSort(req *SearchCandidatesRequest) (string, error) {
+	req.SortBy = strings.ToLower(strings.TrimSpace(req.SortBy))
+	if req.SortBy == "" {
+		req.SortBy = "relevance"
+	}
+	if !validSearchSorts[req.SortBy] {
+		return "", errors.New("Invalid sort_by. Must be: relevance, applied_at, score, or experience")
+	}
+	if strings.ToLower(req.SortOrder) == "asc" {
+		return "asc", nil
 	}
+	return "desc", nil
+}
+
+// candidateSearchRun is the full, sorted result of a search, before pagination
+type candidateSearchRun struct {
+	Results        []CandidateSearchResult
+	Facets         services.CandidateFacets
+	ActiveJobApps  int // Applications of active jobs searched
+	DeletedJobApps int // Applications of deleted jobs searched
+	Filtered       int // Applications left after the structured filters
+}
 
+// runCandidateSearch filters and matches the company's applications within the admin's job scope against
+// the search criteria and sorts the matches. Shared by the search endpoint and the search export.
+func runCandidateSearch(companyID uuid.UUID, scope services.JobScope, req SearchCandidatesRequest, descending bool) (*candidateSearchRun, error) {
 	// Get all applications for this company, including those with deleted jobs (job_id IS NULL)
 	// This allows Find Candidates to search through ALL applications, even if their jobs were deleted
 	var applications []models.Application
-	
+
 	// Query 1: Applications with active jobs (uses index on jobs.company_id and applications.job_id)
 	// This should always work regardless of company_id column existence
+	// Screening answers are only needed to filter on them
+	withAnswers := func(db *gorm.DB) *gorm.DB {
+		if len(req.Answers) > 0 {
+			return db.Preload("Answers")
+		}
+		return db
+	}
+
 	var activeJobApps []models.Application
-	err1 := config.DB.Table("applications").
+	err1 := scope.Apply(config.DB.Table("applications"), "applications.job_id").
 		Select("applications.*").
 		Joins("INNER JOIN jobs ON jobs.id = applications.job_id").
-		Where("jobs.company_id = ?", companyID).
+		Where("jobs.company_id = ? AND jobs.deleted_at IS NULL", companyID).
 		Preload("Job").
+		Scopes(withAnswers).
 		Find(&activeJobApps).Error
-		
-	// Debug: Check if ParsedCVText is loaded
-	if len(activeJobApps) > 0 {
-		fmt.Printf("DEBUG: First active app - Has ParsedCVText: %v, Length: %d\n",
-			activeJobApps[0].ParsedCVText != nil,
-			func() int {
-				if activeJobApps[0].ParsedCVText != nil {
-					return len(*activeJobApps[0].ParsedCVText)
-				}
-				return 0
-			}())
-	}
-	
+
 	if err1 != nil {
 		fmt.Printf("ERROR: Failed to fetch active job applications: %v\n", err1)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch candidates", "details": err1.Error()})
-		return
+		return nil, err1
 	}
-	
+
 	// Query 2: Applications with deleted jobs (job_id IS NULL)
 	// Try with company_id first (if column exists and is populated)
+	// Only company-wide admins see them, as they belong to no hiring team
 	var deletedJobApps []models.Application
-	err2 := config.DB.Table("applications").
-		Select("applications.*").
-		Where("applications.job_id IS NULL AND applications.company_id = ?", companyID).
-		Preload("Job").
-		Find(&deletedJobApps).Error
-	
+	var err2 error
+	if !scope.Restricted {
+		err2 = config.DB.Table("applications").
+			Select("applications.*").
+			Where("applications.job_id IS NULL AND applications.company_id = ?", companyID).
+			Preload("Job").
+			Scopes(withAnswers).
+			Find(&deletedJobApps).Error
+	}
+
 	// If query fails, it might mean:
 	// 1. company_id column doesn't exist yet (migration not run)
 	// 2. company_id is NULL for those applications
@@ -118,81 +153,38 @@ func SearchCandidates(c *gin.Context) {
 		// Continue with just active job applications
 		deletedJobApps = []models.Application{}
 	}
-	
+
 	// Combine results
 	applications = append(activeJobApps, deletedJobApps...)
-	
+
 	// Debug logging
-	fmt.Printf("DEBUG: Found %d total applications (%d active jobs, %d deleted jobs) for company %s\n", 
+	fmt.Printf("DEBUG: Found %d total applications (%d active jobs, %d deleted jobs) for company %s\n",
 		len(applications), len(activeJobApps), len(deletedJobApps), companyID.String())
-	fmt.Printf("DEBUG: Search request - Query: '%s', Skills: %v, MinExp: %v, Languages: %v\n",
-		req.Query, req.Skills, req.MinExperience, req.Languages)
+	fmt.Printf("DEBUG: Search request - Query: '%s', Skills: %v, MinExp: %v, Languages: %v, Sort: %s\n",
+		req.Query, req.Skills, req.MinExperience, req.Languages, req.SortBy)
 
 	// Apply filters to the combined results (in-memory filtering for better performance)
 	filteredApplications := []models.Application{}
 	for _, app := range applications {
-		fmt.Printf("DEBUG: Processing application %s - Email: %s, Has CV Text: %v\n",
-			app.ID.String(), app.Email, app.ParsedCVText != nil && *app.ParsedCVText != "")
-		// Status filter
-		if req.Status != "" && app.Status != req.Status {
-			continue
-		}
-		
-		// Portfolio filter
-		if req.HasPortfolio != nil && *req.HasPortfolio {
-			if app.PortfolioURL == "" {
-				continue
-			}
+		if services.PassesCandidateFilters(app, req.CandidateSearchCriteria) {
+			filteredApplications = append(filteredApplications, app)
 		}
-		
-		// LinkedIn filter
-		if req.HasLinkedIn != nil && *req.HasLinkedIn {
-			if app.LinkedinURL == "" {
-				continue
-			}
-		}
-		
-		// Experience filters
-		if req.MinExperience != nil && app.YearsOfExperience < *req.MinExperience {
-			continue
-		}
-		if req.MaxExperience != nil && app.YearsOfExperience > *req.MaxExperience {
-			continue
-		}
-		
-		// Current position filter
-		if req.CurrentPosition != "" {
-			if !strings.Contains(strings.ToLower(app.CurrentPosition), strings.ToLower(req.CurrentPosition)) {
-				continue
-			}
-		}
-		
-		filteredApplications = append(filteredApplications, app)
 	}
-	
+
 	applications = filteredApplications
-	
+
 	fmt.Printf("DEBUG: After filtering, %d applications remain\n", len(applications))
 
 	// Search through CVs
 	results := []CandidateSearchResult{}
+	matchedApps := []models.Application{}
+	cvTexts := map[string]string{}
 
 	for _, app := range applications {
-		fmt.Printf("DEBUG: Analyzing application %s - CV Text Length: %d\n",
-			app.Email, func() int {
-				if app.ParsedCVText != nil {
-					return len(*app.ParsedCVText)
-				}
-				return 0
-			}())
-		matchScore := 0
-		matchedSkills := []string{}
-
 		// Get CV text (from parsed_cv_text or extract from URL)
 		cvText := ""
 		if app.ParsedCVText != nil && *app.ParsedCVText != "" {
 			cvText = *app.ParsedCVText
-			fmt.Printf("DEBUG: Using parsed CV text for %s (%d chars)\n", app.Email, len(cvText))
 		} else if app.ResumeURL != "" {
 			// Extract text from URL if not parsed yet
 			fmt.Printf("DEBUG: No parsed CV text, extracting from URL for %s\n", app.Email)
@@ -201,177 +193,160 @@ func SearchCandidates(c *gin.Context) {
 				cvText = extractedText
 				// Store parsed text for future searches
 				app.ParsedCVText = &extractedText
-				config.DB.Model(&app).Update("parsed_cv_text", extractedText)
-				fmt.Printf("DEBUG: Extracted and stored CV text for %s (%d chars)\n", app.Email, len(cvText))
+				services.SaveParsedCVText(app.ID, extractedText)
 			} else {
 				fmt.Printf("DEBUG: Failed to extract CV text for %s: %v\n", app.Email, err)
 			}
 		}
 
-		if cvText == "" {
-			// If no CV text but we have other search criteria, still include the candidate
-			// Only skip if we need CV text for the search
-			if req.Query != "" || len(req.Skills) > 0 || len(req.Languages) > 0 {
-				// Need CV text for these searches, skip this candidate
-				fmt.Printf("DEBUG: Skipping %s - no CV text but search requires it\n", app.Email)
-				continue
-			}
-			// Otherwise, continue with empty CV text (will match based on other fields)
-			fmt.Printf("DEBUG: Continuing with %s - no CV text but search doesn't require it\n", app.Email)
+		// Need CV text for text, skill and language searches - skip candidates without it
+		if cvText == "" && req.NeedsCVText() {
+			continue
 		}
 
-		cvLower := strings.ToLower(cvText)
-		reasons := []string{}
-
-		// 1. General text query search
-		hasQueryMatch := false
-		if req.Query != "" {
-			queryLower := strings.ToLower(strings.TrimSpace(req.Query))
-			queryWords := strings.Fields(queryLower)
-			matchedWords := 0
-
-			for _, word := range queryWords {
-				// Match words longer than 2 characters (more reliable)
-				// Use word boundaries for better matching
-				if len(word) > 2 {
-					// Check for exact word match or as part of a larger word
-					wordPattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`)
-					if wordPattern.MatchString(cvLower) {
-						matchedWords++
-					} else if strings.Contains(cvLower, word) {
-						// Fallback: partial match
-						matchedWords++
-					}
-				}
-			}
-
-			if matchedWords > 0 {
-				queryScore := (matchedWords * 100) / len(queryWords)
-				// Include if at least 1 word matches
-				matchScore += queryScore
-				hasQueryMatch = true
-				reasons = append(reasons, fmt.Sprintf("Matched %d/%d search terms", matchedWords, len(queryWords)))
-			}
-		} else {
-			// No query means we'll match based on other criteria
-			hasQueryMatch = true
+		match, ok := services.MatchCandidate(app, cvText, req.CandidateSearchCriteria)
+		if !ok {
+			continue
 		}
 
-		// 2. Skills search
-		if len(req.Skills) > 0 {
-			foundSkills := services.ExtractSkills(cvText, req.Skills)
-			matchedSkills = foundSkills
-			fmt.Printf("DEBUG: Skills search for %s - Required: %v, Found: %v\n", app.Email, req.Skills, foundSkills)
-			if len(foundSkills) > 0 {
-				skillsScore := (len(foundSkills) * 100) / len(req.Skills)
-				matchScore += skillsScore
-				reasons = append(reasons, fmt.Sprintf("Found %d/%d required skills: %s", len(foundSkills), len(req.Skills), strings.Join(foundSkills, ", ")))
-			} else {
-				fmt.Printf("DEBUG: No skills matched for %s\n", app.Email)
-			}
-		}
+		results = append(results, CandidateSearchResult{
+			Application:    app,
+			MatchScore:     match.MatchScore,
+			MatchedSkills:  match.MatchedSkills,
+			MatchedReasons: match.MatchedReasons,
+			FuzzyMatches:   match.FuzzyMatches,
+		})
+		matchedApps = append(matchedApps, app)
+		cvTexts[app.ID.String()] = cvText
+	}
 
-		// 3. Experience search
-		if req.MinExperience != nil {
-			if app.YearsOfExperience >= *req.MinExperience {
-				matchScore += 20
-				reasons = append(reasons, fmt.Sprintf("Has %d years of experience (required: %d+)", app.YearsOfExperience, *req.MinExperience))
-			}
-		}
+	// Facets describe the whole matching set, not just the current page
+	facets := services.BuildCandidateFacets(matchedApps, cvTexts)
 
-		// 4. Languages search
-		if len(req.Languages) > 0 {
-			foundLanguages := services.ExtractLanguages(cvText, req.Languages)
-			if len(foundLanguages) > 0 {
-				langScore := (len(foundLanguages) * 100) / len(req.Languages)
-				matchScore += langScore / 5 // 20% weight
-				reasons = append(reasons, fmt.Sprintf("Found languages: %s", strings.Join(foundLanguages, ", ")))
-			}
-		}
+	// Sort by the requested field, ties broken by application ID
+	sort.SliceStable(results, func(i, j int) bool {
+		return searchResultBefore(
+			searchSortKey(results[i], req.SortBy), results[i].Application.ID.String(),
+			searchSortKey(results[j], req.SortBy), results[j].Application.ID.String(),
+			descending,
+		)
+	})
 
-		// 5. Current position search
-		if req.CurrentPosition != "" {
-			if strings.Contains(strings.ToLower(app.CurrentPosition), strings.ToLower(req.CurrentPosition)) {
-				matchScore += 15
-				reasons = append(reasons, fmt.Sprintf("Current position matches: %s", app.CurrentPosition))
-			}
-		}
+	return &candidateSearchRun{
+		Results:        results,
+		Facets:         facets,
+		ActiveJobApps:  len(activeJobApps),
+		DeletedJobApps: len(deletedJobApps),
+		Filtered:       len(applications),
+	}, nil
+}
 
-		// Only include if there's a match
-		// If query was provided, it must match. Otherwise, check other criteria
-		hasOtherCriteria := len(req.Skills) > 0 || req.MinExperience != nil || req.MaxExperience != nil ||
-			req.CurrentPosition != "" || len(req.Languages) > 0 || req.HasPortfolio != nil || req.HasLinkedIn != nil || req.Status != ""
-		
-		// Include candidate if:
-		// 1. Query matches (or no query provided) AND
-		// 2. Either no other criteria OR at least one criterion matches
-		shouldInclude := false
-		
-		if hasQueryMatch {
-			if hasOtherCriteria {
-				// Have other criteria - need at least some match
-				if matchScore > 0 {
-					shouldInclude = true
-				}
-			} else {
-				// No other criteria - include if query matched (or no query)
-				shouldInclude = true
-			}
-		}
-		
-		if shouldInclude {
-			// Set default score if no criteria matched
-			if matchScore == 0 && req.Query == "" && !hasOtherCriteria {
-				matchScore = 50 // Default score if no criteria at all
-			}
-			
-			// Normalize score to 0-100
-			if matchScore > 100 {
-				matchScore = 100
-			}
-			
-			fmt.Printf("DEBUG: Including candidate %s with score %d, reasons: %v\n", app.Email, matchScore, reasons)
-			results = append(results, CandidateSearchResult{
-				Application:    app,
-				MatchScore:     matchScore,
-				MatchedSkills:  matchedSkills,
-				MatchedReasons: reasons,
-			})
-		} else {
-			fmt.Printf("DEBUG: Excluding candidate %s - hasQueryMatch: %v, hasOtherCriteria: %v, matchScore: %d\n",
-				app.Email, hasQueryMatch, hasOtherCriteria, matchScore)
+// SearchCandidates searches through all CVs in the database
+func SearchCandidates(c *gin.Context) {
+	companyIDVal, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	companyIDStr, ok := companyIDVal.(string)
+	if !ok || companyIDStr == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+
+	companyID, err := uuid.Parse(companyIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID format"})
+		return
+	}
+
+	var req SearchCandidatesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Set default limit
+	if req.Limit <= 0 || req.Limit > 100 {
+		req.Limit = 50
+	}
+
+	// Validate sorting
+	sortOrder, err := normalizeSearchSort(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	descending := sortOrder == "desc"
+
+	var cursor *searchCursor
+	if req.Cursor != "" {
+		cursor = &searchCursor{}
+		if err := utils.DecodeCursor(req.Cursor, cursor); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cursor"})
+			return
 		}
 	}
 
-	// Sort by match score (descending)
-	for i := 0; i < len(results)-1; i++ {
-		for j := i + 1; j < len(results); j++ {
-			if results[i].MatchScore < results[j].MatchScore {
-				results[i], results[j] = results[j], results[i]
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+	run, err := runCandidateSearch(companyID, scope, req, descending)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch candidates", "details": err.Error()})
+		return
+	}
+	results := run.Results
+
+	// Skip everything up to and including the cursor position
+	start := 0
+	if cursor != nil {
+		start = len(results)
+		for i, result := range results {
+			if searchResultBefore(cursor.Key, cursor.ID, searchSortKey(result, req.SortBy), result.Application.ID.String(), descending) {
+				start = i
+				break
 			}
 		}
 	}
 
-	// Limit results
-	if len(results) > req.Limit {
-		results = results[:req.Limit]
+	end := start + req.Limit
+	if end > len(results) {
+		end = len(results)
+	}
+	page := results[start:end]
+	hasMore := end < len(results)
+
+	nextCursor := ""
+	if hasMore && len(page) > 0 {
+		last := page[len(page)-1]
+		nextCursor, _ = utils.EncodeCursor(searchCursor{
+			Key: searchSortKey(last, req.SortBy),
+			ID:  last.Application.ID.String(),
+		})
 	}
 
 	// Debug logging
-	fmt.Printf("DEBUG: Search completed. Found %d matching candidates out of %d total applications\n", 
-		len(results), len(applications))
-	fmt.Printf("DEBUG: Active job apps: %d, Deleted job apps: %d\n", len(activeJobApps), len(deletedJobApps))
+	fmt.Printf("DEBUG: Search completed. Found %d matching candidates out of %d total applications (page of %d)\n",
+		len(results), run.Filtered, len(page))
 
 	c.JSON(http.StatusOK, gin.H{
-		"candidates": results,
-		"count":      len(results),
-		"total":      len(applications),
+		"candidates":  page,
+		"count":       len(page),
+		"total":       len(results),
+		"has_more":    hasMore,
+		"next_cursor": nextCursor,
+		"sort_by":     req.SortBy,
+		"sort_order":  sortOrder,
+		"facets":      run.Facets,
 		"debug": gin.H{
-			"active_job_apps": len(activeJobApps),
-			"deleted_job_apps": len(deletedJobApps),
-			"total_applications_before_filter": len(activeJobApps) + len(deletedJobApps),
-			"total_applications_after_filter": len(applications),
-			"matching_candidates": len(results),
+			"active_job_apps":                  run.ActiveJobApps,
+			"deleted_job_apps":                 run.DeletedJobApps,
+			"total_applications_before_filter": run.ActiveJobApps + run.DeletedJobApps,
+			"total_applications_after_filter":  run.Filtered,
+			"matching_candidates":              len(results),
 		},
 	})
 }
@@ -399,14 +374,16 @@ func GetCandidateDetails(c *gin.Context) {
 		Joins("INNER JOIN jobs ON jobs.id = applications.job_id").
 		Where("applications.id = ? AND jobs.company_id = ?", candidateID, companyIDStr).
 		Preload("Job").
+		Preload("Answers").
 		First(&application).Error
-	
+
 	// If not found, try deleted job applications
 	if err != nil {
 		err = config.DB.Table("applications").
 			Select("applications.*").
 			Where("applications.id = ? AND applications.job_id IS NULL AND applications.company_id = ?", candidateID, companyIDStr).
 			Preload("Job").
+			Preload("Answers").
 			First(&application).Error
 	}
 
@@ -414,6 +391,14 @@ func GetCandidateDetails(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Candidate not found"})
 		return
 	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+	if !scope.Allows(application.JobID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Candidate not found"})
+		return
+	}
 
 	// Get CV text if available
 	cvText := ""
@@ -426,7 +411,7 @@ func GetCandidateDetails(c *gin.Context) {
 			cvText = extractedText
 			// Store for future
 			application.ParsedCVText = &extractedText
-			config.DB.Model(&application).Update("parsed_cv_text", extractedText)
+			services.SaveParsedCVText(application.ID, extractedText)
 		}
 	}
 
@@ -438,11 +423,20 @@ func GetCandidateDetails(c *gin.Context) {
 		experience = services.ExtractExperience(cvText)
 	}
 
+	// Interview feedback, hidden from interviewers who still owe their own scorecard
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	scorecards, err := services.GetApplicationScorecards(application.ID, adminUUID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load scorecards for application %s: %v", application.ID, err)
+	}
+
 	c.JSON(http.StatusOK, gin.H{
-		"candidate": application,
-		"cv_text":   cvText,
-		"skills":    skills,
+		"candidate":  application,
+		"cv_text":    cvText,
+		"skills":     skills,
 		"experience": experience,
+		"scorecards": scorecards,
 	})
 }
-
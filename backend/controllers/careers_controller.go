@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// careersCacheControl lets browsers and CDNs reuse careers responses for 5 minutes
+const careersCacheControl = "public, max-age=300"
+
+// CareersSiteRequest updates a company's careers slug and branding. Omitted fields are left unchanged.
+type CareersSiteRequest struct {
+	Slug         *string `json:"slug"`
+	LogoURL      *string `json:"logo_url"`
+	PrimaryColor *string `json:"primary_color"` // #rrggbb, or empty to use the default theme
+	AccentColor  *string `json:"accent_color"`
+	AboutText    *string `json:"about_text" binding:"omitempty,max=10000"`
+}
+
+// publicJob is a job as shown on the careers page, without internal screening settings
+type publicJob struct {
+	ID           uuid.UUID       `json:"id"`
+	Slug         string          `json:"slug"`
+	Title        string          `json:"title"`
+	Description  string          `json:"description"`
+	Requirements string          `json:"requirements"`
+	Location     string          `json:"location"`
+	JobType      string          `json:"job_type"`
+	Department   string          `json:"department"`
+	SalaryRange  string          `json:"salary_range"`
+	Deadline     models.DateOnly `json:"deadline"`
+	CreatedAt    time.Time       `json:"created_at"`
+	ApplyURL     string          `json:"apply_url"`
+}
+
+func newPublicJob(job models.Job, source string) publicJob {
+	public := publicJob{
+		ID:           job.ID,
+		Title:        job.Title,
+		Description:  job.Description,
+		Requirements: job.Requirements,
+		Location:     job.Location,
+		JobType:      job.JobType,
+		Department:   job.Department,
+		SalaryRange:  job.SalaryRange,
+		Deadline:     job.Deadline,
+		CreatedAt:    job.CreatedAt,
+		ApplyURL:     services.JobApplyURL(job.ID.String(), source),
+	}
+	if job.Slug != nil {
+		public.Slug = *job.Slug
+	}
+	return public
+}
+
+// renderCacheableJSON answers with an ETag of the body, or 304 Not Modified when the client already has it
+func renderCacheableJSON(c *gin.Context, payload interface{}) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build response"})
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	c.Header("Cache-Control", careersCacheControl)
+	c.Header("ETag", etag)
+	for _, candidate := range strings.Split(c.GetHeader("If-None-Match"), ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// renderCareersJobs lists a company's open jobs with its branding.
+// Query parameters: q, location, job_type, department, remote, page and limit (default 50, max 100).
+func renderCareersJobs(c *gin.Context, companyRef string) {
+	company, err := services.FindCareersCompany(companyRef)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "page must be a positive number"})
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive number"})
+		return
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	filters := services.CareersJobFilters{
+		Keyword:    c.Query("q"),
+		Location:   c.Query("location"),
+		JobType:    c.Query("job_type"),
+		Department: c.Query("department"),
+		Remote:     c.Query("remote") == "true",
+	}
+	jobs, total, err := services.SearchPublicJobs(company.ID.String(), filters, (page-1)*limit, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+	facets, err := services.PublicJobFacets(company.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return
+	}
+
+	source := services.TrackingSource(c.Query("source"))
+	if source == "" {
+		source = "careers"
+	}
+	publicJobs := make([]publicJob, 0, len(jobs))
+	for _, job := range jobs {
+		publicJobs = append(publicJobs, newPublicJob(job, source))
+	}
+
+	renderCacheableJSON(c, gin.H{
+		"company":  services.CompanyBranding(company),
+		"jobs":     publicJobs,
+		"total":    total,
+		"page":     page,
+		"limit":    limit,
+		"has_more": int64(page*limit) < total,
+		"filters":  facets,
+	})
+}
+
+// GetCareersJobs returns a company's careers page by slug or ID: branding, open jobs and filter values (public endpoint)
+func GetCareersJobs(c *gin.Context) {
+	renderCareersJobs(c, c.Param("companySlug"))
+}
+
+// GetCareersJob returns one open job by slug or ID, with the company branding and its JobPosting JSON-LD (public endpoint)
+func GetCareersJob(c *gin.Context) {
+	company, err := services.FindCareersCompany(c.Param("companySlug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+	job, err := services.FindPublicJob(company.ID, c.Param("jobSlug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	source := services.TrackingSource(c.Query("source"))
+	if source == "" {
+		source = "careers"
+	}
+	renderCacheableJSON(c, gin.H{
+		"company": services.CompanyBranding(company),
+		"job":     newPublicJob(job, source),
+		"json_ld": services.JobPostingJSONLD(job, company, source),
+	})
+}
+
+// GetCareersPrivacyNotice returns the company's current privacy notice, which candidates accept when applying
+func GetCareersPrivacyNotice(c *gin.Context) {
+	company, err := services.FindCareersCompany(c.Param("companySlug"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+	notice, err := services.CurrentPrivacyNotice(company.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load privacy notice"})
+		return
+	}
+	if notice == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This company hasn't published a privacy notice"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"version":      notice.Version,
+		"title":        notice.Title,
+		"content":      notice.Content,
+		"published_at": notice.CreatedAt,
+	})
+}
+
+// GetCareersSite returns the company's careers slug, branding and public URLs
+func GetCareersSite(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, careersSiteResponse(company))
+}
+
+// UpdateCareersSite changes the company's careers slug and branding
+func UpdateCareersSite(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req CareersSiteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	updates := map[string]interface{}{}
+	if req.Slug != nil {
+		slug := strings.ToLower(strings.TrimSpace(*req.Slug))
+		if !services.IsValidSlug(slug) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid slug: use lowercase letters, digits and dashes (max 80 characters)"})
+			return
+		}
+		if _, err := uuid.Parse(slug); err == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Slug can't be an ID"})
+			return
+		}
+		taken, err := services.IsCompanySlugTaken(slug, company.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check slug"})
+			return
+		}
+		if taken {
+			c.JSON(http.StatusConflict, gin.H{"error": "This slug is already used by another company"})
+			return
+		}
+		updates["slug"] = slug
+	}
+	if req.LogoURL != nil {
+		logoURL := strings.TrimSpace(*req.LogoURL)
+		if logoURL != "" && !strings.HasPrefix(logoURL, "https://") && !strings.HasPrefix(logoURL, "http://") {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "logo_url must be an http(s) URL"})
+			return
+		}
+		if len(logoURL) > 500 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "logo_url must be at most 500 characters"})
+			return
+		}
+		updates["logo_url"] = logoURL
+	}
+	for column, colour := range map[string]*string{"primary_color": req.PrimaryColor, "accent_color": req.AccentColor} {
+		if colour == nil {
+			continue
+		}
+		value := strings.ToLower(strings.TrimSpace(*colour))
+		if value != "" && !services.IsValidHexColour(value) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": column + " must be a hex colour like #1d4ed8"})
+			return
+		}
+		updates[column] = value
+	}
+	if req.AboutText != nil {
+		updates["about_text"] = strings.TrimSpace(*req.AboutText)
+	}
+	if len(updates) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Nothing to update"})
+		return
+	}
+	updates["updated_at"] = time.Now()
+
+	if err := config.DB.Model(&company).Updates(updates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update careers site"})
+		return
+	}
+	if err := config.DB.First(&company, "id = ?", company.ID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load careers site"})
+		return
+	}
+
+	changed := make([]string, 0, len(updates))
+	for column := range updates {
+		if column != "updated_at" {
+			changed = append(changed, column)
+		}
+	}
+	sort.Strings(changed)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogCareersSiteUpdated(company.ID, adminUUID, changed)
+
+	response := careersSiteResponse(company)
+	response["message"] = "Careers site updated successfully"
+	c.JSON(http.StatusOK, response)
+}
+
+// careersSiteResponse returns a company's branding with the URLs of its careers page and feeds
+func careersSiteResponse(company models.Company) gin.H {
+	branding := services.CompanyBranding(company)
+	ref := branding.Slug
+	if ref == "" {
+		ref = company.ID.String()
+	}
+	return gin.H{
+		"careers_site": branding,
+		"careers_url":  config.GetEnv("FRONTEND_URL", "http://localhost:3000") + "/jobs/" + ref,
+		"api_url":      "/api/careers/" + ref,
+	}
+}
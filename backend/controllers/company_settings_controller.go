@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ReapplicationPolicyRequest for updating a company's reapplication rules
+type ReapplicationPolicyRequest struct {
+	CooldownDays *int `json:"cooldown_days" binding:"required,min=0,max=730"` // 0 = may reapply as soon as an application closes
+}
+
+// GetReapplicationPolicy returns the company's reapplication rules
+func GetReapplicationPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id, reapply_cooldown_days").First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"cooldown_days": company.ReapplyCooldownDays,
+	})
+}
+
+// UpdateReapplicationPolicy sets how many days a candidate must wait after an application closes
+// before applying to the same job again. Open applications always block a second one.
+func UpdateReapplicationPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req ReapplicationPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id, reapply_cooldown_days").First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+	oldDays := company.ReapplyCooldownDays
+
+	if err := config.DB.Model(&company).Updates(map[string]interface{}{
+		"reapply_cooldown_days": *req.CooldownDays,
+		"updated_at":            time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update reapplication policy"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogReapplicationPolicyUpdated(company.ID, adminUUID, oldDays, *req.CooldownDays)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "Reapplication policy updated successfully",
+		"cooldown_days": *req.CooldownDays,
+	})
+}
+
+// JobClosePolicyRequest for updating what happens to pending applicants when a job closes automatically
+type JobClosePolicyRequest struct {
+	ApplicantPolicy string `json:"applicant_policy" binding:"required"` // none or notify
+}
+
+// GetJobClosePolicy returns what happens to pending applicants when a job closes automatically
+func GetJobClosePolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id, job_closed_applicant_policy").First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	policy := company.JobClosedApplicantPolicy
+	if policy == "" {
+		policy = services.JobClosedApplicantsNone
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"applicant_policy": policy,
+	})
+}
+
+// UpdateJobClosePolicy sets whether applicants still in the process are emailed when a job
+// closes at its deadline or because its headcount is filled
+func UpdateJobClosePolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req JobClosePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !services.IsValidJobClosedApplicantPolicy(req.ApplicantPolicy) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid applicant_policy. Must be: none or notify"})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id, job_closed_applicant_policy").First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+	oldPolicy := company.JobClosedApplicantPolicy
+
+	if err := config.DB.Model(&company).Updates(map[string]interface{}{
+		"job_closed_applicant_policy": req.ApplicantPolicy,
+		"updated_at":                  time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job close policy"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogJobClosePolicyUpdated(company.ID, adminUUID, oldPolicy, req.ApplicantPolicy)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Job close policy updated successfully",
+		"applicant_policy": req.ApplicantPolicy,
+	})
+}
+
+// RequisitionPolicyRequest for updating whether new jobs need approval and who approves them by default
+type RequisitionPolicyRequest struct {
+	RequireApproval *bool    `json:"require_approval" binding:"required"`
+	ApproverIDs     []string `json:"approver_ids"` // Default approval chain, in order
+}
+
+// GetRequisitionPolicy returns whether new jobs need approval and the default approval chain
+func GetRequisitionPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id, require_job_approval, requisition_approver_ids").First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"require_approval": company.RequireJobApproval,
+		"approver_ids":     services.CompanyRequisitionApproverIDs(company),
+	})
+}
+
+// UpdateRequisitionPolicy sets whether new jobs start as requisitions that must be approved before
+// they are published, and the approval chain used when a requisition doesn't name its own approvers
+func UpdateRequisitionPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req RequisitionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	approvers, err := services.LoadRequisitionApprovers(companyID, req.ApproverIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id").First(&company, "id = ?", companyID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	approverIDs := []string{}
+	for _, approver := range approvers {
+		approverIDs = append(approverIDs, approver.ID.String())
+	}
+	var chain *string
+	if encoded := encodeStringList(approverIDs); encoded != "" {
+		chain = &encoded
+	}
+
+	if err := config.DB.Model(&company).Updates(map[string]interface{}{
+		"require_job_approval":     *req.RequireApproval,
+		"requisition_approver_ids": chain,
+		"updated_at":               time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update requisition policy"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogRequisitionPolicyUpdated(company.ID, adminUUID, *req.RequireApproval, approverIDs)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":          "Requisition policy updated successfully",
+		"require_approval": *req.RequireApproval,
+		"approver_ids":     approverIDs,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"ats-backend/config"
 	"ats-backend/models"
 	"ats-backend/services"
+	"fmt"
 	"net/http"
 	"time"
 
@@ -37,6 +38,17 @@ type UpdateReferralRequest struct {
 	ReferredByPhone string `json:"referred_by_phone"`
 }
 
+// UpdateTagsRequest replaces the tags of an application
+type UpdateTagsRequest struct {
+	Tags []string `json:"tags"`
+}
+
+// applicationInJobScope reports whether the current admin may see an application of their company,
+// responding with not found when they may not
+func applicationInJobScope(c *gin.Context, application models.Application) bool {
+	return inJobScope(c, application.JobID, "Application not found")
+}
+
 // AddCandidateNote adds a note to a candidate's application
 func AddCandidateNote(c *gin.Context) {
 	companyIDVal, exists := c.Get("company_id")
@@ -68,6 +80,9 @@ func AddCandidateNote(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Get admin ID
 	adminIDVal, _ := c.Get("admin_id")
@@ -124,6 +139,9 @@ func GetCandidateNotes(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Get admin ID to filter private notes
 	adminIDVal, _ := c.Get("admin_id")
@@ -243,6 +261,18 @@ func AddToTalentPool(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	// Keeping candidates for future jobs needs their consent
+	if !application.TalentPoolConsent {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "No talent pool consent",
+			"message": "This candidate hasn't agreed to be kept in the talent pool",
+		})
+		return
+	}
 
 	// Get admin ID
 	adminIDVal, _ := c.Get("admin_id")
@@ -272,6 +302,8 @@ func AddToTalentPool(c *gin.Context) {
 		map[string]interface{}{
 			"candidate_name": application.FullName,
 			"candidate_email": application.Email,
+			"privacy_notice_version": application.PrivacyNoticeVersion,
+			"consented_at": application.ConsentedAt,
 		},
 	)
 
@@ -307,6 +339,9 @@ func RemoveFromTalentPool(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	application.InTalentPool = false
 	application.TalentPoolAddedAt = nil
@@ -336,8 +371,14 @@ func GetTalentPool(c *gin.Context) {
 		return
 	}
 
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
 	var applications []models.Application
-	err := config.DB.Where("company_id = ? AND in_talent_pool = true", companyID).
+	err := scope.Apply(config.DB, "job_id").
+		Where("company_id = ? AND in_talent_pool = true", companyID).
 		Preload("Job").
 		Order("talent_pool_added_at DESC").
 		Find(&applications).Error
@@ -385,6 +426,9 @@ func UpdateReferralInfo(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Update referral info
 	application.ReferralSource = req.ReferralSource
@@ -403,6 +447,94 @@ func UpdateReferralInfo(c *gin.Context) {
 	})
 }
 
+// UpdateApplicationTags replaces the tags of an application.
+// Tags are stored lowercase, without duplicates, so they can be filtered on in the applications listing.
+func UpdateApplicationTags(c *gin.Context) {
+	applicationID := c.Param("id")
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req UpdateTagsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	tags := services.NormalizeTags(req.Tags)
+	for _, tag := range tags {
+		if len(tag) > services.MaxTagLength {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Tags can be at most %d characters", services.MaxTagLength)})
+			return
+		}
+	}
+	if len(tags) > 20 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "An application can have at most 20 tags"})
+		return
+	}
+
+	// Verify application belongs to company
+	var application models.Application
+	err = config.DB.Table("applications").
+		Select("applications.id, applications.job_id").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", applicationID, companyID, companyID).
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	if err := config.DB.Model(&models.Application{}).Where("id = ?", application.ID).
+		Update("tags", encodeStringList(tags)).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Tags updated",
+		"tags":    tags,
+	})
+}
+
+// GetApplicationTags lists the tags used across the company's applications, most used first
+func GetApplicationTags(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	type tagCount struct {
+		Tag   string `json:"tag"`
+		Count int64  `json:"count"`
+	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	var tags []tagCount
+	err = scope.Apply(config.DB.Table("applications"), "applications.job_id").
+		Select("tag, COUNT(*) AS count").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(COALESCE(applications.tags, '[]'::jsonb)) AS tag").
+		Where("applications.company_id = ? AND applications.deleted_at IS NULL", companyID).
+		Group("tag").
+		Order("count DESC, tag ASC").
+		Scan(&tags).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch tags"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"tags": tags})
+}
+
 // GetRelationshipTimeline retrieves all interactions with a candidate
 func GetRelationshipTimeline(c *gin.Context) {
 	applicationID := c.Param("id")
@@ -429,6 +561,9 @@ func GetRelationshipTimeline(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	timeline := []gin.H{}
 
@@ -452,8 +587,35 @@ func GetRelationshipTimeline(c *gin.Context) {
 		})
 	}
 
-	// Status changes
-	if application.ReviewedAt != nil {
+	// Status changes (from the recorded history; older applications only know their last change)
+	history, _ := services.GetApplicationStatusHistory(application.ID)
+	for _, change := range history {
+		if change.FromStage == "" {
+			continue // Covered by "Application Submitted"
+		}
+		actorName := "System"
+		switch {
+		case change.Actor != nil:
+			actorName = change.Actor.Name
+		case change.ActorType == models.StatusActorCandidate:
+			actorName = "Candidate"
+		}
+		description := "Application status changed from " + change.FromStage + " to " + change.ToStage
+		if change.Reason != "" {
+			description += ": " + change.Reason
+		}
+		timeline = append(timeline, gin.H{
+			"type":        "status_changed",
+			"title":       "Status: " + change.ToStage,
+			"description": description,
+			"timestamp":   change.CreatedAt,
+			"icon":        getStatusIcon(change.ToStage),
+			"admin":       actorName,
+			"from_status": change.FromStage,
+			"to_status":   change.ToStage,
+		})
+	}
+	if len(history) == 0 && application.ReviewedAt != nil {
 		timeline = append(timeline, gin.H{
 			"type":        "status_changed",
 			"title":       "Status: " + application.Status,
@@ -474,6 +636,41 @@ func GetRelationshipTimeline(c *gin.Context) {
 		})
 	}
 
+	// Interview scorecards (ratings stay hidden until the viewer has submitted their own)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	if scorecards, err := services.GetApplicationScorecards(application.ID, adminUUID); err == nil {
+		if scorecards.Hidden {
+			if scorecards.SubmittedCount > 0 {
+				timeline = append(timeline, gin.H{
+					"type":        "scorecards_hidden",
+					"title":       "Interview Feedback",
+					"description": fmt.Sprintf("%d scorecard(s) submitted. Submit your own scorecard to see them.", scorecards.SubmittedCount),
+					"timestamp":   time.Now(),
+					"icon":        "🔒",
+				})
+			}
+		} else {
+			for _, scorecard := range scorecards.Scorecards {
+				description := "Recommendation: " + scorecard.Recommendation
+				if scorecard.OverallComment != "" {
+					description += ". " + scorecard.OverallComment
+				}
+				timeline = append(timeline, gin.H{
+					"type":           "scorecard",
+					"title":          "Scorecard Submitted",
+					"description":    description,
+					"timestamp":      scorecard.SubmittedAt,
+					"icon":           "📊",
+					"admin":          scorecard.Admin.Name,
+					"recommendation": scorecard.Recommendation,
+					"ratings":        scorecard.Ratings,
+				})
+			}
+		}
+	}
+
 	// Get notes
 	var notes []models.CandidateNote
 	config.DB.Where("application_id = ?", applicationID).
@@ -549,6 +746,8 @@ func getStatusIcon(status string) string {
 		return "✅"
 	case "rejected":
 		return "❌"
+	case "withdrawn":
+		return "↩️"
 	case "cv_viewed":
 		return "👁️"
 	default:
@@ -77,9 +77,7 @@ func ReparseAllCVs(c *gin.Context) {
 			}
 			
 			// Update parsed_cv_text
-			updateErr := config.DB.Model(&models.Application{}).
-				Where("id = ?", app.ID).
-				Update("parsed_cv_text", cvText).Error
+			updateErr := services.SaveParsedCVText(app.ID, cvText)
 
 			if updateErr != nil {
 				log.Printf("ERROR: Failed to save parsed CV text for %s: %v", app.Email, updateErr)
@@ -126,6 +124,9 @@ func ReparseSingleCV(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	if application.ResumeURL == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Application has no resume URL"})
@@ -153,9 +154,7 @@ func ReparseSingleCV(c *gin.Context) {
 	}
 
 	// Update parsed_cv_text
-	updateErr := config.DB.Model(&models.Application{}).
-		Where("id = ?", application.ID).
-		Update("parsed_cv_text", cvText).Error
+	updateErr := services.SaveParsedCVText(application.ID, cvText)
 
 	if updateErr != nil {
 		log.Printf("ERROR: Failed to save parsed CV text: %v", updateErr)
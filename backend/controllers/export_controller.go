@@ -0,0 +1,328 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"bytes"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExportSearchRequest exports the candidates matching a search, with the same criteria as SearchCandidates
+type ExportSearchRequest struct {
+	SearchCandidatesRequest
+	Format  string   `json:"format"`  // csv (default) or xlsx
+	Columns []string `json:"columns"` // Column keys, see GET /exports/columns
+}
+
+// exportRequester returns the company and admin an export is made for
+func exportRequester(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	companyIDStr, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	companyID, err := uuid.Parse(companyIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminID, err := uuid.Parse(adminIDStr)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Admin ID not found in token"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return companyID, adminID, true
+}
+
+// resolveExportOptions validates the requested format and columns
+func resolveExportOptions(c *gin.Context, format string, keys []string) (string, []services.ExportColumn, bool) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = services.ExportFormatCSV
+	}
+	if !services.IsValidExportFormat(format) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid format. Must be: csv or xlsx"})
+		return "", nil, false
+	}
+	columns, err := services.ResolveExportColumns(keys)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "available_columns": services.ExportColumnKeys()})
+		return "", nil, false
+	}
+	return format, columns, true
+}
+
+// sendExport returns small exports straight away and queues a background job for large ones.
+// estimatedRows decides which; load fetches the rows in either case.
+func sendExport(c *gin.Context, companyID, adminID uuid.UUID, source, format string, columns []services.ExportColumn,
+	estimatedRows int64, load func() ([]services.ExportRow, error)) {
+	columnKeys := make([]string, len(columns))
+	for i, column := range columns {
+		columnKeys[i] = column.Key
+	}
+
+	if estimatedRows <= services.ExportInlineRowLimit {
+		rows, err := load()
+		if err != nil {
+			log.Printf("ERROR: Failed to load %s export: %v", source, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export"})
+			return
+		}
+		var buffer bytes.Buffer
+		if err := services.WriteExport(&buffer, format, columns, rows); err != nil {
+			log.Printf("ERROR: Failed to write %s export: %v", source, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to export"})
+			return
+		}
+
+		services.LogDataExported(companyID, adminID, source, format, len(rows), columnKeys, nil)
+
+		c.Header("Content-Disposition", "attachment; filename="+services.ExportFilename(source, format))
+		c.Data(http.StatusOK, services.ExportContentType(format), buffer.Bytes())
+		return
+	}
+
+	columnsJSON, _ := json.Marshal(columnKeys)
+	job := models.ExportJob{
+		CompanyID: companyID,
+		AdminID:   adminID,
+		Source:    source,
+		Format:    format,
+		Columns:   string(columnsJSON),
+		Status:    models.ExportStatusPending,
+		Filename:  services.ExportFilename(source, format),
+	}
+	if err := config.DB.Create(&job).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to start export"})
+		return
+	}
+
+	services.LogDataExported(companyID, adminID, source, format, int(estimatedRows), columnKeys, &job.ID)
+	go services.RunExportJob(job, columns, load)
+
+	c.JSON(http.StatusAccepted, gin.H{
+		"message":      "Export started. You'll be notified when it's ready to download.",
+		"export":       job,
+		"status_url":   "/api/exports/" + job.ID.String(),
+		"download_url": "/api/exports/" + job.ID.String() + "/download",
+	})
+}
+
+// ExportApplications exports the applications listing with the same filters and sorting as GetApplications.
+// Query parameters: format (csv or xlsx), columns (comma separated keys) and any GetApplications filter.
+func ExportApplications(c *gin.Context) {
+	companyID, adminID, ok := exportRequester(c)
+	if !ok {
+		return
+	}
+
+	filters, err := parseApplicationListFilters(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if filters.Scope, ok = loadJobScope(c); !ok {
+		return
+	}
+	format, columns, ok := resolveExportOptions(c, c.Query("format"), splitQueryList(c.Query("columns")))
+	if !ok {
+		return
+	}
+
+	var total int64
+	if err := applicationListQuery(companyID.String(), filters).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count applications"})
+		return
+	}
+
+	load := func() ([]services.ExportRow, error) {
+		var applications []models.Application
+		err := applicationListQuery(companyID.String(), filters).
+			Select(applicationListSelect(false, true)).
+			Preload("Job", preloadListJob).
+			Order(applicationListOrder(filters)).
+			Find(&applications).Error
+		if err != nil {
+			return nil, err
+		}
+		return services.BuildExportRows(applications, nil), nil
+	}
+
+	sendExport(c, companyID, adminID, "applications", format, columns, total, load)
+}
+
+// ExportSearchResults exports every candidate matching a search, in the search's sort order
+func ExportSearchResults(c *gin.Context) {
+	companyID, adminID, ok := exportRequester(c)
+	if !ok {
+		return
+	}
+
+	var req ExportSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sortOrder, err := normalizeSearchSort(&req.SearchCandidatesRequest)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format, columns, ok := resolveExportOptions(c, req.Format, req.Columns)
+	if !ok {
+		return
+	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	// Searching reads every CV of the company, so the pool size decides whether it runs in the background
+	var poolSize int64
+	if err := config.DB.Table("applications").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.company_id = ? OR jobs.company_id = ?", companyID, companyID).
+		Where("applications.deleted_at IS NULL").
+		Count(&poolSize).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to count candidates"})
+		return
+	}
+
+	search := req.SearchCandidatesRequest
+	load := func() ([]services.ExportRow, error) {
+		run, err := runCandidateSearch(companyID, scope, search, sortOrder == "desc")
+		if err != nil {
+			return nil, err
+		}
+		applications := make([]models.Application, 0, len(run.Results))
+		matchScores := map[uuid.UUID]int{}
+		for _, result := range run.Results {
+			applications = append(applications, result.Application)
+			matchScores[result.Application.ID] = result.MatchScore
+		}
+		return services.BuildExportRows(applications, matchScores), nil
+	}
+
+	sendExport(c, companyID, adminID, "candidates", format, columns, poolSize, load)
+}
+
+// GetExportColumns lists the columns an export can include
+func GetExportColumns(c *gin.Context) {
+	columns := []gin.H{}
+	for _, key := range services.ExportColumnKeys() {
+		resolved, _ := services.ResolveExportColumns([]string{key})
+		columns = append(columns, gin.H{"key": key, "header": resolved[0].Header})
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"columns":         columns,
+		"default_columns": services.DefaultExportColumns,
+	})
+}
+
+// visibleExports selects the company's exports the current admin may see. Admins limited
+// to some jobs only see their own, as an export can hold any job's candidates.
+// It responds itself when the admin's scope can't be loaded.
+func visibleExports(c *gin.Context, companyID string) (*gorm.DB, bool) {
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return nil, false
+	}
+	query := config.DB.Where("company_id = ?", companyID)
+	if scope.Restricted {
+		query = query.Where("admin_id = ?", currentAdminID(c))
+	}
+	return query, true
+}
+
+// GetExports lists the company's background exports that are still available
+func GetExports(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	query, ok := visibleExports(c, companyID)
+	if !ok {
+		return
+	}
+
+	var exports []models.ExportJob
+	if err := query.Omit("data").
+		Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+		Order("created_at DESC").
+		Find(&exports).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch exports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"exports": exports})
+}
+
+// GetExport returns the status of a background export
+func GetExport(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	query, ok := visibleExports(c, companyID)
+	if !ok {
+		return
+	}
+
+	var export models.ExportJob
+	if err := query.Omit("data").Where("id = ?", c.Param("id")).First(&export).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+
+	response := gin.H{"export": export}
+	if export.Status == models.ExportStatusCompleted {
+		response["download_url"] = "/api/exports/" + export.ID.String() + "/download"
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// DownloadExport returns the file of a completed background export
+func DownloadExport(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	query, ok := visibleExports(c, companyID)
+	if !ok {
+		return
+	}
+
+	var export models.ExportJob
+	if err := query.Where("id = ?", c.Param("id")).First(&export).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Export not found"})
+		return
+	}
+	if export.Status != models.ExportStatusCompleted {
+		c.JSON(http.StatusConflict, gin.H{"error": "Export is not ready yet", "status": export.Status})
+		return
+	}
+	if export.ExpiresAt != nil && export.ExpiresAt.Before(time.Now()) {
+		c.JSON(http.StatusGone, gin.H{"error": "This export has expired. Please export again."})
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+export.Filename)
+	c.Data(http.StatusOK, services.ExportContentType(export.Format), export.Data)
+}
@@ -0,0 +1,290 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobMemberRequest for putting an admin on a job's hiring team
+type JobMemberRequest struct {
+	AdminID string `json:"admin_id" binding:"required"`
+	Role    string `json:"role" binding:"required"` // recruiter, hiring_manager, interviewer
+}
+
+// JobMemberRoleRequest for changing an admin's role on a hiring team
+type JobMemberRoleRequest struct {
+	Role string `json:"role" binding:"required"` // recruiter, hiring_manager, interviewer
+}
+
+// currentAdminID returns the ID of the admin making the request
+func currentAdminID(c *gin.Context) uuid.UUID {
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	return adminUUID
+}
+
+// loadJobScope returns the jobs the current admin may see. It responds itself when the scope can't be loaded.
+func loadJobScope(c *gin.Context) (services.JobScope, bool) {
+	scope, err := services.LoadJobScope(currentAdminID(c))
+	if err != nil {
+		log.Printf("ERROR: Failed to load job scope of admin %s: %v", currentAdminID(c), err)
+		c.JSON(http.StatusForbidden, gin.H{"error": "Failed to load your access"})
+		return scope, false
+	}
+	return scope, true
+}
+
+// requireCompanyWideAccess lets only admins who see every job go on, for company-wide
+// settings and candidate privacy. It responds itself when the admin may not.
+func requireCompanyWideAccess(c *gin.Context) bool {
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return false
+	}
+	if scope.Restricted {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only company-wide admins can do this"})
+		return false
+	}
+	return true
+}
+
+// findHiringTeamJob loads a job of the company the current admin may see. It responds itself when there is none.
+func findHiringTeamJob(c *gin.Context, companyID string) (*models.Job, services.JobScope, bool) {
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return nil, scope, false
+	}
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil || !scope.Allows(&job.ID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return nil, scope, false
+	}
+	return &job, scope, true
+}
+
+// inJobScope reports whether the current admin may see something of the given job, responding with
+// notFound when they may not, as if it didn't exist
+func inJobScope(c *gin.Context, jobID *uuid.UUID, notFound string) bool {
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return false
+	}
+	if !scope.Allows(jobID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return false
+	}
+	return true
+}
+
+// canManageHiringTeam reports whether the current admin may change who works on a job:
+// company-wide admins, and the job's recruiters and hiring managers
+func canManageHiringTeam(c *gin.Context, job models.Job, scope services.JobScope) bool {
+	if !scope.Restricted {
+		return true
+	}
+	var member models.JobMember
+	if err := config.DB.Where("job_id = ? AND admin_id = ?", job.ID, currentAdminID(c)).First(&member).Error; err != nil {
+		return false
+	}
+	return member.Role == models.JobMemberRecruiter || member.Role == models.JobMemberHiringManager
+}
+
+// GetJobMembers returns the hiring team of a job
+func GetJobMembers(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	job, _, ok := findHiringTeamJob(c, companyID)
+	if !ok {
+		return
+	}
+
+	var members []models.JobMember
+	if err := config.DB.Where("job_id = ?", job.ID).
+		Preload("Admin").
+		Order("created_at ASC").
+		Find(&members).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch hiring team"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"members": members})
+}
+
+// AddJobMember puts an admin of the company on a job's hiring team, or changes their role when they're on it already
+func AddJobMember(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req JobMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !services.IsValidJobMemberRole(req.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Must be: recruiter, hiring_manager or interviewer"})
+		return
+	}
+	if _, err := uuid.Parse(req.AdminID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid admin_id"})
+		return
+	}
+
+	job, scope, ok := findHiringTeamJob(c, companyID)
+	if !ok {
+		return
+	}
+	if !canManageHiringTeam(c, *job, scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only recruiters and hiring managers of this job can change its hiring team"})
+		return
+	}
+
+	var admin models.Admin
+	if err := config.DB.Where("id = ? AND company_id = ?", req.AdminID, companyID).First(&admin).Error; err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The admin must belong to your company"})
+		return
+	}
+
+	var member models.JobMember
+	err = config.DB.Where("job_id = ? AND admin_id = ?", job.ID, admin.ID).First(&member).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hiring team"})
+		return
+	}
+	oldRole := member.Role
+	if oldRole == req.Role {
+		member.Admin = admin
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Already on the hiring team",
+			"member":  member,
+		})
+		return
+	}
+
+	adminUUID := currentAdminID(c)
+	member.CompanyID = job.CompanyID
+	member.JobID = job.ID
+	member.AdminID = admin.ID
+	member.Role = req.Role
+	member.UpdatedAt = time.Now()
+	if oldRole == "" {
+		member.AddedBy = &adminUUID
+	}
+	if err := config.DB.Save(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hiring team"})
+		return
+	}
+	member.Admin = admin
+
+	services.LogJobMemberChanged(job.CompanyID, adminUUID, job.ID, job.Title, admin.ID, admin.Name, oldRole, member.Role)
+	if admin.ID != adminUUID {
+		services.NotifyJobMemberChange(*job, admin.ID, member.Role)
+	}
+
+	status := http.StatusOK
+	message := "Hiring team role updated"
+	if oldRole == "" {
+		status = http.StatusCreated
+		message = "Added to the hiring team"
+	}
+	c.JSON(status, gin.H{
+		"message": message,
+		"member":  member,
+	})
+}
+
+// UpdateJobMember changes an admin's role on a job's hiring team
+func UpdateJobMember(c *gin.Context) {
+	var req JobMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changeJobMember(c, req.Role)
+}
+
+// RemoveJobMember takes an admin off a job's hiring team
+func RemoveJobMember(c *gin.Context) {
+	changeJobMember(c, "")
+}
+
+// changeJobMember gives a member of a job's hiring team another role, or removes them when role is empty
+func changeJobMember(c *gin.Context, role string) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if role != "" && !services.IsValidJobMemberRole(role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid role. Must be: recruiter, hiring_manager or interviewer"})
+		return
+	}
+
+	job, scope, ok := findHiringTeamJob(c, companyID)
+	if !ok {
+		return
+	}
+	if !canManageHiringTeam(c, *job, scope) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only recruiters and hiring managers of this job can change its hiring team"})
+		return
+	}
+
+	var member models.JobMember
+	if err := config.DB.Where("job_id = ? AND admin_id = ?", job.ID, c.Param("adminId")).
+		Preload("Admin").
+		First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not on this job's hiring team"})
+		return
+	}
+	oldRole := member.Role
+	if oldRole == role {
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Hiring team role updated",
+			"member":  member,
+		})
+		return
+	}
+
+	if role == "" {
+		err = config.DB.Delete(&member).Error
+	} else {
+		err = config.DB.Model(&member).Updates(map[string]interface{}{
+			"role":       role,
+			"updated_at": time.Now(),
+		}).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update hiring team"})
+		return
+	}
+
+	adminUUID := currentAdminID(c)
+	services.LogJobMemberChanged(job.CompanyID, adminUUID, job.ID, job.Title, member.AdminID, member.Admin.Name, oldRole, role)
+	if member.AdminID != adminUUID {
+		services.NotifyJobMemberChange(*job, member.AdminID, role)
+	}
+
+	if role == "" {
+		c.JSON(http.StatusOK, gin.H{"message": "Removed from the hiring team"})
+		return
+	}
+	member.Role = role
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Hiring team role updated",
+		"member":  member,
+	})
+}
@@ -0,0 +1,639 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// InterviewDetailsRequest holds the editable details of an interview
+type InterviewDetailsRequest struct {
+	RoundName      string    `json:"round_name" binding:"required"`
+	InterviewerIDs []string  `json:"interviewer_ids" binding:"required,min=1"`
+	StartTime      time.Time `json:"start_time" binding:"required"` // RFC 3339
+	EndTime        time.Time `json:"end_time" binding:"required"`   // RFC 3339
+	TimeZone       string    `json:"time_zone"`                     // IANA name, default UTC
+	Location       string    `json:"location"`
+	VideoLink      string    `json:"video_link"`
+	Notes          string    `json:"notes"`
+}
+
+// ScheduleInterviewRequest for scheduling a new interview
+type ScheduleInterviewRequest struct {
+	ApplicationID string `json:"application_id" binding:"required"`
+	InterviewDetailsRequest
+}
+
+// UpdateInterviewRequest for rescheduling an interview or recording its outcome
+type UpdateInterviewRequest struct {
+	InterviewDetailsRequest
+	Status string `json:"status"` // scheduled (default), completed, no_show
+}
+
+// CheckInterviewConflictsRequest for checking interviewer availability before scheduling
+type CheckInterviewConflictsRequest struct {
+	InterviewerIDs     []string  `json:"interviewer_ids" binding:"required,min=1"`
+	StartTime          time.Time `json:"start_time" binding:"required"`
+	EndTime            time.Time `json:"end_time" binding:"required"`
+	ExcludeInterviewID string    `json:"exclude_interview_id"`
+}
+
+// validateInterviewTimes checks the time range and returns the normalized time zone
+func validateInterviewTimes(start, end time.Time, timeZone string) (string, error) {
+	if !end.After(start) {
+		return "", errors.New("end_time must be after start_time")
+	}
+	if end.Sub(start) > 12*time.Hour {
+		return "", errors.New("an interview can't be longer than 12 hours")
+	}
+	timeZone = strings.TrimSpace(timeZone)
+	if timeZone == "" {
+		return "UTC", nil
+	}
+	if _, err := time.LoadLocation(timeZone); err != nil {
+		return "", fmt.Errorf("unknown time_zone %q", timeZone)
+	}
+	return timeZone, nil
+}
+
+// loadCompanyInterviewers returns the admins with the given IDs, all of which must belong to the company
+func loadCompanyInterviewers(companyID string, ids []string) ([]models.Admin, error) {
+	unique := []string{}
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid interviewer id %q", id)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var admins []models.Admin
+	if err := config.DB.Where("id IN ? AND company_id = ?", unique, companyID).Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	if len(admins) != len(unique) {
+		return nil, errors.New("every interviewer must be an admin of your company")
+	}
+	return admins, nil
+}
+
+// interviewSlotsFor builds one booking per interviewer for the interview's time
+func interviewSlotsFor(interview models.Interview, interviewers []models.Admin) []models.InterviewSlot {
+	slots := []models.InterviewSlot{}
+	for _, admin := range interviewers {
+		slots = append(slots, models.InterviewSlot{
+			InterviewID: interview.ID,
+			AdminID:     admin.ID,
+			StartTime:   interview.StartTime,
+			EndTime:     interview.EndTime,
+			Status:      interview.Status,
+			CreatedAt:   time.Now(),
+		})
+	}
+	return slots
+}
+
+// adminIDsOf returns the IDs of the given admins
+func adminIDsOf(admins []models.Admin) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, admin := range admins {
+		ids = append(ids, admin.ID)
+	}
+	return ids
+}
+
+// requireOpenApplication refuses to schedule for an application that has left the process.
+// It responds itself when the application is closed.
+func requireOpenApplication(c *gin.Context, application models.Application) bool {
+	stages, err := services.GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline"})
+		return false
+	}
+	if services.IsClosedStatus(stages, application.Status) {
+		c.JSON(http.StatusConflict, gin.H{"error": "This application is closed, reopen it before scheduling interviews"})
+		return false
+	}
+	return true
+}
+
+// respondInterviewConflict answers with the clashing bookings when err is an InterviewConflictError
+func respondInterviewConflict(c *gin.Context, err error) bool {
+	var conflictErr *services.InterviewConflictError
+	if !errors.As(err, &conflictErr) {
+		return false
+	}
+	c.JSON(http.StatusConflict, gin.H{
+		"error":     "One or more interviewers are already booked at this time",
+		"conflicts": conflictErr.Conflicts,
+	})
+	return true
+}
+
+// findCompanyInterview loads an interview of the company with its interviewers
+func findCompanyInterview(interviewID, companyID string) (*models.Interview, error) {
+	var interview models.Interview
+	err := config.DB.Where("id = ? AND company_id = ?", interviewID, companyID).
+		Preload("Slots.Admin").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, full_name, email, phone, job_id, company_id, status")
+		}).
+		First(&interview).Error
+	if err != nil {
+		return nil, err
+	}
+	return &interview, nil
+}
+
+// ScheduleInterview schedules an interview for an application and sends calendar invitations
+func ScheduleInterview(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req ScheduleInterviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	timeZone, err := validateInterviewTimes(req.StartTime, req.EndTime, req.TimeZone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var application models.Application
+	// Verify application belongs to company (even if job is deleted)
+	err = config.DB.Table("applications").
+		Select("applications.*").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", req.ApplicationID, companyID, companyID).
+		Preload("Job").
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+	if !requireOpenApplication(c, application) {
+		return
+	}
+
+	interviewers, err := loadCompanyInterviewers(companyID, req.InterviewerIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	now := time.Now()
+	interview := models.Interview{
+		ID:            uuid.New(),
+		CompanyID:     companyUUID,
+		ApplicationID: application.ID,
+		JobID:         application.JobID,
+		RoundName:     strings.TrimSpace(req.RoundName),
+		StartTime:     req.StartTime.UTC(),
+		EndTime:       req.EndTime.UTC(),
+		TimeZone:      timeZone,
+		Location:      req.Location,
+		VideoLink:     req.VideoLink,
+		Notes:         req.Notes,
+		Status:        models.InterviewStatusScheduled,
+		CalendarUID:   services.NewInterviewCalendarUID(),
+		CreatedBy:     &adminUUID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	interview.Slots = interviewSlotsFor(interview, interviewers)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := services.LockInterviewerConflicts(tx, adminIDsOf(interviewers), interview.StartTime, interview.EndTime, nil); err != nil {
+			return err
+		}
+		return tx.Create(&interview).Error
+	})
+	if respondInterviewConflict(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to schedule interview"})
+		return
+	}
+
+	services.LogInterviewScheduled(companyUUID, adminUUID, interview.ID, application.FullName, interview.RoundName, interview.StartTime)
+	advanceToInterviewStage(&application, services.AdminActor(adminUUID), adminUUID, "Interview scheduled: "+interview.RoundName)
+
+	// Tell the other interviewers they've been booked
+	for _, admin := range interviewers {
+		if admin.ID == adminUUID {
+			continue
+		}
+		if err := services.CreateNotification(companyUUID, admin.ID, "interview_scheduled",
+			"New interview: "+interview.RoundName,
+			fmt.Sprintf("You're interviewing %s on %s", application.FullName, services.FormatInterviewTime(interview)),
+			"interview", &interview.ID); err != nil {
+			log.Printf("ERROR: Failed to notify interviewer %s: %v", admin.ID, err)
+		}
+	}
+
+	go services.SendInterviewInvitations(interview.ID, "scheduled")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Interview scheduled successfully",
+		"interview": interview,
+	})
+}
+
+// advanceToInterviewStage moves the application to the "interview_scheduled" stage when its pipeline has one,
+// and tells the candidate by SMS
+func advanceToInterviewStage(application *models.Application, actor services.StatusChangeActor, adminID uuid.UUID, reason string) {
+	oldStatus := application.Status
+	moved, err := services.AdvanceApplicationStage(application, "interview_scheduled", actor, reason)
+	if err != nil {
+		log.Printf("ERROR: Failed to move application %s to interview_scheduled: %v", application.ID, err)
+		return
+	}
+	if !moved {
+		return
+	}
+
+	jobTitle := "Unknown Job (Job Deleted)"
+	if application.JobID != nil && application.Job.ID != uuid.Nil {
+		jobTitle = application.Job.Title
+	}
+	services.LogApplicationStatusChanged(application.CompanyID, adminID, application.ID, application.FullName, jobTitle, oldStatus, application.Status)
+
+	if application.Phone != "" {
+		go func(phone, name string) {
+			if err := services.SendStatusUpdateSMS(phone, name, jobTitle, "interview_scheduled"); err != nil {
+				log.Printf("ERROR: Failed to send interview SMS to %s: %v", phone, err)
+			} else {
+				log.Printf("SUCCESS: Interview SMS sent to %s", phone)
+			}
+		}(application.Phone, application.FullName)
+	}
+}
+
+// GetInterviews lists interviews, filtered by application_id, interviewer_id, status, from and to (RFC 3339)
+func GetInterviews(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	query := scope.Apply(config.DB.Model(&models.Interview{}).Where("interviews.company_id = ?", companyID), "interviews.job_id")
+
+	if applicationID := c.Query("application_id"); applicationID != "" {
+		query = query.Where("interviews.application_id = ?", applicationID)
+	}
+	if interviewerID := c.Query("interviewer_id"); interviewerID != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM interview_slots WHERE interview_slots.interview_id = interviews.id AND interview_slots.admin_id = ?)", interviewerID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("interviews.status = ?", status)
+	}
+	if from := c.Query("from"); from != "" {
+		fromTime, err := time.Parse(time.RFC3339, from)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid from. Use RFC 3339, e.g. 2026-01-02T15:04:05Z"})
+			return
+		}
+		query = query.Where("interviews.end_time >= ?", fromTime)
+	}
+	if to := c.Query("to"); to != "" {
+		toTime, err := time.Parse(time.RFC3339, to)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid to. Use RFC 3339, e.g. 2026-01-02T15:04:05Z"})
+			return
+		}
+		query = query.Where("interviews.start_time <= ?", toTime)
+	}
+
+	var interviews []models.Interview
+	if err := query.
+		Preload("Slots.Admin").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, full_name, email, phone, job_id, company_id, status")
+		}).
+		Order("interviews.start_time ASC").
+		Find(&interviews).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch interviews"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"interviews": interviews,
+		"count":      len(interviews),
+	})
+}
+
+// GetInterview returns a single interview
+func GetInterview(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	interview, err := findCompanyInterview(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+	if !inJobScope(c, interview.JobID, "Interview not found") {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"interview": interview})
+}
+
+// UpdateInterview reschedules an interview, changes its interviewers or records its outcome.
+// Attendees get an updated invitation with the same calendar UID.
+func UpdateInterview(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	interview, err := findCompanyInterview(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+	if !inJobScope(c, interview.JobID, "Interview not found") {
+		return
+	}
+	if interview.Status == models.InterviewStatusCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cancelled interviews can't be changed. Schedule a new one instead."})
+		return
+	}
+
+	var req UpdateInterviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := strings.ToLower(strings.TrimSpace(req.Status))
+	switch status {
+	case "":
+		status = interview.Status
+	case models.InterviewStatusScheduled, models.InterviewStatusCompleted, models.InterviewStatusNoShow:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status. Must be: scheduled, completed, or no_show (cancel with DELETE)"})
+		return
+	}
+
+	timeZone, err := validateInterviewTimes(req.StartTime, req.EndTime, req.TimeZone)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interviewers, err := loadCompanyInterviewers(companyID, req.InterviewerIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Interviewers taken off the interview get a cancellation
+	kept := map[uuid.UUID]bool{}
+	for _, admin := range interviewers {
+		kept[admin.ID] = true
+	}
+	removed := []models.Admin{}
+	for _, slot := range interview.Slots {
+		if !kept[slot.AdminID] {
+			removed = append(removed, slot.Admin)
+		}
+	}
+
+	oldStart := interview.StartTime
+	calendarChanged := !req.StartTime.Equal(interview.StartTime) || !req.EndTime.Equal(interview.EndTime) ||
+		req.Location != interview.Location || req.VideoLink != interview.VideoLink ||
+		strings.TrimSpace(req.RoundName) != interview.RoundName || len(removed) > 0 || len(interviewers) != len(interview.Slots)
+	if calendarChanged && status == models.InterviewStatusScheduled && !requireOpenApplication(c, interview.Application) {
+		return
+	}
+
+	interview.RoundName = strings.TrimSpace(req.RoundName)
+	interview.StartTime = req.StartTime.UTC()
+	interview.EndTime = req.EndTime.UTC()
+	interview.TimeZone = timeZone
+	interview.Location = req.Location
+	interview.VideoLink = req.VideoLink
+	interview.Notes = req.Notes
+	interview.Status = status
+	interview.UpdatedAt = time.Now()
+	if calendarChanged {
+		interview.Sequence++
+	}
+	slots := interviewSlotsFor(*interview, interviewers)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if status == models.InterviewStatusScheduled {
+			if err := services.LockInterviewerConflicts(tx, adminIDsOf(interviewers), interview.StartTime, interview.EndTime, &interview.ID); err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.Interview{}).Where("id = ?", interview.ID).Updates(map[string]interface{}{
+			"round_name": interview.RoundName,
+			"start_time": interview.StartTime,
+			"end_time":   interview.EndTime,
+			"time_zone":  interview.TimeZone,
+			"location":   interview.Location,
+			"video_link": interview.VideoLink,
+			"notes":      interview.Notes,
+			"status":     interview.Status,
+			"sequence":   interview.Sequence,
+			"updated_at": interview.UpdatedAt,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("interview_id = ?", interview.ID).Delete(&models.InterviewSlot{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&slots).Error
+	})
+	if respondInterviewConflict(c, err) {
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update interview"})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	if calendarChanged && status == models.InterviewStatusScheduled {
+		services.LogInterviewRescheduled(companyUUID, adminUUID, interview.ID, interview.Application.FullName, interview.RoundName, oldStart, interview.StartTime)
+		go func(interviewID uuid.UUID) {
+			services.SendInterviewRemovalNotices(interviewID, removed)
+			services.SendInterviewInvitations(interviewID, "rescheduled")
+		}(interview.ID)
+	}
+
+	interview.Slots = slots
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Interview updated successfully",
+		"interview": interview,
+	})
+}
+
+// CancelInterview cancels an interview and sends a calendar cancellation with the same UID
+func CancelInterview(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	interview, err := findCompanyInterview(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+	if !inJobScope(c, interview.JobID, "Interview not found") {
+		return
+	}
+	if interview.Status == models.InterviewStatusCancelled {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Interview is already cancelled"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		return services.CancelInterviewTx(tx, interview)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel interview"})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogInterviewCancelled(companyUUID, adminUUID, interview.ID, interview.Application.FullName, interview.RoundName)
+
+	go services.SendInterviewInvitations(interview.ID, "cancelled")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":   "Interview cancelled",
+		"interview": interview,
+	})
+}
+
+// DownloadInterviewICS returns the interview's current .ics file
+func DownloadInterviewICS(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	interview, err := findCompanyInterview(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+	if !inJobScope(c, interview.JobID, "Interview not found") {
+		return
+	}
+
+	jobTitle := ""
+	if interview.JobID != nil {
+		var job models.Job
+		if err := config.DB.Select("id, title").First(&job, "id = ?", *interview.JobID).Error; err == nil {
+			jobTitle = job.Title
+		}
+	}
+
+	attendees := []services.CalendarAttendee{{Name: interview.Application.FullName, Email: interview.Application.Email}}
+	for _, slot := range interview.Slots {
+		attendees = append(attendees, services.CalendarAttendee{Name: slot.Admin.Name, Email: slot.Admin.Email})
+	}
+	method := services.CalendarMethodRequest
+	if interview.Status == models.InterviewStatusCancelled {
+		method = services.CalendarMethodCancel
+	}
+	ics := services.BuildICS(services.InterviewCalendarEvent(*interview, jobTitle, services.CalendarAttendee{}, attendees, method))
+
+	c.Header("Content-Disposition", "attachment; filename=interview.ics")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", ics)
+}
+
+// CheckInterviewConflicts reports existing bookings that overlap a proposed interview time
+func CheckInterviewConflicts(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req CheckInterviewConflictsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := validateInterviewTimes(req.StartTime, req.EndTime, ""); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interviewers, err := loadCompanyInterviewers(companyID, req.InterviewerIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var exclude *uuid.UUID
+	if req.ExcludeInterviewID != "" {
+		if id, err := uuid.Parse(req.ExcludeInterviewID); err == nil {
+			exclude = &id
+		}
+	}
+
+	conflicts, err := services.FindInterviewerConflicts(adminIDsOf(interviewers), req.StartTime, req.EndTime, exclude)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to check interviewer availability"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"available": len(conflicts) == 0,
+		"conflicts": conflicts,
+	})
+}
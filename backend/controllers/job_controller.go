@@ -26,17 +26,40 @@ func getCompanyID(c *gin.Context) (string, error) {
 	return companyID, nil
 }
 
+// parsePublishAt reads an RFC 3339 publish time; empty means publish now
+func parsePublishAt(value string) (*time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, nil
+	}
+	publishAt, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, err
+	}
+	return &publishAt, nil
+}
+
 // CreateJob creates a new job posting
 func CreateJob(c *gin.Context) {
 	var jobRequest struct {
-		Title            string `json:"title" binding:"required"`
-		Description      string `json:"description" binding:"required"`
+		TemplateID       string `json:"template_id"` // Fills in the fields left empty, plus questions and pipeline
+		Title            string `json:"title"`
+		Description      string `json:"description"`
 		Requirements     string `json:"requirements"`
 		Location         string `json:"location"`
 		JobType          string `json:"job_type"`
+		Department       string `json:"department"`
 		SalaryRange      string `json:"salary_range"`
 		Deadline         string `json:"deadline" binding:"required"`
 		Status           string `json:"status"`
+		PublishAt        string `json:"publish_at"` // RFC 3339; a future time keeps the job scheduled until then
+		Headcount        int    `json:"headcount" binding:"min=0"` // 0 = not limited
+		KeepOpenWhenFilled bool  `json:"keep_open_when_filled"`
+		FilledMessage    string  `json:"filled_message"` // Emailed to remaining active candidates when the job closes filled
+		Justification    string  `json:"justification"`            // Requisition details, when jobs need approval
+		BudgetMin        float64 `json:"budget_min" binding:"gte=0"`
+		BudgetMax        float64 `json:"budget_max" binding:"gte=0"`
+		BudgetCurrency   string  `json:"budget_currency"`
 		AutoShortlist    bool   `json:"auto_shortlist"`
 		ShortlistCriteria string `json:"shortlist_criteria"`
 	}
@@ -45,6 +68,11 @@ func CreateJob(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	publishAt, err := parsePublishAt(jobRequest.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid publish_at format. Use RFC 3339, e.g. 2025-01-31T09:00:00Z"})
+		return
+	}
 
 	// Get company ID from JWT token (set by auth middleware)
 	companyIDVal, exists := c.Get("company_id")
@@ -75,6 +103,44 @@ func CreateJob(c *gin.Context) {
 		return
 	}
 
+	// Start from a job template: it fills in what the request leaves empty
+	var template *models.JobTemplate
+	setup := services.JobSetup{}
+	if jobRequest.TemplateID != "" {
+		template = &models.JobTemplate{}
+		if err := config.DB.Where("id = ? AND company_id = ?", jobRequest.TemplateID, companyID).First(template).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job template not found"})
+			return
+		}
+		questions, err := services.DecodeTemplateQuestions(*template)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read job template questions"})
+			return
+		}
+		setup.Questions = questions
+
+		for field, value := range map[*string]string{
+			&jobRequest.Title:        template.Title,
+			&jobRequest.Description:  template.Description,
+			&jobRequest.Requirements: template.Requirements,
+			&jobRequest.Location:     template.Location,
+			&jobRequest.JobType:      template.JobType,
+			&jobRequest.Department:   template.Department,
+			&jobRequest.SalaryRange:  template.SalaryRange,
+		} {
+			if strings.TrimSpace(*field) == "" {
+				*field = value
+			}
+		}
+		if jobRequest.ShortlistCriteria == "" && template.ShortlistCriteria != nil {
+			jobRequest.ShortlistCriteria = *template.ShortlistCriteria
+		}
+	}
+	if strings.TrimSpace(jobRequest.Title) == "" || strings.TrimSpace(jobRequest.Description) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title and description are required"})
+		return
+	}
+
 	// Parse deadline date string (format: "YYYY-MM-DD")
 	deadlineStr := strings.TrimSpace(jobRequest.Deadline)
 	deadlineTime, err := time.Parse("2006-01-02", deadlineStr)
@@ -100,9 +166,14 @@ func CreateJob(c *gin.Context) {
 		Requirements:     jobRequest.Requirements,
 		Location:         jobRequest.Location,
 		JobType:          jobRequest.JobType,
+		Department:       strings.TrimSpace(jobRequest.Department),
 		SalaryRange:      jobRequest.SalaryRange,
 		Deadline:         deadline,
 		Status:           jobRequest.Status,
+		PublishAt:        publishAt,
+		Headcount:        jobRequest.Headcount,
+		KeepOpenWhenFilled: jobRequest.KeepOpenWhenFilled,
+		FilledMessage:    strings.TrimSpace(jobRequest.FilledMessage),
 		AutoShortlist:    jobRequest.AutoShortlist,
 		CreatedAt:        time.Now(),
 		UpdatedAt:        time.Now(),
@@ -111,6 +182,10 @@ func CreateJob(c *gin.Context) {
 	if job.Status == "" {
 		job.Status = "open"
 	}
+	// The scheduler opens the job at publish_at
+	if publishAt != nil && publishAt.After(time.Now()) && job.Status == "open" {
+		job.Status = "scheduled"
+	}
 	if !job.AutoShortlist {
 		job.AutoShortlist = true
 	}
@@ -120,8 +195,30 @@ func CreateJob(c *gin.Context) {
 		job.ShortlistCriteria = &jobRequest.ShortlistCriteria
 	}
 
-	// Save to database
-	if err := config.DB.Create(&job).Error; err != nil {
+	// Companies that require approval get a draft requisition instead of a live job
+	services.StartRequisition(&job)
+	if job.RequisitionStatus != "" {
+		if err := applyRequisitionBudget(&job, jobRequest.BudgetMin, jobRequest.BudgetMax, jobRequest.BudgetCurrency); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		job.Justification = strings.TrimSpace(jobRequest.Justification)
+	}
+
+	if template != nil {
+		job.PipelineTemplateID = template.PipelineTemplateID
+	}
+
+	// Get admin ID from context; the creating admin owns the job and is notified when it opens or closes
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminID, _ := uuid.Parse(adminIDStr)
+	if adminID != uuid.Nil {
+		job.CreatedBy = &adminID
+	}
+
+	// Save to database, with the template's application form and a careers page slug
+	if err := services.CreateJobWithSetup(&job, setup); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error":   "Failed to create job",
 			"details": err.Error(),
@@ -129,13 +226,14 @@ func CreateJob(c *gin.Context) {
 		return
 	}
 
-	// Get admin ID from context
-	adminIDVal, _ := c.Get("admin_id")
-	adminIDStr, _ := adminIDVal.(string)
-	adminID, _ := uuid.Parse(adminIDStr)
-
 	// Log job creation
 	services.LogJobCreated(companyID, adminID, job.ID, job.Title)
+	if template != nil {
+		if err := services.MarkJobTemplateUsed(template.ID); err != nil {
+			log.Printf("CreateJob: failed to count use of job template %s: %v", template.ID, err)
+		}
+		services.LogJobTemplateUsed(companyID, adminID, template.ID, template.Name, job.ID, job.Title)
+	}
 
 	c.JSON(http.StatusCreated, gin.H{
 		"message": "Job created successfully",
@@ -152,17 +250,14 @@ func GetJobs(c *gin.Context) {
 	}
 	var jobs []models.Job
 
-	// Auto-close expired jobs for this company before fetching
-	// A job is considered expired if its deadline date is before today's date.
-	today := time.Now().UTC().Format("2006-01-02")
-	if err := config.DB.
-		Model(&models.Job{}).
-		Where("company_id = ? AND status = ? AND deadline < ?", companyID, "open", today).
-		Update("status", "closed").Error; err != nil {
-		log.Printf("GetJobs: failed to auto-close expired jobs for company %s: %v", companyID, err)
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
 	}
 
-	query := config.DB.Where("company_id = ?", companyID)
+	// Scheduled publishing and closing at the deadline are done by the job_lifecycle scheduled job
+	// Admins without a company-wide role only see the jobs whose hiring team they're on
+	query := scope.Apply(config.DB.Where("company_id = ?", companyID), "id")
 
 	// Filter by status if provided
 	if status := c.Query("status"); status != "" {
@@ -186,8 +281,13 @@ func GetJob(c *gin.Context) {
 		return
 	}
 
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
 	var job models.Job
-	if err := config.DB.Where("id = ? AND company_id = ?", jobID, companyID).First(&job).Error; err != nil {
+	if err := config.DB.Where("id = ? AND company_id = ?", jobID, companyID).First(&job).Error; err != nil || !scope.Allows(&job.ID) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
@@ -195,21 +295,10 @@ func GetJob(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"job": job})
 }
 
-// GetPublicJobs returns open jobs for public (no auth needed)
+// GetPublicJobs returns open jobs for public (no auth needed).
+// The company may be given by careers slug or ID; filters and pagination are those of GetCareersJobs.
 func GetPublicJobs(c *gin.Context) {
-	companyID := c.Param("companyId")
-	var jobs []models.Job
-
-	now := time.Now()
-	err := config.DB.Where("company_id = ? AND status = ? AND deadline > ?",
-		companyID, "open", now.Format("2006-01-02")).Find(&jobs).Error
-
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{"jobs": jobs})
+	renderCareersJobs(c, c.Param("companyId"))
 }
 
 // UpdateJob updates an existing job
@@ -226,6 +315,9 @@ func UpdateJob(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
 
 	// Store old values for logging
 	oldStatus := job.Status
@@ -242,9 +334,14 @@ func UpdateJob(c *gin.Context) {
 		Requirements     string `json:"requirements"`
 		Location         string `json:"location"`
 		JobType          string `json:"job_type"`
+		Department       *string `json:"department"`
 		SalaryRange      string `json:"salary_range"`
 		Deadline         string `json:"deadline"`
 		Status           string `json:"status"`
+		PublishAt        *string `json:"publish_at"` // Empty string publishes a scheduled job now
+		Headcount        *int   `json:"headcount" binding:"omitempty,min=0"`
+		KeepOpenWhenFilled *bool `json:"keep_open_when_filled"`
+		FilledMessage    *string `json:"filled_message"`
 		AutoShortlist    bool   `json:"auto_shortlist"`
 		ShortlistCriteria string `json:"shortlist_criteria"`
 	}
@@ -270,6 +367,9 @@ func UpdateJob(c *gin.Context) {
 	if jobRequest.JobType != "" {
 		job.JobType = jobRequest.JobType
 	}
+	if jobRequest.Department != nil {
+		job.Department = strings.TrimSpace(*jobRequest.Department)
+	}
 	if jobRequest.SalaryRange != "" {
 		job.SalaryRange = jobRequest.SalaryRange
 	}
@@ -289,8 +389,41 @@ func UpdateJob(c *gin.Context) {
 		}
 	}
 	if jobRequest.Status != "" {
+		if (jobRequest.Status == "open" || jobRequest.Status == "scheduled") && jobRequest.Status != job.Status {
+			if err := services.CheckJobPublishable(job); err != nil {
+				c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+				return
+			}
+		}
 		job.Status = jobRequest.Status
 	}
+	if jobRequest.PublishAt != nil {
+		publishAt, err := parsePublishAt(*jobRequest.PublishAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid publish_at format. Use RFC 3339, e.g. 2025-01-31T09:00:00Z"})
+			return
+		}
+		job.PublishAt = publishAt
+		scheduled := publishAt != nil && publishAt.After(time.Now())
+		if scheduled && job.Status == "open" {
+			job.Status = "scheduled"
+		} else if !scheduled && job.Status == "scheduled" {
+			job.Status = "open"
+		}
+	}
+	if jobRequest.Headcount != nil {
+		if *jobRequest.Headcount != job.Headcount && requisitionLocked(job) {
+			c.JSON(http.StatusConflict, gin.H{"error": "Headcount is part of the requisition under approval. Change it through the requisition."})
+			return
+		}
+		job.Headcount = *jobRequest.Headcount
+	}
+	if jobRequest.KeepOpenWhenFilled != nil {
+		job.KeepOpenWhenFilled = *jobRequest.KeepOpenWhenFilled
+	}
+	if jobRequest.FilledMessage != nil {
+		job.FilledMessage = strings.TrimSpace(*jobRequest.FilledMessage)
+	}
 	job.AutoShortlist = jobRequest.AutoShortlist
 	if jobRequest.ShortlistCriteria != "" {
 		job.ShortlistCriteria = &jobRequest.ShortlistCriteria
@@ -300,15 +433,21 @@ func UpdateJob(c *gin.Context) {
 
 	job.UpdatedAt = time.Now()
 
-	if err := config.DB.Save(&job).Error; err != nil {
+	// Hires are counted as applications move, don't overwrite them
+	if err := config.DB.Omit("hired_count").Save(&job).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job"})
 		return
 	}
 
+	// A lower headcount may leave the job filled
+	if jobRequest.Headcount != nil || jobRequest.KeepOpenWhenFilled != nil {
+		go services.CloseJobIfFilled(job.ID)
+	}
+
 	// Log job update - check if status changed or other fields
 	changes := make(map[string]interface{})
-	if jobRequest.Status != "" && jobRequest.Status != oldStatus {
-		services.LogJobStatusChanged(companyUUID, adminUUID, jobUUID, job.Title, oldStatus, job.Status)
+	if job.Status != oldStatus {
+		services.LogJobStatusChanged(companyUUID, &adminUUID, jobUUID, job.Title, oldStatus, job.Status, "")
 	} else {
 		// Log general update
 		if jobRequest.Title != "" && jobRequest.Title != oldTitle {
@@ -341,6 +480,9 @@ func DeleteJob(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
 		return
 	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
 
 	// Store job details for logging before deletion
 	jobTitle := job.Title
@@ -350,7 +492,8 @@ func DeleteJob(c *gin.Context) {
 	adminIDStr, _ := adminIDVal.(string)
 	adminUUID, _ := uuid.Parse(adminIDStr)
 
-	if err := config.DB.Delete(&job).Error; err != nil {
+	// Approval steps and hiring team stay with the trashed job, so a restore brings them back
+	if err := services.TrashJob(&job, adminUUID); err != nil {
 		log.Printf("DeleteJob ERROR: Failed to delete job %s: %v", jobID, err)
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"error": "Failed to delete job",
@@ -362,6 +505,6 @@ func DeleteJob(c *gin.Context) {
 	// Log job deletion (async, don't fail if logging fails)
 	services.LogJobDeleted(companyUUID, adminUUID, jobUUID, jobTitle)
 
-	c.JSON(http.StatusOK, gin.H{"message": "Job deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Job moved to the trash"})
 }
 
@@ -0,0 +1,300 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"encoding/xml"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// feedCacheControl lets aggregators and CDNs cache feeds for 15 minutes
+const feedCacheControl = "public, max-age=900"
+
+// indeedFeed is the Indeed XML job feed
+type indeedFeed struct {
+	XMLName       xml.Name    `xml:"source"`
+	Publisher     string      `xml:"publisher"`
+	PublisherURL  string      `xml:"publisherurl"`
+	LastBuildDate string      `xml:"lastBuildDate"`
+	Jobs          []indeedJob `xml:"job"`
+}
+
+type indeedJob struct {
+	Title           indeedCDATA  `xml:"title"`
+	Date            indeedCDATA  `xml:"date"`
+	ReferenceNumber indeedCDATA  `xml:"referencenumber"`
+	URL             indeedCDATA  `xml:"url"`
+	Company         indeedCDATA  `xml:"company"`
+	City            indeedCDATA  `xml:"city"`
+	Country         indeedCDATA  `xml:"country"`
+	Description     indeedCDATA  `xml:"description"`
+	Salary          *indeedCDATA `xml:"salary,omitempty"`
+	JobType         *indeedCDATA `xml:"jobtype,omitempty"`
+	ExpirationDate  indeedCDATA  `xml:"expirationdate"`
+	RemoteType      *indeedCDATA `xml:"remotetype,omitempty"`
+}
+
+type indeedCDATA struct {
+	Value string `xml:",cdata"`
+}
+
+// rssFeed is an RSS 2.0 feed
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category,omitempty"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// atomFeed is an Atom 1.0 feed
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string   `xml:"id"`
+	Title     string   `xml:"title"`
+	Updated   string   `xml:"updated"`
+	Published string   `xml:"published"`
+	Link      atomLink `xml:"link"`
+	Summary   string   `xml:"summary"`
+}
+
+// loadFeedJobs returns the company and its open jobs for a feed, answering 404 or 500 itself
+func loadFeedJobs(c *gin.Context) (models.Company, []models.Job, bool) {
+	var company models.Company
+	if err := config.DB.First(&company, "id = ?", c.Param("companyId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return company, nil, false
+	}
+	jobs, err := services.PublicOpenJobs(company.ID.String())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch jobs"})
+		return company, nil, false
+	}
+	return company, jobs, true
+}
+
+// feedSource returns the tracking source put on apply links, from ?source= or the feed's default
+func feedSource(c *gin.Context, fallback string) string {
+	if source := services.TrackingSource(c.Query("source")); source != "" {
+		return source
+	}
+	return fallback
+}
+
+// renderFeedXML writes a cacheable XML feed, with the XML declaration feed readers expect
+func renderFeedXML(c *gin.Context, contentType string, feed interface{}) {
+	body, err := xml.Marshal(feed)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build feed"})
+		return
+	}
+	c.Header("Cache-Control", feedCacheControl)
+	c.Data(http.StatusOK, contentType, append([]byte(xml.Header), body...))
+}
+
+// lastJobUpdate returns when the most recently changed job was updated, or now without jobs
+func lastJobUpdate(jobs []models.Job) time.Time {
+	latest := time.Time{}
+	for _, job := range jobs {
+		if job.UpdatedAt.After(latest) {
+			latest = job.UpdatedAt
+		}
+	}
+	if latest.IsZero() {
+		return time.Now()
+	}
+	return latest
+}
+
+// GetJobPostingsFeed returns the schema.org JobPosting JSON-LD of every open job of a company (public endpoint)
+func GetJobPostingsFeed(c *gin.Context) {
+	company, jobs, ok := loadFeedJobs(c)
+	if !ok {
+		return
+	}
+	source := feedSource(c, "google")
+
+	postings := make([]map[string]interface{}, 0, len(jobs))
+	for _, job := range jobs {
+		postings = append(postings, services.JobPostingJSONLD(job, company, source))
+	}
+
+	c.Header("Cache-Control", feedCacheControl)
+	c.Header("Content-Type", "application/ld+json; charset=utf-8")
+	c.JSON(http.StatusOK, postings)
+}
+
+// GetJobPosting returns the schema.org JobPosting JSON-LD of one open job, to embed in its page (public endpoint)
+func GetJobPosting(c *gin.Context) {
+	var company models.Company
+	if err := config.DB.First(&company, "id = ?", c.Param("companyId")).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+		return
+	}
+
+	var job models.Job
+	err := config.DB.Where("id = ? AND company_id = ? AND status = ? AND deadline > ?",
+		c.Param("jobId"), company.ID, "open", time.Now().Format("2006-01-02")).
+		First(&job).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+
+	c.Header("Cache-Control", feedCacheControl)
+	c.Header("Content-Type", "application/ld+json; charset=utf-8")
+	c.JSON(http.StatusOK, services.JobPostingJSONLD(job, company, feedSource(c, "google")))
+}
+
+// GetIndeedFeed returns an Indeed-compatible XML feed of a company's open jobs (public endpoint)
+func GetIndeedFeed(c *gin.Context) {
+	company, jobs, ok := loadFeedJobs(c)
+	if !ok {
+		return
+	}
+	source := feedSource(c, "indeed")
+
+	feed := indeedFeed{
+		Publisher:     company.CompanyName,
+		PublisherURL:  services.CareersPageURL(company.ID.String()),
+		LastBuildDate: lastJobUpdate(jobs).UTC().Format(time.RFC1123),
+		Jobs:          []indeedJob{},
+	}
+	if company.CompanyWebsite != "" {
+		feed.PublisherURL = company.CompanyWebsite
+	}
+	for _, job := range jobs {
+		city, country, remote := services.JobLocation(job.Location)
+		description := job.Description
+		if job.Requirements != "" {
+			description += "\n\nRequirements:\n" + job.Requirements
+		}
+		entry := indeedJob{
+			Title:           indeedCDATA{job.Title},
+			Date:            indeedCDATA{job.CreatedAt.UTC().Format(time.RFC1123)},
+			ReferenceNumber: indeedCDATA{job.ID.String()},
+			URL:             indeedCDATA{services.JobApplyURL(job.ID.String(), source)},
+			Company:         indeedCDATA{company.CompanyName},
+			City:            indeedCDATA{city},
+			Country:         indeedCDATA{country},
+			Description:     indeedCDATA{description},
+			ExpirationDate:  indeedCDATA{services.JobValidThrough(job).Format("2006-01-02")},
+		}
+		if job.SalaryRange != "" {
+			entry.Salary = &indeedCDATA{job.SalaryRange}
+		}
+		if jobType := services.IndeedJobType(job.JobType); jobType != "" {
+			entry.JobType = &indeedCDATA{jobType}
+		}
+		if remote {
+			entry.RemoteType = &indeedCDATA{"Fully remote"}
+		}
+		feed.Jobs = append(feed.Jobs, entry)
+	}
+
+	renderFeedXML(c, "application/xml; charset=utf-8", feed)
+}
+
+// GetRSSFeed returns an RSS 2.0 feed of a company's open jobs (public endpoint)
+func GetRSSFeed(c *gin.Context) {
+	company, jobs, ok := loadFeedJobs(c)
+	if !ok {
+		return
+	}
+	source := feedSource(c, "rss")
+
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         "Jobs at " + company.CompanyName,
+			Link:          services.CareersPageURL(company.ID.String()),
+			Description:   "Open positions at " + company.CompanyName,
+			LastBuildDate: lastJobUpdate(jobs).UTC().Format(time.RFC1123Z),
+			Items:         []rssItem{},
+		},
+	}
+	for _, job := range jobs {
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       job.Title,
+			Link:        services.JobApplyURL(job.ID.String(), source),
+			GUID:        rssGUID{IsPermaLink: false, Value: job.ID.String()},
+			PubDate:     job.CreatedAt.UTC().Format(time.RFC1123Z),
+			Description: job.Description,
+			Category:    job.JobType,
+		})
+	}
+
+	renderFeedXML(c, "application/rss+xml; charset=utf-8", feed)
+}
+
+// GetAtomFeed returns an Atom feed of a company's open jobs (public endpoint)
+func GetAtomFeed(c *gin.Context) {
+	company, jobs, ok := loadFeedJobs(c)
+	if !ok {
+		return
+	}
+	source := feedSource(c, "atom")
+
+	feed := atomFeed{
+		ID:      "urn:uuid:" + company.ID.String(),
+		Title:   "Jobs at " + company.CompanyName,
+		Updated: lastJobUpdate(jobs).UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: company.CompanyName},
+		Link:    atomLink{Href: services.CareersPageURL(company.ID.String())},
+		Entries: []atomEntry{},
+	}
+	for _, job := range jobs {
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        "urn:uuid:" + job.ID.String(),
+			Title:     job.Title,
+			Updated:   job.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: job.CreatedAt.UTC().Format(time.RFC3339),
+			Link:      atomLink{Href: services.JobApplyURL(job.ID.String(), source), Rel: "alternate"},
+			Summary:   job.Description,
+		})
+	}
+
+	renderFeedXML(c, "application/atom+xml; charset=utf-8", feed)
+}
@@ -0,0 +1,435 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// JobTemplateRequest for creating and updating job templates
+type JobTemplateRequest struct {
+	Name               string                `json:"name" binding:"required"`
+	Title              string                `json:"title"`
+	Description        string                `json:"description"`
+	Requirements       string                `json:"requirements"`
+	Location           string                `json:"location"`
+	JobType            string                `json:"job_type"`
+	Department         string                `json:"department"`
+	SalaryRange        string                `json:"salary_range"`
+	AutoShortlist      *bool                 `json:"auto_shortlist"`     // Defaults to true
+	ShortlistCriteria  string                `json:"shortlist_criteria"` // JSON, as on jobs
+	PipelineTemplateID *string               `json:"pipeline_template_id"`
+	Questions          []FormQuestionRequest `json:"questions"`
+}
+
+// SaveJobAsTemplateRequest for saving an existing job as a template
+type SaveJobAsTemplateRequest struct {
+	Name string `json:"name"` // Defaults to the job title
+}
+
+// CloneJobRequest for copying a job
+type CloneJobRequest struct {
+	Deadline  string `json:"deadline" binding:"required"` // YYYY-MM-DD
+	Title     string `json:"title"`                       // Defaults to the original title
+	Status    string `json:"status"`                      // Defaults to open
+	PublishAt string `json:"publish_at"`                  // RFC 3339; a future time keeps the copy scheduled until then
+}
+
+var errInvalidDeadline = errors.New("deadline can't be in the past")
+
+// parseJobDeadline reads a YYYY-MM-DD deadline that isn't in the past
+func parseJobDeadline(value string) (models.DateOnly, error) {
+	var deadline models.DateOnly
+	if err := deadline.UnmarshalJSON([]byte(strings.TrimSpace(value))); err != nil {
+		return deadline, err
+	}
+	if deadline.Time.Format("2006-01-02") < time.Now().UTC().Format("2006-01-02") {
+		return deadline, errInvalidDeadline
+	}
+	return deadline, nil
+}
+
+// findCompanyPipeline checks that a pipeline template belongs to the company; empty means the company default
+func findCompanyPipeline(companyID string, pipelineID *string) (*uuid.UUID, bool) {
+	if pipelineID == nil || *pipelineID == "" {
+		return nil, true
+	}
+	var pipeline models.PipelineTemplate
+	if err := config.DB.Select("id").Where("id = ? AND company_id = ?", *pipelineID, companyID).First(&pipeline).Error; err != nil {
+		return nil, false
+	}
+	return &pipeline.ID, true
+}
+
+// applyJobTemplateRequest validates a template request and copies it onto template
+func applyJobTemplateRequest(c *gin.Context, companyID string, req JobTemplateRequest, template *models.JobTemplate) bool {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Template name is required"})
+		return false
+	}
+	if req.ShortlistCriteria != "" && !json.Valid([]byte(req.ShortlistCriteria)) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "shortlist_criteria must be valid JSON"})
+		return false
+	}
+	pipelineID, ok := findCompanyPipeline(companyID, req.PipelineTemplateID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline not found"})
+		return false
+	}
+	questions, err := buildFormQuestions(req.Questions)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return false
+	}
+
+	template.Name = name
+	template.Title = strings.TrimSpace(req.Title)
+	template.Description = req.Description
+	template.Requirements = req.Requirements
+	template.Location = req.Location
+	template.JobType = req.JobType
+	template.Department = strings.TrimSpace(req.Department)
+	template.SalaryRange = req.SalaryRange
+	template.AutoShortlist = req.AutoShortlist == nil || *req.AutoShortlist
+	template.ShortlistCriteria = nil
+	if req.ShortlistCriteria != "" {
+		template.ShortlistCriteria = &req.ShortlistCriteria
+	}
+	template.PipelineTemplateID = pipelineID
+	template.Questions = services.EncodeTemplateQuestions(questions)
+	template.FormQuestions = questions
+	return true
+}
+
+// withTemplateQuestions fills in the decoded questions of a template for the response
+func withTemplateQuestions(template *models.JobTemplate) error {
+	questions, err := services.DecodeTemplateQuestions(*template)
+	if err != nil {
+		return err
+	}
+	template.FormQuestions = questions
+	return nil
+}
+
+// CreateJobTemplate saves a job template
+func CreateJobTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req JobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	template := models.JobTemplate{CompanyID: companyUUID, CreatedBy: &adminUUID}
+	if !applyJobTemplateRequest(c, companyID, req, &template) {
+		return
+	}
+	if err := config.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job template"})
+		return
+	}
+
+	services.LogJobTemplateSaved(companyUUID, adminUUID, template.ID, template.Name, true)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Job template created successfully",
+		"template": template,
+	})
+}
+
+// GetJobTemplates returns the company's job templates, most used first
+func GetJobTemplates(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var templates []models.JobTemplate
+	if err := config.DB.Where("company_id = ?", companyID).
+		Order("usage_count DESC").
+		Order("name ASC").
+		Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch job templates"})
+		return
+	}
+	for i := range templates {
+		if err := withTemplateQuestions(&templates[i]); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read job template questions"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"templates": templates})
+}
+
+// GetJobTemplate returns a single job template
+func GetJobTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var template models.JobTemplate
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job template not found"})
+		return
+	}
+	if err := withTemplateQuestions(&template); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to read job template questions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+// UpdateJobTemplate replaces a job template. Jobs already created from it are not changed.
+func UpdateJobTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var template models.JobTemplate
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job template not found"})
+		return
+	}
+
+	var req JobTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !applyJobTemplateRequest(c, companyID, req, &template) {
+		return
+	}
+	template.UpdatedAt = time.Now()
+
+	if err := config.DB.Save(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job template"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogJobTemplateSaved(template.CompanyID, adminUUID, template.ID, template.Name, false)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Job template updated successfully",
+		"template": template,
+	})
+}
+
+// DeleteJobTemplate deletes a job template. Jobs created from it are kept.
+func DeleteJobTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var template models.JobTemplate
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job template not found"})
+		return
+	}
+	if err := config.DB.Delete(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete job template"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	services.LogJobTemplateDeleted(template.CompanyID, adminUUID, template.ID, template.Name)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Job template deleted successfully"})
+}
+
+// SaveJobAsTemplate saves an existing job, with its screening questions and pipeline, as a template
+func SaveJobAsTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req SaveJobAsTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+	setup, err := services.LoadJobSetup(job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load job application form"})
+		return
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = job.Title
+	}
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	template := models.JobTemplate{
+		CompanyID:          job.CompanyID,
+		Name:               name,
+		Title:              job.Title,
+		Description:        job.Description,
+		Requirements:       job.Requirements,
+		Location:           job.Location,
+		JobType:            job.JobType,
+		Department:         job.Department,
+		SalaryRange:        job.SalaryRange,
+		AutoShortlist:      job.AutoShortlist,
+		ShortlistCriteria:  job.ShortlistCriteria,
+		PipelineTemplateID: job.PipelineTemplateID,
+		Questions:          services.EncodeTemplateQuestions(setup.Questions),
+		CreatedBy:          &adminUUID,
+		FormQuestions:      setup.Questions,
+	}
+	if err := config.DB.Create(&template).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create job template"})
+		return
+	}
+
+	services.LogJobTemplateSaved(template.CompanyID, adminUUID, template.ID, template.Name, true)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Job saved as template successfully",
+		"template": template,
+	})
+}
+
+// CloneJob copies a job with a new deadline: its details, shortlist criteria, pipeline,
+// application form and scorecard template. Applications are not copied.
+func CloneJob(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req CloneJobRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	deadline, err := parseJobDeadline(req.Deadline)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid deadline: " + err.Error()})
+		return
+	}
+	publishAt, err := parsePublishAt(req.PublishAt)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid publish_at format. Use RFC 3339, e.g. 2025-01-31T09:00:00Z"})
+		return
+	}
+
+	var source models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&source).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &source.ID, "Job not found") {
+		return
+	}
+	setup, err := services.LoadJobSetup(source.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load job setup"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	now := time.Now()
+	job := models.Job{
+		CompanyID:          source.CompanyID,
+		Title:              source.Title,
+		Description:        source.Description,
+		Requirements:       source.Requirements,
+		Location:           source.Location,
+		JobType:            source.JobType,
+		Department:         source.Department,
+		SalaryRange:        source.SalaryRange,
+		Deadline:           deadline,
+		Status:             "open",
+		PublishAt:          publishAt,
+		Headcount:          source.Headcount,
+		KeepOpenWhenFilled: source.KeepOpenWhenFilled,
+		FilledMessage:      source.FilledMessage,
+		Justification:      source.Justification,
+		BudgetMin:          source.BudgetMin,
+		BudgetMax:          source.BudgetMax,
+		BudgetCurrency:     source.BudgetCurrency,
+		CreatedBy:          &adminUUID,
+		AutoShortlist:      source.AutoShortlist,
+		ShortlistCriteria:  source.ShortlistCriteria,
+		PipelineTemplateID: source.PipelineTemplateID,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		job.Title = title
+	}
+	if status := strings.TrimSpace(req.Status); status != "" {
+		job.Status = status
+	}
+	if publishAt != nil && publishAt.After(now) && job.Status == "open" {
+		job.Status = "scheduled"
+	}
+	// A copy is a new requisition and needs its own approval
+	services.StartRequisition(&job)
+
+	if err := services.CreateJobWithSetup(&job, setup); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to clone job"})
+		return
+	}
+
+	services.LogJobCloned(job.CompanyID, adminUUID, job.ID, job.Title, source.ID, source.Title)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":          "Job cloned successfully",
+		"job":              job,
+		"cloned_from":      source.ID,
+		"questions_copied": len(setup.Questions),
+		"scorecard_copied": setup.Scorecard != nil,
+	})
+}
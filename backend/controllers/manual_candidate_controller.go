@@ -5,10 +5,11 @@ import (
 	"ats-backend/models"
 	"ats-backend/services"
 	"encoding/json"
+	"fmt"
 	"log"
 	"net/http"
+	"strings"
 	"time"
-	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -26,7 +27,7 @@ type ManualCandidateRequest struct {
 	CurrentPosition    string `json:"current_position"`
 	LinkedinURL        string `json:"linkedin_url"`
 	PortfolioURL       string `json:"portfolio_url"`
-	Status             string `json:"status"` // Can be "pending", "shortlisted", etc.
+	Status             string `json:"status"` // Any stage key of the job's pipeline (defaults to its first stage)
 	Notes              string `json:"notes"` // Admin notes about why this candidate was added manually
 }
 
@@ -62,6 +63,9 @@ func AddManualCandidate(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found or doesn't belong to your company"})
 		return
 	}
+	if !inJobScope(c, &job.ID, "Job not found or doesn't belong to your company") {
+		return
+	}
 
 	// Check if candidate already applied for this job
 	var existingApp models.Application
@@ -99,94 +103,28 @@ func AddManualCandidate(c *gin.Context) {
 	}
 
 	if application.Status == "" {
-		application.Status = "pending"
+		application.Status = services.InitialStageKey(companyUUID, &jobID)
+	} else {
+		stages, err := services.GetPipelineStages(companyUUID, &jobID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline"})
+			return
+		}
+		if _, found := services.FindPipelineStage(stages, application.Status); !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status is not a stage of this job's pipeline"})
+			return
+		}
 	}
 
-	// Save application
-	if err := config.DB.Create(&application).Error; err != nil {
+	// Save application (with the first status history entry)
+	if err := services.CreateApplicationWithHistory(&application, services.AdminActor(adminID)); err != nil {
 		log.Printf("Failed to create manual candidate: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to add candidate"})
 		return
 	}
 
-	// Parse and store CV text (async)
-	go func() {
-		if application.ResumeURL != "" {
-			log.Printf("Parsing CV text for manually added candidate %s (URL: %s)", application.ID.String(), application.ResumeURL)
-			cvText, err := services.ExtractTextFromURL(application.ResumeURL)
-			if err != nil {
-				log.Printf("ERROR: Failed to parse CV text for %s (Email: %s, URL: %s): %v", 
-					application.FullName, application.Email, application.ResumeURL, err)
-				return
-			}
-			
-			if len(cvText) < 50 {
-				log.Printf("WARNING: CV text too short for %s (%d characters). URL: %s", 
-					application.FullName, len(cvText), application.ResumeURL)
-				return
-			}
-			
-			// Validate UTF-8 before saving
-			if !utf8.ValidString(cvText) {
-				log.Printf("ERROR: Extracted CV text is not valid UTF-8 for %s. Skipping save.", 
-					application.FullName)
-				return
-			}
-			
-			// Update using the application ID (more reliable than using the model)
-			updateErr := config.DB.Model(&models.Application{}).
-				Where("id = ?", application.ID).
-				Update("parsed_cv_text", cvText).Error
-			
-			if updateErr != nil {
-				log.Printf("ERROR: Failed to save parsed CV text to database for %s: %v", 
-					application.FullName, updateErr)
-			} else {
-				log.Printf("SUCCESS: CV text parsed and stored for %s (%d characters)", 
-					application.FullName, len(cvText))
-			}
-		} else {
-			log.Printf("WARNING: No ResumeURL provided for manually added application %s", application.ID.String())
-		}
-	}()
-
-	// Auto-analyze CV if job has criteria (async)
-	go func() {
-		log.Printf("Auto-analyzing CV for manually added candidate %s", application.ID.String())
-		
-		var criteria services.Criteria
-		
-		// Try to use job's shortlist criteria if available
-		if job.ShortlistCriteria != nil && *job.ShortlistCriteria != "" {
-			if err := json.Unmarshal([]byte(*job.ShortlistCriteria), &criteria); err == nil {
-				criteria.JobDescription = job.Description
-				criteria.JobRequirements = job.Requirements
-			}
-		} else {
-			// Use default criteria based on job description
-			criteria = services.Criteria{
-				RequiredSkills:      []string{},
-				MinExperience:       0,
-				RequiredLanguages:   []string{},
-				MatchJobDescription: true,
-				JobDescription:      job.Description,
-				JobRequirements:     job.Requirements,
-			}
-		}
-		
-		// Analyze CV
-		analysisResult, err := services.MatchCVFromURL(application.ResumeURL, criteria, job.Title)
-		if err == nil {
-			application.Score = analysisResult.MatchScore
-			analysisJSON, _ := json.Marshal(analysisResult)
-			analysisJSONStr := string(analysisJSON)
-			application.AnalysisResult = &analysisJSONStr
-			config.DB.Save(&application)
-			log.Printf("CV analyzed for manually added candidate %s: Score=%d%%", application.FullName, analysisResult.MatchScore)
-		} else {
-			log.Printf("Failed to analyze CV for manually added candidate %s: %v", application.Email, err)
-		}
-	}()
+	// Parse, store and score the CV in the background
+	services.QueueCVProcessing(application.ID)
 
 	// Log manual candidate addition
 	services.LogActivity(
@@ -210,3 +148,109 @@ func AddManualCandidate(c *gin.Context) {
 	})
 }
 
+
+// maxImportCSVSize and maxImportArchiveSize limit the files of a bulk import
+const (
+	maxImportCSVSize     = 5 * 1024 * 1024   // 5MB
+	maxImportArchiveSize = 500 * 1024 * 1024 // 500MB
+)
+
+// ImportCandidates bulk imports candidates from a CSV, with their CVs in an optional ZIP archive.
+// Multipart form fields:
+//   - csv: the candidates, one per row (required)
+//   - cvs: ZIP of CV files, matched by the row's resume_file column or named after the candidate's email
+//   - job_id: job for rows without a job_id column
+//   - status: stage for every imported candidate (defaults to the pipeline's first stage)
+//   - mapping: JSON object of application field to CSV header, e.g. {"full_name": "Candidate Name"}
+func ImportCandidates(c *gin.Context) {
+	companyIDStr, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	companyID, _ := uuid.Parse(companyIDStr)
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminID, _ := uuid.Parse(adminIDStr)
+
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	options := services.ImportOptions{
+		CompanyID: companyID,
+		AdminID:   adminID,
+		Status:    strings.TrimSpace(c.PostForm("status")),
+		Scope:     scope,
+	}
+	if jobIDStr := c.PostForm("job_id"); jobIDStr != "" {
+		jobID, err := uuid.Parse(jobIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+			return
+		}
+		var job models.Job
+		if err := config.DB.Where("id = ? AND company_id = ?", jobID, companyID).First(&job).Error; err != nil || !scope.Allows(&job.ID) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Job not found or doesn't belong to your company"})
+			return
+		}
+		options.JobID = &jobID
+	}
+
+	mapping := map[string]string{}
+	if mappingStr := c.PostForm("mapping"); mappingStr != "" {
+		if err := json.Unmarshal([]byte(mappingStr), &mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "mapping must be a JSON object of field to CSV header"})
+			return
+		}
+	}
+
+	csvFile, csvHeader, err := c.Request.FormFile("csv")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No CSV file provided"})
+		return
+	}
+	defer csvFile.Close()
+	if csvHeader.Size > maxImportCSVSize {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("CSV file exceeds maximum allowed size of %d MB", maxImportCSVSize/(1024*1024)),
+		})
+		return
+	}
+
+	rows, err := services.ParseImportCSV(csvFile, mapping)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error(), "fields": services.ImportFields})
+		return
+	}
+
+	var archive *services.CVArchive
+	archiveName := ""
+	if archiveFile, archiveHeader, err := c.Request.FormFile("cvs"); err == nil {
+		defer archiveFile.Close()
+		if archiveHeader.Size > maxImportArchiveSize {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("CV archive exceeds maximum allowed size of %d MB", maxImportArchiveSize/(1024*1024)),
+			})
+			return
+		}
+		archive, err = services.OpenCVArchive(archiveFile, archiveHeader.Size)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		archiveName = archiveHeader.Filename
+	}
+
+	report := services.ImportCandidates(rows, archive, options)
+	log.Printf("SUCCESS: Imported %d of %d candidate(s) for company %s (%d skipped, %d failed)",
+		report.Imported, report.Total, companyID, report.Skipped, report.Failed)
+	services.LogCandidatesImported(companyID, adminID, report, csvHeader.Filename, archiveName)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Imported %d of %d candidate(s)", report.Imported, report.Total),
+		"report":  report,
+	})
+}
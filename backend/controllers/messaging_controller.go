@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -17,13 +18,12 @@ import (
 type SendMessageRequest struct {
 	ApplicationID string `json:"application_id" binding:"required"`
 	Message       string `json:"message" binding:"required"`
-	SenderEmail   string `json:"sender_email" binding:"required,email"`
+	SenderEmail   string `json:"sender_email" binding:"omitempty,email"` // Recruiters only; candidates send as the signed-in email
 }
 
 // GetMessagesRequest for retrieving messages
 type GetMessagesRequest struct {
 	ApplicationID string `json:"application_id" binding:"required"`
-	Email         string `json:"email" binding:"required,email"`
 }
 
 // SendMessage handles sending messages (both candidate and recruiter)
@@ -46,9 +46,16 @@ func SendMessage(c *gin.Context) {
 	var senderID *uuid.UUID
 
 	// Check if sender is a recruiter (admin)
-	_, exists := c.Get("company_id")
+	companyIDVal, exists := c.Get("company_id")
 	if exists {
-		// This is a protected route, so sender is a recruiter
+		// This is a protected route, so sender is a recruiter of the application's company
+		if companyID, _ := companyIDVal.(string); application.CompanyID.String() != companyID {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+			return
+		}
+		if !applicationInJobScope(c, application) {
+			return
+		}
 		senderType = "recruiter"
 		adminIDVal, _ := c.Get("admin_id")
 		adminIDStr, _ := adminIDVal.(string)
@@ -57,11 +64,12 @@ func SendMessage(c *gin.Context) {
 			senderID = &adminUUID
 		}
 	} else {
-		// Public route - verify email matches application email
-		if req.SenderEmail != application.Email {
-			c.JSON(http.StatusForbidden, gin.H{"error": "Email does not match application"})
+		// Candidate route - only the signed-in candidate can write on their application
+		if candidateEmail(c) == "" || !strings.EqualFold(candidateEmail(c), application.Email) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 			return
 		}
+		req.SenderEmail = application.Email
 	}
 
 	// Create message
@@ -137,20 +145,19 @@ func SendMessage(c *gin.Context) {
 	})
 }
 
-// GetMessages retrieves messages for an application
+// GetMessages retrieves messages for one of the signed-in candidate's applications
 func GetMessages(c *gin.Context) {
 	applicationID := c.Query("application_id")
-	email := c.Query("email")
 
-	if applicationID == "" || email == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "application_id and email are required"})
+	if applicationID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "application_id is required"})
 		return
 	}
 
-	// Verify application exists and email matches
+	// Verify application belongs to the candidate
 	var application models.Application
-	if err := config.DB.Where("id = ? AND email = ?", applicationID, email).First(&application).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found or email does not match"})
+	if err := config.DB.Where("id = ? AND LOWER(email) = ?", applicationID, candidateEmail(c)).First(&application).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
 
@@ -206,6 +213,9 @@ func GetMessagesForRecruiter(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
 		return
 	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
 
 	// Get messages for this application
 	var messages []models.Message
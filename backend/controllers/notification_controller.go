@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetNotifications returns the admin's in-app notifications (newest first)
+func GetNotifications(c *gin.Context) {
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	query := config.DB.Where("admin_id = ?", adminIDStr).Order("created_at DESC")
+
+	// Only unread notifications if requested
+	if c.Query("unread") == "true" {
+		query = query.Where("is_read = false")
+	}
+
+	var notifications []models.Notification
+	if err := query.Limit(100).Find(&notifications).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch notifications"})
+		return
+	}
+
+	var unreadCount int64
+	config.DB.Model(&models.Notification{}).
+		Where("admin_id = ? AND is_read = false", adminIDStr).
+		Count(&unreadCount)
+
+	c.JSON(http.StatusOK, gin.H{
+		"notifications": notifications,
+		"count":         len(notifications),
+		"unread_count":  unreadCount,
+	})
+}
+
+// MarkNotificationRead marks a single notification as read
+func MarkNotificationRead(c *gin.Context) {
+	notificationID := c.Param("id")
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	var notification models.Notification
+	if err := config.DB.Where("id = ? AND admin_id = ?", notificationID, adminIDStr).First(&notification).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification not found"})
+		return
+	}
+
+	if !notification.IsRead {
+		now := time.Now()
+		notification.IsRead = true
+		notification.ReadAt = &now
+		if err := config.DB.Save(&notification).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notification"})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Notification marked as read",
+		"notification": notification,
+	})
+}
+
+// MarkAllNotificationsRead marks all of the admin's notifications as read
+func MarkAllNotificationsRead(c *gin.Context) {
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	result := config.DB.Model(&models.Notification{}).
+		Where("admin_id = ? AND is_read = false", adminIDStr).
+		Updates(map[string]interface{}{"is_read": true, "read_at": time.Now()})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":       "All notifications marked as read",
+		"updated_count": result.RowsAffected,
+	})
+}
@@ -0,0 +1,839 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"ats-backend/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OfferDetailsRequest holds the editable details of an offer
+type OfferDetailsRequest struct {
+	Salary        float64    `json:"salary" binding:"required,gt=0"`
+	Currency      string     `json:"currency" binding:"required"` // ISO 4217, e.g. EUR
+	Bonus         float64    `json:"bonus" binding:"gte=0"`
+	StartDate     string     `json:"start_date"` // YYYY-MM-DD
+	ExpiresAt     *time.Time `json:"expires_at"` // Default 7 days after the offer is created
+	TermsTemplate string     `json:"terms_template"`
+	ApproverIDs   []string   `json:"approver_ids"` // Approval chain, in order
+}
+
+// CreateOfferRequest for drafting an offer
+type CreateOfferRequest struct {
+	ApplicationID string `json:"application_id" binding:"required"`
+	OfferDetailsRequest
+}
+
+// OfferApprovalDecisionRequest for approving or rejecting an offer
+type OfferApprovalDecisionRequest struct {
+	Decision string `json:"decision" binding:"required"` // approve, reject
+	Comment  string `json:"comment"`
+}
+
+// DeclineOfferRequest for a candidate declining an offer
+type DeclineOfferRequest struct {
+	Reason string `json:"reason"`
+}
+
+// loadOfferApprovers returns the approvers in the given order; all must be admins of the company
+func loadOfferApprovers(companyID string, ids []string) ([]models.Admin, error) {
+	approvers := []models.Admin{}
+	if len(ids) == 0 {
+		return approvers, nil
+	}
+
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid approver id %q", id)
+		}
+		if seen[id] {
+			return nil, errors.New("an approver can only appear once in the approval chain")
+		}
+		seen[id] = true
+	}
+
+	var admins []models.Admin
+	if err := config.DB.Where("id IN ? AND company_id = ?", ids, companyID).Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	byID := map[string]models.Admin{}
+	for _, admin := range admins {
+		byID[admin.ID.String()] = admin
+	}
+	for _, id := range ids {
+		admin, ok := byID[id]
+		if !ok {
+			return nil, errors.New("every approver must be an admin of your company")
+		}
+		approvers = append(approvers, admin)
+	}
+	return approvers, nil
+}
+
+// applyOfferDetails validates the request and copies it onto the offer, rendering its terms
+func applyOfferDetails(offer *models.Offer, req OfferDetailsRequest, application models.Application, companyName string) error {
+	currency := strings.ToUpper(strings.TrimSpace(req.Currency))
+	if len(currency) != 3 {
+		return errors.New("currency must be a 3-letter ISO code, e.g. EUR")
+	}
+
+	var startDate *time.Time
+	if req.StartDate != "" {
+		parsed, err := time.Parse("2006-01-02", req.StartDate)
+		if err != nil {
+			return errors.New("invalid start_date. Use YYYY-MM-DD")
+		}
+		startDate = &parsed
+	}
+
+	expiresAt := time.Now().AddDate(0, 0, 7)
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(time.Now()) {
+			return errors.New("expires_at must be in the future")
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	termsTemplate := req.TermsTemplate
+	if strings.TrimSpace(termsTemplate) == "" {
+		termsTemplate = services.DefaultOfferTermsTemplate
+	}
+
+	jobTitle := "Unknown Job (Job Deleted)"
+	if application.JobID != nil && application.Job.ID != uuid.Nil {
+		jobTitle = application.Job.Title
+	}
+
+	offer.Salary = req.Salary
+	offer.Currency = currency
+	offer.Bonus = req.Bonus
+	offer.StartDate = startDate
+	offer.ExpiresAt = expiresAt.UTC()
+	offer.TermsTemplate = termsTemplate
+	offer.Terms = services.RenderOfferTerms(termsTemplate, services.OfferTermsData{
+		CandidateName: application.FullName,
+		JobTitle:      jobTitle,
+		CompanyName:   companyName,
+		Salary:        offer.Salary,
+		Currency:      offer.Currency,
+		Bonus:         offer.Bonus,
+		StartDate:     offer.StartDate,
+		ExpiresAt:     offer.ExpiresAt,
+	})
+	return nil
+}
+
+// offerApprovalsFor builds the approval chain of an offer
+func offerApprovalsFor(offerID uuid.UUID, approvers []models.Admin) []models.OfferApproval {
+	approvals := []models.OfferApproval{}
+	for i, approver := range approvers {
+		approvals = append(approvals, models.OfferApproval{
+			OfferID:    offerID,
+			ApproverID: approver.ID,
+			Step:       i,
+			Status:     models.OfferApprovalPending,
+		})
+	}
+	return approvals
+}
+
+// findCompanyOffer loads an offer of the company with its approval chain
+func findCompanyOffer(offerID, companyID string) (*models.Offer, error) {
+	var offer models.Offer
+	err := config.DB.Where("id = ? AND company_id = ?", offerID, companyID).
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step ASC")
+		}).
+		Preload("Approvals.Approver").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, full_name, email, phone, job_id, company_id, status")
+		}).
+		Preload("Application.Job").
+		First(&offer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// offerJobTitle returns the title of the offer's job
+func offerJobTitle(offer models.Offer) string {
+	if offer.Application.JobID != nil && offer.Application.Job.ID != uuid.Nil {
+		return offer.Application.Job.Title
+	}
+	return "Unknown Job (Job Deleted)"
+}
+
+// companyNameOf returns the display name of a company
+func companyNameOf(companyID uuid.UUID) string {
+	var company models.Company
+	if err := config.DB.Select("id, company_name").First(&company, "id = ?", companyID).Error; err != nil {
+		return "our company"
+	}
+	return company.CompanyName
+}
+
+// notifyNextOfferApprover asks the approver of the next pending step to review the offer
+func notifyNextOfferApprover(offer models.Offer) {
+	next, ok := services.NextOfferApproval(offer)
+	if !ok {
+		return
+	}
+	if err := services.CreateNotification(offer.CompanyID, next.ApproverID, "offer_approval_requested",
+		"Offer awaiting your approval",
+		fmt.Sprintf("Please review the offer for %s (%s)", offer.Application.FullName, offerJobTitle(offer)),
+		"offer", &offer.ID); err != nil {
+		log.Printf("ERROR: Failed to notify approver %s of offer %s: %v", next.ApproverID, offer.ID, err)
+	}
+}
+
+// CreateOffer drafts an offer for an application
+func CreateOffer(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req CreateOfferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var application models.Application
+	// Verify application belongs to company (even if job is deleted)
+	err = config.DB.Table("applications").
+		Select("applications.*").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", req.ApplicationID, companyID, companyID).
+		Preload("Job").
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	var openOffers int64
+	config.DB.Model(&models.Offer{}).
+		Where("application_id = ? AND status IN ?", application.ID, services.OpenOfferStatuses).
+		Count(&openOffers)
+	if openOffers > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This candidate already has an offer in progress. Withdraw it first."})
+		return
+	}
+
+	approvers, err := loadOfferApprovers(companyID, req.ApproverIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	now := time.Now()
+	offer := models.Offer{
+		ID:            uuid.New(),
+		CompanyID:     companyUUID,
+		ApplicationID: application.ID,
+		JobID:         application.JobID,
+		Status:        models.OfferStatusDraft,
+		CreatedBy:     adminUUID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := applyOfferDetails(&offer, req.OfferDetailsRequest, application, companyNameOf(companyUUID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	offer.Approvals = offerApprovalsFor(offer.ID, approvers)
+
+	if err := config.DB.Create(&offer).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create offer"})
+		return
+	}
+
+	services.LogOfferCreated(companyUUID, adminUUID, offer.ID, application.FullName, offer.Salary, offer.Currency)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Offer drafted successfully",
+		"offer":   offer,
+	})
+}
+
+// GetOffers lists offers, filtered by application_id and status
+func GetOffers(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	query := scope.Apply(config.DB.Model(&models.Offer{}).Where("company_id = ?", companyID), "job_id")
+	if applicationID := c.Query("application_id"); applicationID != "" {
+		query = query.Where("application_id = ?", applicationID)
+	}
+	if status := c.Query("status"); status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var offers []models.Offer
+	if err := query.
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step ASC")
+		}).
+		Preload("Approvals.Approver").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, full_name, email, job_id, company_id, status")
+		}).
+		Order("created_at DESC").
+		Find(&offers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch offers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"offers": offers,
+		"count":  len(offers),
+	})
+}
+
+// GetOffer returns a single offer with its approval chain
+func GetOffer(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	offer, err := findCompanyOffer(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+	if !inJobScope(c, offer.JobID, "Offer not found") {
+		return
+	}
+
+	response := gin.H{"offer": offer}
+	if next, ok := services.NextOfferApproval(*offer); ok && offer.Status == models.OfferStatusPendingApproval {
+		response["awaiting_approver_id"] = next.ApproverID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateOffer changes a draft or rejected offer. The offer goes back to draft and needs approval again.
+func UpdateOffer(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req OfferDetailsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offer, err := findCompanyOffer(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+	if !inJobScope(c, offer.JobID, "Offer not found") {
+		return
+	}
+	if offer.Status != models.OfferStatusDraft && offer.Status != models.OfferStatusRejected {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft or rejected offers can be edited"})
+		return
+	}
+
+	approvers, err := loadOfferApprovers(companyID, req.ApproverIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyOfferDetails(offer, req, offer.Application, companyNameOf(offer.CompanyID)); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	offer.Status = models.OfferStatusDraft
+	offer.UpdatedAt = time.Now()
+	approvals := offerApprovalsFor(offer.ID, approvers)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("offer_id = ?", offer.ID).Delete(&models.OfferApproval{}).Error; err != nil {
+			return err
+		}
+		if len(approvals) > 0 {
+			if err := tx.Create(&approvals).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Offer{}).Where("id = ?", offer.ID).Updates(map[string]interface{}{
+			"salary":         offer.Salary,
+			"currency":       offer.Currency,
+			"bonus":          offer.Bonus,
+			"start_date":     offer.StartDate,
+			"expires_at":     offer.ExpiresAt,
+			"terms_template": offer.TermsTemplate,
+			"terms":          offer.Terms,
+			"status":         offer.Status,
+			"updated_at":     offer.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update offer"})
+		return
+	}
+	offer.Approvals = approvals
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Offer updated successfully",
+		"offer":   offer,
+	})
+}
+
+// SubmitOfferForApproval starts the approval chain. Offers without approvers are approved right away.
+func SubmitOfferForApproval(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	offer, err := findCompanyOffer(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+	if !inJobScope(c, offer.JobID, "Offer not found") {
+		return
+	}
+	if offer.Status != models.OfferStatusDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft offers can be submitted for approval"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	offer.Status = models.OfferStatusPendingApproval
+	if len(offer.Approvals) == 0 {
+		offer.Status = models.OfferStatusApproved
+	}
+	if err := config.DB.Model(offer).Updates(map[string]interface{}{
+		"status":     offer.Status,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit offer"})
+		return
+	}
+
+	services.LogOfferStatusChanged(offer.CompanyID, adminUUID, offer.ID, offer.Application.FullName, offer.Status)
+	notifyNextOfferApprover(*offer)
+
+	message := "Offer submitted for approval"
+	if offer.Status == models.OfferStatusApproved {
+		message = "Offer approved - it has no approvers"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"offer":   offer,
+	})
+}
+
+// DecideOfferApproval records the current approver's decision. Approvers decide in the order of the chain;
+// a rejection sends the offer back to its creator.
+func DecideOfferApproval(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req OfferApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	decision := strings.ToLower(strings.TrimSpace(req.Decision))
+	if decision != "approve" && decision != "reject" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "decision must be approve or reject"})
+		return
+	}
+	if decision == "reject" && strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please add a comment explaining the rejection"})
+		return
+	}
+
+	offer, err := findCompanyOffer(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+	if !inJobScope(c, offer.JobID, "Offer not found") {
+		return
+	}
+	if offer.Status != models.OfferStatusPendingApproval {
+		c.JSON(http.StatusConflict, gin.H{"error": "This offer is not awaiting approval"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	approval, ok := services.NextOfferApproval(*offer)
+	if !ok || approval.ApproverID != adminUUID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "It's not your turn to approve this offer"})
+		return
+	}
+
+	now := time.Now()
+	approval.Status = models.OfferApprovalApproved
+	if decision == "reject" {
+		approval.Status = models.OfferApprovalRejected
+	}
+	approval.Comment = strings.TrimSpace(req.Comment)
+	approval.DecidedAt = &now
+
+	// Rejecting ends the chain; approving the last step approves the offer
+	newStatus := models.OfferStatusPendingApproval
+	if decision == "reject" {
+		newStatus = models.OfferStatusRejected
+	} else if _, more := services.NextOfferApproval(*offer); !more {
+		newStatus = models.OfferStatusApproved
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.OfferApproval{}).
+			Where("id = ? AND status = ?", approval.ID, models.OfferApprovalPending).
+			Updates(map[string]interface{}{
+				"status":     approval.Status,
+				"comment":    approval.Comment,
+				"decided_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("already decided")
+		}
+		// The offer may have been withdrawn while the approval was pending
+		result = tx.Model(&models.Offer{}).
+			Where("id = ? AND status = ?", offer.ID, models.OfferStatusPendingApproval).
+			Updates(map[string]interface{}{
+				"status":     newStatus,
+				"updated_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected != 1 {
+			return errors.New("offer no longer pending approval")
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to record your decision, it may already have been recorded"})
+		return
+	}
+	offer.Status = newStatus
+	offer.UpdatedAt = now
+
+	services.LogOfferApprovalDecision(offer.CompanyID, adminUUID, offer.ID, offer.Application.FullName, approval.Status, approval.Comment)
+
+	switch newStatus {
+	case models.OfferStatusPendingApproval:
+		notifyNextOfferApprover(*offer)
+	case models.OfferStatusApproved, models.OfferStatusRejected:
+		if offer.CreatedBy != adminUUID {
+			title := "Offer approved"
+			message := fmt.Sprintf("The offer for %s is approved and ready to send", offer.Application.FullName)
+			if newStatus == models.OfferStatusRejected {
+				title = "Offer rejected"
+				message = fmt.Sprintf("The offer for %s was rejected: %s", offer.Application.FullName, approval.Comment)
+			}
+			if err := services.CreateNotification(offer.CompanyID, offer.CreatedBy, "offer_"+newStatus, title, message, "offer", &offer.ID); err != nil {
+				log.Printf("ERROR: Failed to notify creator of offer %s: %v", offer.ID, err)
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Decision recorded",
+		"offer":   offer,
+	})
+}
+
+// SendOffer emails an approved offer to the candidate and moves the application to the offer stage
+func SendOffer(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	offer, err := findCompanyOffer(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+	if !inJobScope(c, offer.JobID, "Offer not found") {
+		return
+	}
+	if offer.Status != models.OfferStatusApproved {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only approved offers can be sent"})
+		return
+	}
+	if !offer.ExpiresAt.After(time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This offer has already expired. Update its expiry date first."})
+		return
+	}
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send offer"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	now := time.Now()
+	result := config.DB.Model(&models.Offer{}).
+		Where("id = ? AND status = ?", offer.ID, models.OfferStatusApproved).
+		Updates(map[string]interface{}{
+			"status":     models.OfferStatusSent,
+			"token":      token,
+			"sent_at":    now,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to send offer"})
+		return
+	}
+	if result.RowsAffected != 1 {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only approved offers can be sent"})
+		return
+	}
+	offer.Status = models.OfferStatusSent
+	offer.SentAt = &now
+	offer.UpdatedAt = now
+
+	services.LogOfferStatusChanged(offer.CompanyID, adminUUID, offer.ID, offer.Application.FullName, offer.Status)
+
+	var application models.Application
+	if err := config.DB.Preload("Job").First(&application, "id = ?", offer.ApplicationID).Error; err == nil {
+		oldStatus := application.Status
+		moved, err := services.AdvanceApplicationStage(&application, "offer", services.AdminActor(adminUUID), "Offer sent")
+		if err != nil {
+			log.Printf("ERROR: Failed to move application %s to offer: %v", application.ID, err)
+		} else if moved {
+			services.LogApplicationStatusChanged(application.CompanyID, adminUUID, application.ID, application.FullName, offerJobTitle(*offer), oldStatus, application.Status)
+		}
+	}
+
+	offerURL := services.OfferURL(token)
+	go func(to, name, jobTitle, companyName string, expiresAt time.Time) {
+		if err := services.SendOfferEmail(to, name, jobTitle, companyName, offerURL, expiresAt); err != nil {
+			log.Printf("ERROR: Failed to send offer email to %s: %v", to, err)
+		} else {
+			log.Printf("SUCCESS: Offer email sent to %s", to)
+		}
+	}(offer.Application.Email, offer.Application.FullName, offerJobTitle(*offer), companyNameOf(offer.CompanyID), offer.ExpiresAt)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Offer sent to the candidate",
+		"offer":   offer,
+		"url":     offerURL,
+	})
+}
+
+// WithdrawOffer withdraws an offer that the candidate hasn't answered yet
+func WithdrawOffer(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	offer, err := findCompanyOffer(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+	if !inJobScope(c, offer.JobID, "Offer not found") {
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	result := config.DB.Model(&models.Offer{}).
+		Where("id = ? AND status IN ?", offer.ID, services.OpenOfferStatuses).
+		Updates(map[string]interface{}{
+			"status":     models.OfferStatusWithdrawn,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to withdraw offer"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "This offer can no longer be withdrawn"})
+		return
+	}
+	offer.Status = models.OfferStatusWithdrawn
+
+	services.LogOfferStatusChanged(offer.CompanyID, adminUUID, offer.ID, offer.Application.FullName, offer.Status)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Offer withdrawn",
+		"offer":   offer,
+	})
+}
+
+// findOfferByToken loads a sent offer by the token in the candidate's link.
+// The offer must be for an application of the signed-in candidate's email.
+func findOfferByToken(token, email string) (*models.Offer, error) {
+	if token == "" || email == "" {
+		return nil, gorm.ErrRecordNotFound
+	}
+	var offer models.Offer
+	err := config.DB.Where("token = ?", token).
+		Where("application_id IN (SELECT id FROM applications WHERE LOWER(email) = ? AND deleted_at IS NULL)", email).
+		Preload("Approvals").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, full_name, email, phone, job_id, company_id, status")
+		}).
+		Preload("Application.Job").
+		First(&offer).Error
+	if err != nil {
+		return nil, err
+	}
+	return &offer, nil
+}
+
+// candidateOfferView returns what the candidate gets to see of an offer
+func candidateOfferView(offer models.Offer) gin.H {
+	return gin.H{
+		"job_title":      offerJobTitle(offer),
+		"company_name":   companyNameOf(offer.CompanyID),
+		"candidate_name": offer.Application.FullName,
+		"salary":         offer.Salary,
+		"currency":       offer.Currency,
+		"bonus":          offer.Bonus,
+		"start_date":     offer.StartDate,
+		"expires_at":     offer.ExpiresAt,
+		"terms":          offer.Terms,
+		"status":         offer.Status,
+		"responded_at":   offer.RespondedAt,
+		"can_respond":    services.IsOfferOpen(offer),
+	}
+}
+
+// GetCandidateOffer shows an offer to the candidate (candidate session and link token)
+func GetCandidateOffer(c *gin.Context) {
+	offer, err := findOfferByToken(c.Param("token"), candidateEmail(c))
+	if err != nil || offer.Status == models.OfferStatusWithdrawn {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+
+	if offer.ViewedAt == nil && offer.Status == models.OfferStatusSent {
+		now := time.Now()
+		config.DB.Model(offer).Update("viewed_at", now)
+		offer.ViewedAt = &now
+	}
+
+	c.JSON(http.StatusOK, gin.H{"offer": candidateOfferView(*offer)})
+}
+
+// AcceptOffer accepts an offer for the candidate (candidate session and link token)
+func AcceptOffer(c *gin.Context) {
+	respondToCandidateOffer(c, true)
+}
+
+// DeclineOffer declines an offer for the candidate (candidate session and link token)
+func DeclineOffer(c *gin.Context) {
+	respondToCandidateOffer(c, false)
+}
+
+// respondToCandidateOffer records the candidate's answer and tells the hiring team
+func respondToCandidateOffer(c *gin.Context, accept bool) {
+	var req DeclineOfferRequest
+	if !accept {
+		// The reason is optional, so an empty body is fine
+		_ = c.ShouldBindJSON(&req)
+	}
+
+	offer, err := findOfferByToken(c.Param("token"), candidateEmail(c))
+	if err != nil || offer.Status == models.OfferStatusWithdrawn {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Offer not found"})
+		return
+	}
+
+	if err := services.RespondToOffer(offer, accept, strings.TrimSpace(req.Reason)); err != nil {
+		if errors.Is(err, services.ErrOfferNotOpen) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error(), "offer": candidateOfferView(*offer)})
+			return
+		}
+		if errors.Is(err, services.ErrApplicationClosed) {
+			c.JSON(http.StatusConflict, gin.H{"error": "This offer can no longer be accepted because your application is closed. Please contact the hiring team."})
+			return
+		}
+		log.Printf("ERROR: Failed to record response to offer %s: %v", offer.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record your response. Please try again."})
+		return
+	}
+
+	candidateName := offer.Application.FullName
+	jobTitle := offerJobTitle(*offer)
+	services.LogOfferStatusChanged(offer.CompanyID, offer.CreatedBy, offer.ID, candidateName, offer.Status)
+
+	message := "Offer accepted. Welcome aboard!"
+	if accept {
+		services.NotifyOfferTeam(*offer, "offer_accepted", "Offer accepted",
+			fmt.Sprintf("%s accepted the offer for %s", candidateName, jobTitle))
+	} else {
+		message = "Offer declined. Thank you for letting us know."
+		description := fmt.Sprintf("%s declined the offer for %s", candidateName, jobTitle)
+		if offer.DeclineReason != "" {
+			description += ": " + offer.DeclineReason
+		}
+		services.NotifyOfferTeam(*offer, "offer_declined", "Offer declined", description)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"offer":   candidateOfferView(*offer),
+	})
+}
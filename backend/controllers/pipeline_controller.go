@@ -0,0 +1,347 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PipelineStageRequest is one stage of a pipeline template request
+type PipelineStageRequest struct {
+	Key  string `json:"key"` // Defaults to the name, e.g. "Tech Interview" -> "tech_interview"
+	Name string `json:"name" binding:"required"`
+	Type string `json:"type"` // active (default), hired, rejected
+}
+
+// PipelineTemplateRequest for creating and updating pipeline templates
+type PipelineTemplateRequest struct {
+	Name      string                 `json:"name" binding:"required"`
+	IsDefault bool                   `json:"is_default"`
+	Stages    []PipelineStageRequest `json:"stages" binding:"required"`
+}
+
+// buildPipelineStages converts and validates the requested stages, in the order given
+func buildPipelineStages(requested []PipelineStageRequest) ([]models.PipelineStage, error) {
+	stages := make([]models.PipelineStage, 0, len(requested))
+	for i, stage := range requested {
+		key := stage.Key
+		if strings.TrimSpace(key) == "" {
+			key = stage.Name
+		}
+		stageType := strings.ToLower(strings.TrimSpace(stage.Type))
+		if stageType == "" {
+			stageType = models.StageTypeActive
+		}
+		stages = append(stages, models.PipelineStage{
+			Key:      services.NormalizeStageKey(key),
+			Name:     strings.TrimSpace(stage.Name),
+			Type:     stageType,
+			Position: i,
+		})
+	}
+	if err := services.ValidatePipelineStages(stages); err != nil {
+		return nil, err
+	}
+	return stages, nil
+}
+
+// clearDefaultPipeline unsets the default flag on the company's other templates
+func clearDefaultPipeline(tx *gorm.DB, companyID string, keepID uuid.UUID) error {
+	return tx.Model(&models.PipelineTemplate{}).
+		Where("company_id = ? AND id <> ? AND is_default = ?", companyID, keepID, true).
+		Update("is_default", false).Error
+}
+
+// CreatePipelineTemplate creates a pipeline template with ordered stages
+func CreatePipelineTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req PipelineTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stages, err := buildPipelineStages(req.Stages)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	now := time.Now()
+	template := models.PipelineTemplate{
+		CompanyID: companyUUID,
+		Name:      strings.TrimSpace(req.Name),
+		IsDefault: req.IsDefault,
+		CreatedAt: now,
+		UpdatedAt: now,
+		Stages:    stages,
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&template).Error; err != nil {
+			return err
+		}
+		if template.IsDefault {
+			return clearDefaultPipeline(tx, companyID, template.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create pipeline"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":  "Pipeline created successfully",
+		"pipeline": template,
+	})
+}
+
+// GetPipelineTemplates returns the company's pipeline templates and the built-in default
+func GetPipelineTemplates(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var templates []models.PipelineTemplate
+	if err := config.DB.Where("company_id = ?", companyID).
+		Preload("Stages", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		Order("created_at ASC").
+		Find(&templates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch pipelines"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"pipelines":       templates,
+		"built_in_stages": services.DefaultPipelineStages(),
+	})
+}
+
+// GetPipelineTemplate returns a single pipeline template
+func GetPipelineTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var template models.PipelineTemplate
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).
+		Preload("Stages", func(db *gorm.DB) *gorm.DB { return db.Order("position ASC") }).
+		First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"pipeline": template})
+}
+
+// UpdatePipelineTemplate renames a template and replaces its stages.
+// Applications sitting in a removed stage keep their status until they are moved.
+func UpdatePipelineTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var template models.PipelineTemplate
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline not found"})
+		return
+	}
+
+	var req PipelineTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	stages, err := buildPipelineStages(req.Stages)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	template.Name = strings.TrimSpace(req.Name)
+	template.IsDefault = req.IsDefault
+	template.UpdatedAt = time.Now()
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&template).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("pipeline_template_id = ?", template.ID).Delete(&models.PipelineStage{}).Error; err != nil {
+			return err
+		}
+		for i := range stages {
+			stages[i].PipelineTemplateID = template.ID
+		}
+		if err := tx.Create(&stages).Error; err != nil {
+			return err
+		}
+		if template.IsDefault {
+			return clearDefaultPipeline(tx, companyID, template.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update pipeline"})
+		return
+	}
+
+	template.Stages = stages
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Pipeline updated successfully",
+		"pipeline": template,
+	})
+}
+
+// DeletePipelineTemplate deletes a template. Jobs using it fall back to the company default.
+func DeletePipelineTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var template models.PipelineTemplate
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&template).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline not found"})
+		return
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Job{}).
+			Where("company_id = ? AND pipeline_template_id = ?", companyID, template.ID).
+			Update("pipeline_template_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.JobTemplate{}).
+			Where("company_id = ? AND pipeline_template_id = ?", companyID, template.ID).
+			Update("pipeline_template_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("pipeline_template_id = ?", template.ID).Delete(&models.PipelineStage{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&template).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete pipeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Pipeline deleted successfully"})
+}
+
+// GetJobPipeline returns the stages that apply to a job
+func GetJobPipeline(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+
+	stages, err := services.GetPipelineStages(job.CompanyID, &job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"job_id":               job.ID,
+		"pipeline_template_id": job.PipelineTemplateID, // null = company default
+		"stages":               stages,
+	})
+}
+
+// SetJobPipeline overrides the pipeline for a single job (null resets it to the company default)
+func SetJobPipeline(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req struct {
+		PipelineTemplateID *string `json:"pipeline_template_id"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+
+	var templateID *uuid.UUID
+	if req.PipelineTemplateID != nil && *req.PipelineTemplateID != "" {
+		var template models.PipelineTemplate
+		if err := config.DB.Where("id = ? AND company_id = ?", *req.PipelineTemplateID, companyID).First(&template).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Pipeline not found"})
+			return
+		}
+		templateID = &template.ID
+	}
+
+	if err := config.DB.Model(&job).Updates(map[string]interface{}{
+		"pipeline_template_id": templateID,
+		"updated_at":           time.Now(),
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update job pipeline"})
+		return
+	}
+
+	stages, err := services.GetPipelineStages(job.CompanyID, &job.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load pipeline"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":              "Job pipeline updated successfully",
+		"pipeline_template_id": templateID,
+		"stages":               stages,
+	})
+}
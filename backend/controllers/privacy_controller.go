@@ -0,0 +1,324 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// CandidateErasureRequest for erasing everything held about a candidate email
+type CandidateErasureRequest struct {
+	Email  string `json:"email" binding:"required,email"`
+	Action string `json:"action" binding:"required"` // delete or anonymise
+}
+
+// PrivacyNoticeRequest for publishing a new privacy notice version
+type PrivacyNoticeRequest struct {
+	Title   string `json:"title" binding:"required"`
+	Content string `json:"content" binding:"required"`
+}
+
+// RetentionPolicyRequest for creating and updating retention policies
+type RetentionPolicyRequest struct {
+	Stage    string `json:"stage" binding:"required"` // e.g. rejected or withdrawn
+	Months   int    `json:"months" binding:"required,min=1"`
+	Action   string `json:"action" binding:"required"` // delete or anonymise
+	IsActive *bool  `json:"is_active"`                 // Defaults to true
+}
+
+// ExportCandidateData returns a ZIP of everything the company holds about a candidate email (?email=)
+func ExportCandidateData(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+	email := strings.TrimSpace(c.Query("email"))
+	if email == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "email is required"})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	applications, err := services.CandidateApplications(companyUUID, email)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch candidate data"})
+		return
+	}
+	if len(applications) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No candidate data found for this email"})
+		return
+	}
+
+	data, err := services.BuildCandidateExport(email, applications)
+	if err != nil {
+		log.Printf("ERROR: Failed to build candidate export for company %s: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to build candidate export"})
+		return
+	}
+
+	services.LogCandidateDataExported(companyUUID, currentAdminID(c), email, len(applications))
+
+	filename := fmt.Sprintf("candidate-data-%s.zip", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.Data(http.StatusOK, "application/zip", data)
+}
+
+// EraseCandidateData deletes or anonymises everything the company holds about a candidate email,
+// including their files in storage
+func EraseCandidateData(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req CandidateErasureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if !services.IsValidRetentionAction(req.Action) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action. Must be: delete or anonymise"})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminUUID := currentAdminID(c)
+	erased, err := services.EraseCandidate(companyUUID, adminUUID, req.Email, req.Action)
+	if erased > 0 {
+		services.LogCandidateErased(companyUUID, adminUUID, req.Action, erased)
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to erase candidate of company %s: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":        "Failed to erase all candidate data",
+			"erased_count": erased,
+		})
+		return
+	}
+	if erased == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No candidate data found for this email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      fmt.Sprintf("Erased candidate data from %d application(s)", erased),
+		"erased_count": erased,
+		"action":       req.Action,
+	})
+}
+
+// GetRetentionPolicies returns the company's retention policies
+func GetRetentionPolicies(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var policies []models.RetentionPolicy
+	if err := config.DB.Where("company_id = ?", companyID).Order("created_at ASC").Find(&policies).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch retention policies"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"policies": policies})
+}
+
+// applyRetentionPolicyRequest validates a policy request and copies it onto policy
+func applyRetentionPolicyRequest(c *gin.Context, req RetentionPolicyRequest, policy *models.RetentionPolicy) bool {
+	if !services.IsValidRetentionAction(req.Action) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action. Must be: delete or anonymise"})
+		return false
+	}
+	// A policy without a stage would erase candidates still in the process
+	policy.Stage = services.NormalizeStageKey(req.Stage)
+	if policy.Stage == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Choose the stage whose candidates the policy erases, e.g. rejected"})
+		return false
+	}
+	policy.Months = req.Months
+	policy.Action = req.Action
+	if req.IsActive != nil {
+		policy.IsActive = *req.IsActive
+	}
+	return true
+}
+
+// CreateRetentionPolicy adds a retention policy; it first runs with the next daily retention job
+func CreateRetentionPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req RetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	adminUUID := currentAdminID(c)
+	companyUUID, _ := uuid.Parse(companyID)
+	policy := models.RetentionPolicy{
+		CompanyID: companyUUID,
+		IsActive:  true,
+		CreatedBy: &adminUUID,
+	}
+	if !applyRetentionPolicyRequest(c, req, &policy) {
+		return
+	}
+	if err := config.DB.Create(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create retention policy"})
+		return
+	}
+
+	services.LogRetentionPolicyChanged(companyUUID, adminUUID, policy, "created")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": "Retention policy created",
+		"policy":  policy,
+	})
+}
+
+// UpdateRetentionPolicy changes a retention policy of the company
+func UpdateRetentionPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req RetentionPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var policy models.RetentionPolicy
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&policy).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Retention policy not found"})
+		return
+	}
+	if !applyRetentionPolicyRequest(c, req, &policy) {
+		return
+	}
+	policy.UpdatedAt = time.Now()
+	if err := config.DB.Save(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update retention policy"})
+		return
+	}
+
+	services.LogRetentionPolicyChanged(policy.CompanyID, currentAdminID(c), policy, "updated")
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Retention policy updated",
+		"policy":  policy,
+	})
+}
+
+// DeleteRetentionPolicy removes a retention policy of the company
+func DeleteRetentionPolicy(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var policy models.RetentionPolicy
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&policy).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Retention policy not found"})
+		return
+	}
+	if err := config.DB.Delete(&policy).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete retention policy"})
+		return
+	}
+
+	services.LogRetentionPolicyChanged(policy.CompanyID, currentAdminID(c), policy, "deleted")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Retention policy deleted"})
+}
+
+// GetPrivacyNotices returns every published version of the company's privacy notice, newest first
+func GetPrivacyNotices(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var notices []models.PrivacyNotice
+	if err := config.DB.Where("company_id = ?", companyID).Order("version DESC").Find(&notices).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch privacy notices"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"notices": notices})
+}
+
+// PublishPrivacyNotice publishes the next version of the company's privacy notice.
+// Candidates must accept the new version from then on.
+func PublishPrivacyNotice(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	if !requireCompanyWideAccess(c) {
+		return
+	}
+
+	var req PrivacyNoticeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	title := strings.TrimSpace(req.Title)
+	content := strings.TrimSpace(req.Content)
+	if title == "" || content == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Title and content are required"})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminUUID := currentAdminID(c)
+	notice, err := services.PublishPrivacyNotice(companyUUID, adminUUID, title, content)
+	if err != nil {
+		log.Printf("ERROR: Failed to publish privacy notice of company %s: %v", companyID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to publish privacy notice"})
+		return
+	}
+
+	services.LogPrivacyNoticePublished(companyUUID, adminUUID, notice)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message": fmt.Sprintf("Privacy notice version %d published", notice.Version),
+		"notice":  notice,
+	})
+}
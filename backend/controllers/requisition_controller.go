@@ -0,0 +1,381 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RequisitionRequest holds the details an approver signs off on before a job goes live
+type RequisitionRequest struct {
+	Justification  string   `json:"justification" binding:"required"`
+	Headcount      int      `json:"headcount" binding:"required,gt=0"`
+	BudgetMin      float64  `json:"budget_min" binding:"gte=0"`
+	BudgetMax      float64  `json:"budget_max" binding:"required,gt=0"`
+	BudgetCurrency string   `json:"budget_currency" binding:"required"` // ISO 4217, e.g. EUR
+	ApproverIDs    []string `json:"approver_ids"`                       // Approval chain, in order; empty uses the company's default chain
+}
+
+// RequisitionDecisionRequest for approving or rejecting a job requisition
+type RequisitionDecisionRequest struct {
+	Decision string `json:"decision" binding:"required"` // approve, reject
+	Comment  string `json:"comment"`
+}
+
+// applyRequisitionBudget validates a salary band and copies it onto the job
+func applyRequisitionBudget(job *models.Job, budgetMin, budgetMax float64, currency string) error {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if currency != "" && len(currency) != 3 {
+		return errors.New("budget_currency must be a 3-letter ISO code, e.g. EUR")
+	}
+	if budgetMax > 0 && budgetMin > budgetMax {
+		return errors.New("budget_min can't be more than budget_max")
+	}
+	job.BudgetMin = budgetMin
+	job.BudgetMax = budgetMax
+	job.BudgetCurrency = currency
+	return nil
+}
+
+// requisitionLocked reports whether a job's requisition is with its approvers or already approved,
+// so what they sign off on can't change under them
+func requisitionLocked(job models.Job) bool {
+	return job.RequisitionStatus == models.RequisitionPendingApproval ||
+		job.RequisitionStatus == models.RequisitionApproved
+}
+
+// findCompanyRequisition loads a job of the company with its approval chain
+func findCompanyRequisition(jobID, companyID string) (*models.Job, error) {
+	var job models.Job
+	err := config.DB.Where("id = ? AND company_id = ?", jobID, companyID).
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step ASC")
+		}).
+		Preload("Approvals.Approver").
+		First(&job).Error
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// UpdateJobRequisition sets the justification, headcount, budget and approval chain of a requisition.
+// Rejected or approved requisitions go back to draft and need approval again.
+func UpdateJobRequisition(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req RequisitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	job, err := findCompanyRequisition(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+	switch {
+	case job.RequisitionStatus == "":
+		c.JSON(http.StatusConflict, gin.H{"error": "This job doesn't need approval"})
+		return
+	case job.RequisitionStatus == models.RequisitionPendingApproval:
+		c.JSON(http.StatusConflict, gin.H{"error": "This requisition is awaiting approval and can't be edited"})
+		return
+	case job.Status == "open" || job.Status == "scheduled":
+		c.JSON(http.StatusConflict, gin.H{"error": "Close this job before changing its approved requisition"})
+		return
+	}
+
+	approvers, err := services.LoadRequisitionApprovers(companyID, req.ApproverIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := applyRequisitionBudget(job, req.BudgetMin, req.BudgetMax, req.BudgetCurrency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	job.Justification = strings.TrimSpace(req.Justification)
+	job.Headcount = req.Headcount
+	job.RequisitionStatus = models.RequisitionDraft
+	job.UpdatedAt = time.Now()
+	approvals := services.JobApprovalsFor(job.ID, approvers)
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("job_id = ?", job.ID).Delete(&models.JobApproval{}).Error; err != nil {
+			return err
+		}
+		if len(approvals) > 0 {
+			if err := tx.Create(&approvals).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&models.Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"justification":      job.Justification,
+			"headcount":          job.Headcount,
+			"budget_min":         job.BudgetMin,
+			"budget_max":         job.BudgetMax,
+			"budget_currency":    job.BudgetCurrency,
+			"requisition_status": job.RequisitionStatus,
+			"updated_at":         job.UpdatedAt,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update requisition"})
+		return
+	}
+	job.Approvals = approvals
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Requisition updated successfully",
+		"job":     job,
+	})
+}
+
+// SubmitJobRequisition sends a draft requisition to its first approver. Requisitions without their own
+// approval chain use the company's default chain.
+func SubmitJobRequisition(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	job, err := findCompanyRequisition(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+	if job.RequisitionStatus != models.RequisitionDraft {
+		c.JSON(http.StatusConflict, gin.H{"error": "Only draft requisitions can be submitted for approval"})
+		return
+	}
+	if strings.TrimSpace(job.Justification) == "" || job.Headcount == 0 || job.BudgetMax == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Add a justification, headcount and budget before submitting"})
+		return
+	}
+
+	if len(job.Approvals) == 0 {
+		var company models.Company
+		if err := config.DB.Select("id, requisition_approver_ids").First(&company, "id = ?", companyID).Error; err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Company not found"})
+			return
+		}
+		approvers, err := services.LoadRequisitionApprovers(companyID, services.CompanyRequisitionApproverIDs(company))
+		if err != nil {
+			c.JSON(http.StatusConflict, gin.H{"error": "The company's default approval chain is no longer valid: " + err.Error()})
+			return
+		}
+		if len(approvers) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Choose at least one approver, or set up the company's default approval chain"})
+			return
+		}
+		job.Approvals = services.JobApprovalsFor(job.ID, approvers)
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	job.RequisitionStatus = models.RequisitionPendingApproval
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		for i := range job.Approvals {
+			if job.Approvals[i].ID != uuid.Nil {
+				continue
+			}
+			if err := tx.Create(&job.Approvals[i]).Error; err != nil {
+				return err
+			}
+		}
+		result := tx.Model(&models.Job{}).
+			Where("id = ? AND requisition_status = ?", job.ID, models.RequisitionDraft).
+			Updates(map[string]interface{}{
+				"requisition_status": job.RequisitionStatus,
+				"updated_at":         time.Now(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("already submitted")
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to submit requisition, it may already have been submitted"})
+		return
+	}
+
+	services.LogRequisitionSubmitted(job.CompanyID, adminUUID, job.ID, job.Title, job.Headcount, len(job.Approvals))
+	services.NotifyNextRequisitionApprover(*job)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Requisition submitted for approval",
+		"job":     job,
+	})
+}
+
+// DecideJobRequisition records the current approver's decision. Approvers decide in the order of the chain;
+// the last approval makes the job publishable and a rejection sends it back to its owner.
+func DecideJobRequisition(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req RequisitionDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	decision := strings.ToLower(strings.TrimSpace(req.Decision))
+	if decision != "approve" && decision != "reject" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "decision must be approve or reject"})
+		return
+	}
+	if decision == "reject" && strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Please add a comment explaining the rejection"})
+		return
+	}
+
+	job, err := findCompanyRequisition(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if job.RequisitionStatus != models.RequisitionPendingApproval {
+		c.JSON(http.StatusConflict, gin.H{"error": "This requisition is not awaiting approval"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	approval, ok := services.NextJobApproval(*job)
+	if !ok || approval.ApproverID != adminUUID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "It's not your turn to approve this requisition"})
+		return
+	}
+
+	now := time.Now()
+	approval.Status = models.JobApprovalApproved
+	if decision == "reject" {
+		approval.Status = models.JobApprovalRejected
+	}
+	approval.Comment = strings.TrimSpace(req.Comment)
+	approval.DecidedAt = &now
+
+	// Rejecting ends the chain; approving the last step approves the requisition
+	newStatus := models.RequisitionPendingApproval
+	if decision == "reject" {
+		newStatus = models.RequisitionRejected
+	} else if _, more := services.NextJobApproval(*job); !more {
+		newStatus = models.RequisitionApproved
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.JobApproval{}).
+			Where("id = ? AND status = ?", approval.ID, models.JobApprovalPending).
+			Updates(map[string]interface{}{
+				"status":     approval.Status,
+				"comment":    approval.Comment,
+				"decided_at": now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.New("already decided")
+		}
+		return tx.Model(&models.Job{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"requisition_status": newStatus,
+			"updated_at":         now,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "Failed to record your decision, it may already have been recorded"})
+		return
+	}
+	job.RequisitionStatus = newStatus
+
+	services.LogRequisitionDecision(job.CompanyID, adminUUID, job.ID, job.Title, approval.Status, approval.Comment, approval.Step)
+
+	switch newStatus {
+	case models.RequisitionPendingApproval:
+		services.NotifyNextRequisitionApprover(*job)
+	case models.RequisitionApproved:
+		services.NotifyJobOwner(*job, "requisition_approved", "Requisition approved",
+			fmt.Sprintf("%s is approved and can now be published", job.Title))
+	case models.RequisitionRejected:
+		services.NotifyJobOwner(*job, "requisition_rejected", "Requisition rejected",
+			fmt.Sprintf("%s was rejected: %s", job.Title, approval.Comment))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Decision recorded",
+		"job":     job,
+	})
+}
+
+// GetPendingRequisitions returns the requisitions waiting for the current admin's decision
+func GetPendingRequisitions(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	var jobs []models.Job
+	err = config.DB.Where("company_id = ? AND requisition_status = ?", companyID, models.RequisitionPendingApproval).
+		Where("id IN (?)", config.DB.Model(&models.JobApproval{}).
+			Select("job_id").
+			Where("approver_id = ? AND status = ?", adminUUID, models.JobApprovalPending)).
+		Preload("Approvals", func(db *gorm.DB) *gorm.DB {
+			return db.Order("step ASC")
+		}).
+		Preload("Approvals.Approver").
+		Order("updated_at ASC").
+		Find(&jobs).Error
+	if err != nil {
+		log.Printf("GetPendingRequisitions: failed to load requisitions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch requisitions"})
+		return
+	}
+
+	// Only those where it's the admin's turn
+	pending := []models.Job{}
+	for _, job := range jobs {
+		if next, ok := services.NextJobApproval(job); ok && next.ApproverID == adminUUID {
+			pending = append(pending, job)
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"requisitions": pending})
+}
@@ -0,0 +1,293 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SavedSearchRequest for creating and updating saved searches
+type SavedSearchRequest struct {
+	Name          string                  `json:"name" binding:"required"`
+	Criteria      SearchCandidatesRequest `json:"criteria"`
+	IsShared      bool                    `json:"is_shared"`
+	AlertsEnabled *bool                   `json:"alerts_enabled"`
+	AlertChannel  string                  `json:"alert_channel"` // email (default) or in_app
+}
+
+// normalizeAlertChannel validates an alert channel, defaulting to email
+func normalizeAlertChannel(channel string) (string, bool) {
+	channel = strings.ToLower(strings.TrimSpace(channel))
+	switch channel {
+	case "":
+		return "email", true
+	case "email", "in_app":
+		return channel, true
+	default:
+		return "", false
+	}
+}
+
+// CreateSavedSearch saves a candidate search under a name
+func CreateSavedSearch(c *gin.Context) {
+	companyIDVal, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	companyID, ok := companyIDVal.(string)
+	if !ok || companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, valid := normalizeAlertChannel(req.AlertChannel)
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert_channel. Must be: email or in_app"})
+		return
+	}
+
+	// Cursors are page positions, not part of the search itself
+	req.Criteria.Cursor = ""
+	criteriaJSON, err := json.Marshal(req.Criteria)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search criteria"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+	companyUUID, _ := uuid.Parse(companyID)
+
+	alertsEnabled := true
+	if req.AlertsEnabled != nil {
+		alertsEnabled = *req.AlertsEnabled
+	}
+
+	now := time.Now()
+	savedSearch := models.SavedSearch{
+		CompanyID:     companyUUID,
+		AdminID:       adminUUID,
+		Name:          strings.TrimSpace(req.Name),
+		Criteria:      string(criteriaJSON),
+		IsShared:      req.IsShared,
+		AlertsEnabled: alertsEnabled,
+		AlertChannel:  channel,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := config.DB.Create(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save search"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":      "Search saved successfully",
+		"saved_search": savedSearch,
+	})
+}
+
+// GetSavedSearches lists the admin's own saved searches plus those shared within the company
+func GetSavedSearches(c *gin.Context) {
+	companyIDVal, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	companyID, ok := companyIDVal.(string)
+	if !ok || companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	var searches []models.SavedSearch
+	if err := config.DB.Where("company_id = ? AND (admin_id = ? OR is_shared = true)", companyID, adminIDStr).
+		Preload("Admin").
+		Order("created_at DESC").
+		Find(&searches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch saved searches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"saved_searches": searches,
+		"count":          len(searches),
+	})
+}
+
+// GetSavedSearch returns a single saved search visible to the admin
+func GetSavedSearch(c *gin.Context) {
+	savedSearchID := c.Param("id")
+	companyIDVal, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	companyID, ok := companyIDVal.(string)
+	if !ok || companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	var savedSearch models.SavedSearch
+	if err := config.DB.Where("id = ? AND company_id = ? AND (admin_id = ? OR is_shared = true)", savedSearchID, companyID, adminIDStr).
+		Preload("Admin").
+		First(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"saved_search": savedSearch})
+}
+
+// UpdateSavedSearch updates a saved search (owner only)
+func UpdateSavedSearch(c *gin.Context) {
+	savedSearchID := c.Param("id")
+
+	var req SavedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	channel, valid := normalizeAlertChannel(req.AlertChannel)
+	if !valid {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert_channel. Must be: email or in_app"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	// Find saved search and verify ownership
+	var savedSearch models.SavedSearch
+	if err := config.DB.Where("id = ? AND admin_id = ?", savedSearchID, adminIDStr).First(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found or you don't have permission to edit it"})
+		return
+	}
+
+	req.Criteria.Cursor = ""
+	criteriaJSON, err := json.Marshal(req.Criteria)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid search criteria"})
+		return
+	}
+
+	savedSearch.Name = strings.TrimSpace(req.Name)
+	savedSearch.Criteria = string(criteriaJSON)
+	savedSearch.IsShared = req.IsShared
+	savedSearch.AlertChannel = channel
+	if req.AlertsEnabled != nil {
+		savedSearch.AlertsEnabled = *req.AlertsEnabled
+	}
+	savedSearch.UpdatedAt = time.Now()
+
+	if err := config.DB.Save(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":      "Saved search updated successfully",
+		"saved_search": savedSearch,
+	})
+}
+
+// DeleteSavedSearch deletes a saved search and its alert history (owner only)
+func DeleteSavedSearch(c *gin.Context) {
+	savedSearchID := c.Param("id")
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	var savedSearch models.SavedSearch
+	if err := config.DB.Where("id = ? AND admin_id = ?", savedSearchID, adminIDStr).First(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found or you don't have permission to delete it"})
+		return
+	}
+
+	if err := config.DB.Where("saved_search_id = ?", savedSearch.ID).Delete(&models.SavedSearchMatch{}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+	if err := config.DB.Delete(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete saved search"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Saved search deleted successfully"})
+}
+
+// GetSavedSearchMatches returns the candidates already alerted for a saved search
+func GetSavedSearchMatches(c *gin.Context) {
+	savedSearchID := c.Param("id")
+	companyIDVal, exists := c.Get("company_id")
+	if !exists {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	companyID, ok := companyIDVal.(string)
+	if !ok || companyID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid company ID"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+
+	var savedSearch models.SavedSearch
+	if err := config.DB.Where("id = ? AND company_id = ? AND (admin_id = ? OR is_shared = true)", savedSearchID, companyID, adminIDStr).
+		First(&savedSearch).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Saved search not found"})
+		return
+	}
+
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	// Only matches on jobs the admin may see, without the CV text and analysis
+	var matches []models.SavedSearchMatch
+	query := config.DB.Model(&models.SavedSearchMatch{}).
+		Joins("JOIN applications ON applications.id = saved_search_matches.application_id").
+		Where("saved_search_matches.saved_search_id = ?", savedSearch.ID)
+	if err := scope.Apply(query, "applications.job_id").
+		Select("saved_search_matches.*").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select(applicationListSelect(false, false))
+		}).
+		Preload("Application.Job", preloadListJob).
+		Order("saved_search_matches.created_at DESC").
+		Limit(200).
+		Find(&matches).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch matches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"matches": matches,
+		"count":   len(matches),
+	})
+}
@@ -0,0 +1,425 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"ats-backend/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AvailabilityWindowRequest is one availability window of a scheduling link
+type AvailabilityWindowRequest struct {
+	StartTime time.Time `json:"start_time" binding:"required"` // RFC 3339
+	EndTime   time.Time `json:"end_time" binding:"required"`   // RFC 3339
+}
+
+// CreateSchedulingLinkRequest for publishing availability for an interview round
+type CreateSchedulingLinkRequest struct {
+	RoundName       string                      `json:"round_name" binding:"required"`
+	InterviewerIDs  []string                    `json:"interviewer_ids" binding:"required,min=1"`
+	DurationMinutes int                         `json:"duration_minutes" binding:"required,min=5,max=480"`
+	BufferMinutes   int                         `json:"buffer_minutes" binding:"min=0,max=240"`
+	TimeZone        string                      `json:"time_zone"`
+	Location        string                      `json:"location"`
+	VideoLink       string                      `json:"video_link"`
+	Windows         []AvailabilityWindowRequest `json:"windows" binding:"required,min=1,max=50"`
+	ExpiresAt       *time.Time                  `json:"expires_at"` // Default: 7 days, or the end of the last window if sooner
+}
+
+// BookSchedulingSlotRequest for a candidate booking one of the offered slots
+type BookSchedulingSlotRequest struct {
+	StartTime time.Time `json:"start_time" binding:"required"` // Start of one of the listed slots
+}
+
+// schedulingLinkMaxDays is how far ahead availability can be published
+const schedulingLinkMaxDays = 90
+
+// CreateSchedulingLink publishes availability for an interview round and emails the candidate a booking link
+func CreateSchedulingLink(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req CreateSchedulingLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var application models.Application
+	// Verify application belongs to company (even if job is deleted)
+	err = config.DB.Table("applications").
+		Select("applications.*").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", c.Param("id"), companyID, companyID).
+		Preload("Job").
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+	if !requireOpenApplication(c, application) {
+		return
+	}
+
+	timeZone := strings.TrimSpace(req.TimeZone)
+	if timeZone == "" {
+		timeZone = "UTC"
+	} else if _, err := time.LoadLocation(timeZone); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown time_zone %q", timeZone)})
+		return
+	}
+
+	now := time.Now()
+	duration := time.Duration(req.DurationMinutes) * time.Minute
+	windows := []models.AvailabilityWindow{}
+	var lastWindowEnd time.Time
+	for _, window := range req.Windows {
+		if window.EndTime.Sub(window.StartTime) < duration {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Every window must be at least as long as duration_minutes"})
+			return
+		}
+		if !window.EndTime.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Windows must be in the future"})
+			return
+		}
+		if window.StartTime.After(now.AddDate(0, 0, schedulingLinkMaxDays)) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Windows can be at most %d days ahead", schedulingLinkMaxDays)})
+			return
+		}
+		windows = append(windows, models.AvailabilityWindow{StartTime: window.StartTime.UTC(), EndTime: window.EndTime.UTC()})
+		if window.EndTime.After(lastWindowEnd) {
+			lastWindowEnd = window.EndTime
+		}
+	}
+	sort.Slice(windows, func(i, j int) bool { return windows[i].StartTime.Before(windows[j].StartTime) })
+
+	expiresAt := now.AddDate(0, 0, 7)
+	if lastWindowEnd.Before(expiresAt) {
+		expiresAt = lastWindowEnd
+	}
+	if req.ExpiresAt != nil {
+		if !req.ExpiresAt.After(now) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "expires_at must be in the future"})
+			return
+		}
+		expiresAt = *req.ExpiresAt
+	}
+
+	interviewers, err := loadCompanyInterviewers(companyID, req.InterviewerIDs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	interviewerIDsJSON, _ := json.Marshal(adminIDsOf(interviewers))
+
+	token, err := utils.GenerateSecureToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling link"})
+		return
+	}
+
+	companyUUID, _ := uuid.Parse(companyID)
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	link := models.SchedulingLink{
+		CompanyID:       companyUUID,
+		ApplicationID:   application.ID,
+		Token:           token,
+		RoundName:       strings.TrimSpace(req.RoundName),
+		DurationMinutes: req.DurationMinutes,
+		BufferMinutes:   req.BufferMinutes,
+		TimeZone:        timeZone,
+		Location:        req.Location,
+		VideoLink:       req.VideoLink,
+		InterviewerIDs:  string(interviewerIDsJSON),
+		Status:          models.SchedulingLinkOpen,
+		ExpiresAt:       expiresAt.UTC(),
+		CreatedBy:       adminUUID,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+		Windows:         windows,
+	}
+
+	if err := config.DB.Create(&link).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create scheduling link"})
+		return
+	}
+
+	freeSlots, err := services.ComputeFreeSlots(link, now)
+	if err != nil {
+		log.Printf("ERROR: Failed to compute free slots for scheduling link %s: %v", link.ID, err)
+	}
+
+	jobTitle := "Unknown Job (Job Deleted)"
+	if application.JobID != nil && application.Job.ID != uuid.Nil {
+		jobTitle = application.Job.Title
+	}
+	schedulingURL := services.SchedulingLinkURL(link.Token)
+
+	go func() {
+		if err := services.SendSchedulingLinkEmail(application.Email, application.FullName, jobTitle, link.RoundName, schedulingURL, link.ExpiresAt); err != nil {
+			log.Printf("ERROR: Failed to send scheduling link to %s: %v", application.Email, err)
+		} else {
+			log.Printf("SUCCESS: Scheduling link sent to %s", application.Email)
+		}
+	}()
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":         "Scheduling link sent to the candidate",
+		"scheduling_link": link,
+		"url":             schedulingURL,
+		"free_slots":      len(freeSlots),
+	})
+}
+
+// GetSchedulingLinks lists the scheduling links of an application
+func GetSchedulingLinks(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var application models.Application
+	// Verify application belongs to company (even if job is deleted)
+	err = config.DB.Table("applications").
+		Select("applications.id, applications.job_id").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", c.Param("id"), companyID, companyID).
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	var links []models.SchedulingLink
+	if err := config.DB.Where("application_id = ? AND company_id = ?", application.ID, companyID).
+		Preload("Windows").
+		Order("created_at DESC").
+		Find(&links).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scheduling links"})
+		return
+	}
+
+	results := []gin.H{}
+	for _, link := range links {
+		results = append(results, gin.H{
+			"scheduling_link": link,
+			"url":             services.SchedulingLinkURL(link.Token),
+			"active":          services.IsSchedulingLinkActive(link),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"scheduling_links": results,
+		"count":            len(results),
+	})
+}
+
+// CancelSchedulingLink withdraws a scheduling link that hasn't been used yet
+func CancelSchedulingLink(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var link models.SchedulingLink
+	if err := config.DB.Select("id, application_id").
+		Where("id = ? AND company_id = ?", c.Param("id"), companyID).
+		First(&link).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Open scheduling link not found"})
+		return
+	}
+	var application models.Application
+	config.DB.Select("id, job_id").First(&application, "id = ?", link.ApplicationID)
+	if !inJobScope(c, application.JobID, "Open scheduling link not found") {
+		return
+	}
+
+	result := config.DB.Model(&models.SchedulingLink{}).
+		Where("id = ? AND status = ?", link.ID, models.SchedulingLinkOpen).
+		Updates(map[string]interface{}{
+			"status":     models.SchedulingLinkCancelled,
+			"updated_at": time.Now(),
+		})
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to cancel scheduling link"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Open scheduling link not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Scheduling link cancelled"})
+}
+
+// findSchedulingLinkByToken loads a scheduling link with its windows from the candidate's token.
+// The link must be for an application of the signed-in candidate's email.
+func findSchedulingLinkByToken(token, email string) (*models.SchedulingLink, error) {
+	var link models.SchedulingLink
+	if err := config.DB.Where("token = ?", token).
+		Where("application_id IN (SELECT id FROM applications WHERE LOWER(email) = ? AND deleted_at IS NULL)", email).
+		Preload("Windows").
+		First(&link).Error; err != nil {
+		return nil, err
+	}
+	return &link, nil
+}
+
+// GetSchedulingSlots lists the free interview slots of a scheduling link (candidate session and link token)
+func GetSchedulingSlots(c *gin.Context) {
+	link, err := findSchedulingLinkByToken(c.Param("token"), candidateEmail(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	var application models.Application
+	if err := config.DB.Preload("Job").First(&application, "id = ?", link.ApplicationID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	response := gin.H{
+		"round_name":       link.RoundName,
+		"duration_minutes": link.DurationMinutes,
+		"time_zone":        link.TimeZone,
+		"location":         link.Location,
+		"status":           link.Status,
+		"expires_at":       link.ExpiresAt,
+		"candidate_name":   application.FullName,
+		"job": gin.H{
+			"id":    application.Job.ID,
+			"title": application.Job.Title,
+		},
+		"slots": []services.TimeSlot{},
+	}
+
+	if !services.IsSchedulingLinkActive(*link) {
+		if link.Status == models.SchedulingLinkBooked && link.InterviewID != nil {
+			var interview models.Interview
+			if err := config.DB.First(&interview, "id = ?", *link.InterviewID).Error; err == nil {
+				response["booked_interview"] = candidateInterviewView(interview)
+			}
+		}
+		response["active"] = false
+		c.JSON(http.StatusOK, response)
+		return
+	}
+
+	slots, err := services.ComputeFreeSlots(*link, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load available slots"})
+		return
+	}
+	response["active"] = true
+	response["slots"] = slots
+	c.JSON(http.StatusOK, response)
+}
+
+// BookSchedulingSlot books an interview slot through a scheduling link (candidate session and link token)
+func BookSchedulingSlot(c *gin.Context) {
+	link, err := findSchedulingLinkByToken(c.Param("token"), candidateEmail(c))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scheduling link not found"})
+		return
+	}
+
+	var req BookSchedulingSlotRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	interview, err := services.BookSchedulingLink(link, req.StartTime)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrSchedulingLinkClosed):
+			c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		case errors.Is(err, services.ErrSlotUnavailable):
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		default:
+			log.Printf("ERROR: Failed to book scheduling link %s: %v", link.ID, err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to book interview"})
+		}
+		return
+	}
+
+	var application models.Application
+	if err := config.DB.Preload("Job").First(&application, "id = ?", link.ApplicationID).Error; err == nil {
+		services.LogInterviewScheduled(link.CompanyID, link.CreatedBy, interview.ID, application.FullName, interview.RoundName, interview.StartTime)
+		advanceToInterviewStage(&application, services.StatusChangeActor{Type: models.StatusActorCandidate}, link.CreatedBy,
+			"Candidate booked "+interview.RoundName)
+
+		// Let the recruiter and interviewers know
+		notified := map[uuid.UUID]bool{}
+		recipients := append([]uuid.UUID{link.CreatedBy}, interviewSlotAdminIDs(interview.Slots)...)
+		for _, adminID := range recipients {
+			if notified[adminID] {
+				continue
+			}
+			notified[adminID] = true
+			if err := services.CreateNotification(link.CompanyID, adminID, "interview_booked",
+				"Interview booked: "+interview.RoundName,
+				fmt.Sprintf("%s booked %s", application.FullName, services.FormatInterviewTime(*interview)),
+				"interview", &interview.ID); err != nil {
+				log.Printf("ERROR: Failed to notify admin %s about booking: %v", adminID, err)
+			}
+		}
+	}
+
+	go services.SendInterviewInvitations(interview.ID, "scheduled")
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Interview booked. A calendar invitation is on its way to your inbox.",
+		"interview": candidateInterviewView(*interview),
+	})
+}
+
+// interviewSlotAdminIDs returns the interviewers of the given bookings
+func interviewSlotAdminIDs(slots []models.InterviewSlot) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, slot := range slots {
+		ids = append(ids, slot.AdminID)
+	}
+	return ids
+}
+
+// candidateInterviewView is the part of an interview a candidate gets to see
+func candidateInterviewView(interview models.Interview) gin.H {
+	return gin.H{
+		"id":         interview.ID,
+		"round_name": interview.RoundName,
+		"start_time": interview.StartTime,
+		"end_time":   interview.EndTime,
+		"time_zone":  interview.TimeZone,
+		"when":       services.FormatInterviewTime(interview),
+		"location":   interview.Location,
+		"video_link": interview.VideoLink,
+		"status":     interview.Status,
+	}
+}
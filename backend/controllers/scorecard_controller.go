@@ -0,0 +1,424 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ScorecardCompetencyRequest is one competency of a scorecard template request
+type ScorecardCompetencyRequest struct {
+	Name            string `json:"name" binding:"required"`
+	Description     string `json:"description"`
+	CommentRequired bool   `json:"comment_required"`
+}
+
+// ScorecardTemplateRequest for creating or replacing a job's scorecard template
+type ScorecardTemplateRequest struct {
+	Name            string                       `json:"name"`
+	RatingMin       int                          `json:"rating_min"` // Default 1
+	RatingMax       int                          `json:"rating_max"` // Default 5
+	RequireComments bool                         `json:"require_comments"`
+	Competencies    []ScorecardCompetencyRequest `json:"competencies" binding:"required,min=1"`
+}
+
+// ScorecardRatingRequest is the rating of one competency
+type ScorecardRatingRequest struct {
+	CompetencyID string `json:"competency_id" binding:"required"`
+	Rating       int    `json:"rating" binding:"required"`
+	Comment      string `json:"comment"`
+}
+
+// SubmitScorecardRequest for submitting an interviewer's scorecard
+type SubmitScorecardRequest struct {
+	Recommendation string                   `json:"recommendation" binding:"required"` // strong_no, no, yes, strong_yes
+	OverallComment string                   `json:"overall_comment"`
+	Ratings        []ScorecardRatingRequest `json:"ratings" binding:"required,min=1"`
+}
+
+// findJobScorecardTemplate loads the scorecard template of a job with its competencies in order
+func findJobScorecardTemplate(jobID uuid.UUID) (*models.ScorecardTemplate, error) {
+	var template models.ScorecardTemplate
+	err := config.DB.Where("job_id = ?", jobID).
+		Preload("Competencies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&template).Error
+	if err != nil {
+		return nil, err
+	}
+	return &template, nil
+}
+
+// buildScorecardRatings validates the submitted ratings against the template.
+// Every competency must be rated exactly once, within the template's scale.
+func buildScorecardRatings(template *models.ScorecardTemplate, requested []ScorecardRatingRequest) ([]models.ScorecardRating, error) {
+	byID := map[string]ScorecardRatingRequest{}
+	for _, rating := range requested {
+		if _, duplicate := byID[rating.CompetencyID]; duplicate {
+			return nil, fmt.Errorf("competency %s is rated more than once", rating.CompetencyID)
+		}
+		byID[rating.CompetencyID] = rating
+	}
+
+	ratings := []models.ScorecardRating{}
+	for _, competency := range template.Competencies {
+		rating, ok := byID[competency.ID.String()]
+		if !ok {
+			return nil, fmt.Errorf("missing rating for %q", competency.Name)
+		}
+		delete(byID, competency.ID.String())
+
+		if rating.Rating < template.RatingMin || rating.Rating > template.RatingMax {
+			return nil, fmt.Errorf("rating for %q must be between %d and %d", competency.Name, template.RatingMin, template.RatingMax)
+		}
+		comment := strings.TrimSpace(rating.Comment)
+		if comment == "" && (template.RequireComments || competency.CommentRequired) {
+			return nil, fmt.Errorf("a comment is required for %q", competency.Name)
+		}
+		ratings = append(ratings, models.ScorecardRating{
+			CompetencyID:   competency.ID,
+			CompetencyName: competency.Name,
+			Rating:         rating.Rating,
+			Comment:        comment,
+		})
+	}
+	if len(byID) > 0 {
+		return nil, errors.New("ratings include competencies that aren't on this job's scorecard")
+	}
+	return ratings, nil
+}
+
+// GetJobScorecardTemplate returns the scorecard template of a job
+func GetJobScorecardTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+
+	template, err := findJobScorecardTemplate(job.ID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "This job has no scorecard yet"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"template": template})
+}
+
+// SetJobScorecardTemplate creates or replaces the scorecard template of a job.
+// Submitted scorecards keep their ratings; they store the competency names they were rated on.
+func SetJobScorecardTemplate(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req ScorecardTemplateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if req.RatingMin == 0 && req.RatingMax == 0 {
+		req.RatingMin, req.RatingMax = 1, 5
+	}
+	if req.RatingMin < 1 || req.RatingMax > 10 || req.RatingMin >= req.RatingMax {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The rating scale must go from a lower to a higher number between 1 and 10"})
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Where("id = ? AND company_id = ?", c.Param("id"), companyID).First(&job).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found"})
+		return
+	}
+	if !inJobScope(c, &job.ID, "Job not found") {
+		return
+	}
+
+	competencies := []models.ScorecardCompetency{}
+	seen := map[string]bool{}
+	for i, competency := range req.Competencies {
+		name := strings.TrimSpace(competency.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Every competency needs a name"})
+			return
+		}
+		if seen[strings.ToLower(name)] {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Competency %q is listed twice", name)})
+			return
+		}
+		seen[strings.ToLower(name)] = true
+		competencies = append(competencies, models.ScorecardCompetency{
+			Name:            name,
+			Description:     strings.TrimSpace(competency.Description),
+			CommentRequired: competency.CommentRequired,
+			Position:        i,
+		})
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = job.Title + " Scorecard"
+	}
+
+	var template models.ScorecardTemplate
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("job_id = ?", job.ID).First(&template).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		template.CompanyID = job.CompanyID
+		template.JobID = job.ID
+		template.Name = name
+		template.RatingMin = req.RatingMin
+		template.RatingMax = req.RatingMax
+		template.RequireComments = req.RequireComments
+		if err := tx.Save(&template).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("template_id = ?", template.ID).Delete(&models.ScorecardCompetency{}).Error; err != nil {
+			return err
+		}
+		for i := range competencies {
+			competencies[i].TemplateID = template.ID
+		}
+		return tx.Create(&competencies).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to save scorecard template"})
+		return
+	}
+	template.Competencies = competencies
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":  "Scorecard template saved successfully",
+		"template": template,
+	})
+}
+
+// SubmitScorecard records the caller's scorecard for an interview they were an interviewer on.
+// A scorecard can only be submitted once per interviewer and interview.
+func SubmitScorecard(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var req SubmitScorecardRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.Recommendation = strings.ToLower(strings.TrimSpace(req.Recommendation))
+	if !services.IsValidRecommendation(req.Recommendation) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid recommendation. Use strong_no, no, yes or strong_yes"})
+		return
+	}
+
+	interview, err := findCompanyInterview(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+	if !inJobScope(c, interview.JobID, "Interview not found") {
+		return
+	}
+	if interview.Status == models.InterviewStatusCancelled || interview.Status == models.InterviewStatusNoShow {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Scorecards can't be submitted for a " + interview.Status + " interview"})
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	isInterviewer := false
+	for _, slot := range interview.Slots {
+		if slot.AdminID == adminUUID {
+			isInterviewer = true
+			break
+		}
+	}
+	if !isInterviewer {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Only interviewers of this interview can submit a scorecard"})
+		return
+	}
+
+	if interview.JobID == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "The job of this interview no longer exists"})
+		return
+	}
+	template, err := findJobScorecardTemplate(*interview.JobID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This job has no scorecard template yet"})
+		return
+	}
+
+	ratings, err := buildScorecardRatings(template, req.Ratings)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var existing int64
+	config.DB.Model(&models.Scorecard{}).Where("interview_id = ? AND admin_id = ?", interview.ID, adminUUID).Count(&existing)
+	if existing > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already submitted a scorecard for this interview"})
+		return
+	}
+
+	scorecard := models.Scorecard{
+		CompanyID:      interview.CompanyID,
+		InterviewID:    interview.ID,
+		AdminID:        adminUUID,
+		ApplicationID:  interview.ApplicationID,
+		TemplateID:     template.ID,
+		Recommendation: req.Recommendation,
+		OverallComment: strings.TrimSpace(req.OverallComment),
+		SubmittedAt:    time.Now(),
+		Ratings:        ratings,
+	}
+	if err := config.DB.Create(&scorecard).Error; err != nil {
+		// The unique index catches a concurrent double submit
+		if strings.Contains(err.Error(), "idx_scorecard_interview_admin") {
+			c.JSON(http.StatusConflict, gin.H{"error": "You have already submitted a scorecard for this interview"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to submit scorecard"})
+		return
+	}
+
+	services.LogScorecardSubmitted(interview.CompanyID, adminUUID, scorecard.ID, interview.Application.FullName, interview.RoundName)
+
+	// Let the organizer know feedback is in
+	if interview.CreatedBy != nil && *interview.CreatedBy != adminUUID {
+		if err := services.CreateNotification(interview.CompanyID, *interview.CreatedBy, "scorecard_submitted",
+			"Scorecard submitted: "+interview.RoundName,
+			fmt.Sprintf("New interview feedback for %s", interview.Application.FullName),
+			"interview", &interview.ID); err != nil {
+			log.Printf("ERROR: Failed to notify organizer of interview %s: %v", interview.ID, err)
+		}
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"message":   "Scorecard submitted successfully",
+		"scorecard": scorecard,
+	})
+}
+
+// GetInterviewScorecards lists the scorecards of an interview.
+// Interviewers who haven't submitted theirs yet only see how many were submitted.
+func GetInterviewScorecards(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	interview, err := findCompanyInterview(c.Param("id"), companyID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Interview not found"})
+		return
+	}
+	if !inJobScope(c, interview.JobID, "Interview not found") {
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	var scorecards []models.Scorecard
+	if err := config.DB.Where("interview_id = ?", interview.ID).
+		Preload("Ratings").
+		Preload("Admin").
+		Order("submitted_at ASC").
+		Find(&scorecards).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scorecards"})
+		return
+	}
+
+	pending, err := services.PendingScorecardInterviews(interview.ApplicationID, adminUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scorecards"})
+		return
+	}
+	if len(pending) > 0 {
+		c.JSON(http.StatusOK, gin.H{
+			"hidden":             true,
+			"submitted_count":    len(scorecards),
+			"pending_interviews": pending,
+			"message":            "Submit your own scorecard to see the other interviewers' feedback",
+		})
+		return
+	}
+
+	aggregate := services.BuildScorecardAggregate(scorecards)
+	c.JSON(http.StatusOK, gin.H{
+		"hidden":          false,
+		"submitted_count": len(scorecards),
+		"scorecards":      scorecards,
+		"aggregate":       aggregate,
+	})
+}
+
+// GetApplicationScorecards returns all scorecards of an application with the aggregated view
+func GetApplicationScorecards(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+
+	var application models.Application
+	// Verify application belongs to company (even if job is deleted)
+	err = config.DB.Table("applications").
+		Select("applications.id, applications.job_id").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.id = ? AND (applications.company_id = ? OR jobs.company_id = ?)", c.Param("id"), companyID, companyID).
+		First(&application).Error
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found"})
+		return
+	}
+	if !applicationInJobScope(c, application) {
+		return
+	}
+
+	adminIDVal, _ := c.Get("admin_id")
+	adminIDStr, _ := adminIDVal.(string)
+	adminUUID, _ := uuid.Parse(adminIDStr)
+
+	result, err := services.GetApplicationScorecards(application.ID, adminUUID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch scorecards"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"ats-backend/config"
 	"ats-backend/models"
+	"ats-backend/services"
 	"ats-backend/utils"
 	"net/http"
 
@@ -60,9 +61,12 @@ func GetSuperAdminStats(c *gin.Context) {
 		TotalJobs           int64 `json:"total_jobs"`
 		OpenJobs            int64 `json:"open_jobs"`
 		TotalApplications   int64 `json:"total_applications"`
-		PendingApplications int64 `json:"pending_applications"`
-		ShortlistedApplications int64 `json:"shortlisted_applications"`
+		ActiveApplications  int64 `json:"active_applications"` // In an active stage of their job's pipeline
+		HiredApplications   int64 `json:"hired_applications"`  // In a hired stage of their job's pipeline
 		TotalAdmins         int64 `json:"total_admins"`
+		TotalOpenings       int64 `json:"total_openings"`  // Positions of jobs with a headcount
+		FilledOpenings      int64 `json:"filled_openings"` // Of those, the positions filled
+		TotalHires          int64 `json:"total_hires"`
 	}
 
 	// Get company stats
@@ -73,10 +77,19 @@ func GetSuperAdminStats(c *gin.Context) {
 	config.DB.Model(&models.Job{}).Count(&stats.TotalJobs)
 	config.DB.Model(&models.Job{}).Where("status = ?", "open").Count(&stats.OpenJobs)
 
+	// Get openings versus filled
+	if headcount, err := services.SummarizeHeadcount(config.DB); err == nil {
+		stats.TotalOpenings = headcount.Openings
+		stats.FilledOpenings = headcount.Filled
+		stats.TotalHires = headcount.Hired
+	}
+
 	// Get application stats
 	config.DB.Model(&models.Application{}).Count(&stats.TotalApplications)
-	config.DB.Model(&models.Application{}).Where("status = ?", "pending").Count(&stats.PendingApplications)
-	config.DB.Model(&models.Application{}).Where("status = ?", "shortlisted").Count(&stats.ShortlistedApplications)
+	if byType, err := services.CountApplicationsByStageType(config.DB); err == nil {
+		stats.ActiveApplications = byType[models.StageTypeActive]
+		stats.HiredApplications = byType[models.StageTypeHired]
+	}
 
 	// Get admin stats
 	config.DB.Model(&models.Admin{}).Count(&stats.TotalAdmins)
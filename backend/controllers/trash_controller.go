@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/services"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TrashedJob is a job in the trash with the date it will be purged
+type TrashedJob struct {
+	models.Job
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// TrashedApplication is an application in the trash with the date it will be purged
+type TrashedApplication struct {
+	models.Application
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+// withTrashedJob preloads an application's job even when the job is in the trash too
+func withTrashedJob(db *gorm.DB) *gorm.DB {
+	return db.Preload("Job", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	})
+}
+
+// GetTrash lists the company's deleted jobs and applications that haven't been purged yet.
+// ?type=jobs or ?type=applications limits the listing to one of them.
+func GetTrash(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	listType := c.Query("type")
+	if listType != "" && listType != "jobs" && listType != "applications" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid type. Must be: jobs or applications"})
+		return
+	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	trashedJobs := []TrashedJob{}
+	if listType != "applications" {
+		var jobs []models.Job
+		if err := scope.Apply(config.DB.Unscoped(), "id").
+			Where("company_id = ? AND deleted_at IS NOT NULL", companyID).
+			Order("deleted_at DESC").
+			Find(&jobs).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+			return
+		}
+		for _, job := range jobs {
+			trashedJobs = append(trashedJobs, TrashedJob{Job: job, PurgeAt: services.PurgeDate(job.DeletedAt.Time)})
+		}
+	}
+
+	trashedApplications := []TrashedApplication{}
+	if listType != "jobs" {
+		var applications []models.Application
+		if err := scope.Apply(config.DB.Unscoped(), "job_id").
+			Where("company_id = ? AND deleted_at IS NOT NULL", companyID).
+			Scopes(withTrashedJob).
+			Order("deleted_at DESC").
+			Find(&applications).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch trash"})
+			return
+		}
+		for _, application := range applications {
+			trashedApplications = append(trashedApplications, TrashedApplication{
+				Application: application,
+				PurgeAt:     services.PurgeDate(application.DeletedAt.Time),
+			})
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"jobs":           trashedJobs,
+		"applications":   trashedApplications,
+		"retention_days": int(services.TrashRetention().Hours() / 24),
+	})
+}
+
+// RestoreTrashedJob takes a deleted job of the company out of the trash
+func RestoreTrashedJob(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	var job models.Job
+	if err := config.DB.Unscoped().
+		Where("id = ? AND company_id = ? AND deleted_at IS NOT NULL", c.Param("id"), companyID).
+		First(&job).Error; err != nil || !scope.Allows(&job.ID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Job not found in the trash"})
+		return
+	}
+
+	if err := services.RestoreJob(&job); err != nil {
+		log.Printf("ERROR: Failed to restore job %s: %v", job.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore job"})
+		return
+	}
+
+	services.LogJobRestored(job.CompanyID, currentAdminID(c), job.ID, job.Title)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Job restored",
+		"job":     job,
+	})
+}
+
+// RestoreTrashedApplication takes a deleted application of the company out of the trash
+func RestoreTrashedApplication(c *gin.Context) {
+	companyID, err := getCompanyID(c)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Company ID not found in token"})
+		return
+	}
+	scope, ok := loadJobScope(c)
+	if !ok {
+		return
+	}
+
+	var application models.Application
+	if err := config.DB.Unscoped().
+		Where("id = ? AND company_id = ? AND deleted_at IS NOT NULL", c.Param("id"), companyID).
+		Scopes(withTrashedJob).
+		First(&application).Error; err != nil || !scope.Allows(application.JobID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Application not found in the trash"})
+		return
+	}
+
+	if err := services.RestoreApplication(&application); err != nil {
+		log.Printf("ERROR: Failed to restore application %s: %v", application.ID, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore application"})
+		return
+	}
+
+	services.LogApplicationRestored(application.CompanyID, currentAdminID(c), application.ID, application.FullName)
+
+	message := "Application restored"
+	if application.Job.DeletedAt.Valid {
+		message = "Application restored. Its job is still in the trash, restore it to see the application in the job's pipeline"
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"message":     message,
+		"application": application,
+	})
+}
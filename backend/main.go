@@ -31,6 +31,12 @@ func main() {
 	// Initialize database
 	config.InitDB()
 
+	// Give companies and jobs created before careers slugs existed their slug
+	services.BackfillSlugs()
+
+	// Count the hires of jobs from before hires were counted
+	services.BackfillHiredCounts()
+
 	// Initialize Supabase Storage buckets (optional - can be created manually)
 	if err := services.CreateBucketIfNotExists("resumes", true); err != nil {
 		log.Printf("Warning: Failed to create resumes bucket: %v", err)
@@ -50,6 +56,11 @@ func main() {
 	// Setup routes
 	routes.SetupRoutes(router)
 
+	// Start background jobs (set SCHEDULER_ENABLED=false on secondary instances)
+	if config.GetEnv("SCHEDULER_ENABLED", "true") != "false" {
+		services.StartScheduler()
+	}
+
 	// Start server
 	port := config.GetEnv("PORT", "8080")
 	log.Printf("Server starting on port %s", port)
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"ats-backend/utils"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CandidateAuthMiddleware requires a candidate session token, obtained through a magic link,
+// and puts the candidate's email in the context
+func CandidateAuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header required"})
+			c.Abort()
+			return
+		}
+
+		// Extract token from "Bearer <token>"
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || parts[0] != "Bearer" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid authorization header"})
+			c.Abort()
+			return
+		}
+
+		claims, err := utils.VerifyCandidateJWT(parts[1], utils.CandidateSessionPurpose)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired session, please request a new sign-in link"})
+			c.Abort()
+			return
+		}
+
+		c.Set("candidate_email", claims.CandidateEmail)
+		c.Next()
+	}
+}
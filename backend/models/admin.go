@@ -13,6 +13,7 @@ type Admin struct {
 	Email        string    `gorm:"size:255;unique;not null" json:"email"`
 	PasswordHash string    `gorm:"size:255;not null" json:"-"`
 	Role         string    `gorm:"size:50;default:'admin'" json:"role"`
+	IsApprover   bool      `gorm:"default:false" json:"is_approver"` // Approver role: may approve job requisitions
 	CreatedAt    time.Time `json:"created_at"`
 	
 	// Relations
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Application struct {
@@ -23,6 +24,7 @@ type Application struct {
 	Score              int        `gorm:"default:0" json:"score"` // AI match score 0-100
 	AnalysisResult     *string    `gorm:"type:jsonb" json:"analysis_result,omitempty"` // AI analysis JSON
 	ParsedCVText       *string    `gorm:"type:text" json:"parsed_cv_text,omitempty"` // Extracted CV text for searching
+	CVParsedAt         *time.Time `json:"cv_parsed_at,omitempty"` // When ParsedCVText was last extracted
 	AppliedAt          time.Time  `json:"applied_at"`
 	ReviewedAt         *time.Time `json:"reviewed_at,omitempty"`
 	ReviewedBy         *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`
@@ -39,8 +41,26 @@ type Application struct {
 	InTalentPool       bool       `gorm:"default:false" json:"in_talent_pool"` // Marked for future opportunities
 	TalentPoolAddedAt  *time.Time `json:"talent_pool_added_at,omitempty"` // When added to talent pool
 	TalentPoolAddedBy  *uuid.UUID `gorm:"type:uuid" json:"talent_pool_added_by,omitempty"` // Admin who added to talent pool
+	Tags               string     `gorm:"type:jsonb" json:"tags,omitempty"` // JSON array of lowercase recruiter tags
+
+	// Screening
+	ScreeningResult    string     `gorm:"size:20" json:"screening_result,omitempty"` // passed, flagged, knocked_out (empty without a form)
+
+	// Privacy
+	AnonymisedAt       *time.Time `json:"anonymised_at,omitempty"` // Personal data erased; kept for reporting only
+	PrivacyNoticeID    *uuid.UUID `gorm:"type:uuid" json:"privacy_notice_id,omitempty"` // Notice accepted when applying
+	PrivacyNoticeVersion int      `gorm:"default:0" json:"privacy_notice_version,omitempty"`
+	ConsentedAt        *time.Time `json:"consented_at,omitempty"` // When the notice was accepted or the opt-ins last changed
+	ConsentIP          string     `gorm:"size:45" json:"consent_ip,omitempty"`
+	TalentPoolConsent  bool       `gorm:"default:false" json:"talent_pool_consent"` // May be kept in the talent pool for future jobs
+	MarketingConsent   bool       `gorm:"default:false" json:"marketing_consent"`   // May receive job alerts and nurture emails
+
+	// Trash
+	DeletedAt          gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"` // In the trash since; purged after the retention period
+	DeletedBy          *uuid.UUID `gorm:"type:uuid" json:"deleted_by,omitempty"`
 
 	// Relations
-	Job Job `gorm:"foreignKey:JobID" json:"job,omitempty"`
+	Job     Job                 `gorm:"foreignKey:JobID" json:"job,omitempty"`
+	Answers []ApplicationAnswer `gorm:"foreignKey:ApplicationID" json:"answers,omitempty"`
 }
 
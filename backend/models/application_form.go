@@ -0,0 +1,80 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Application form question types
+const (
+	QuestionTypeText         = "text"
+	QuestionTypeNumber       = "number"
+	QuestionTypeSingleChoice = "single_choice"
+	QuestionTypeMultiChoice  = "multi_choice"
+	QuestionTypeYesNo        = "yes_no"
+	QuestionTypeDate         = "date"
+	QuestionTypeFile         = "file" // Answered with the URL returned by the upload endpoints
+)
+
+// What happens when a knockout question gets a disqualifying answer
+const (
+	KnockoutActionReject = "reject" // Application goes straight to the rejected stage
+	KnockoutActionFlag   = "flag"   // Application is flagged for the recruiter
+)
+
+// Screening results of an application, based on its knockout answers
+const (
+	ScreeningPassed     = "passed"
+	ScreeningFlagged    = "flagged"
+	ScreeningKnockedOut = "knocked_out"
+)
+
+// ApplicationForm holds the screening questions candidates answer when applying to a job
+type ApplicationForm struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID uuid.UUID `gorm:"type:uuid;not null;index" json:"company_id"`
+	JobID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"job_id"` // One form per job
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	Questions []FormQuestion `gorm:"foreignKey:FormID" json:"questions"`
+}
+
+// FormQuestion is one question of an application form
+type FormQuestion struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	FormID    uuid.UUID `gorm:"type:uuid;not null;index" json:"form_id"`
+	Key       string    `gorm:"size:100;not null" json:"key"` // Stable identifier answers are submitted and filtered by
+	Label     string    `gorm:"type:text;not null" json:"label"`
+	HelpText  string    `gorm:"type:text" json:"help_text,omitempty"`
+	Type      string    `gorm:"size:20;not null" json:"type"` // text, number, single_choice, multi_choice, yes_no, date, file
+	Required  bool      `gorm:"default:false" json:"required"`
+	Options   string    `gorm:"type:jsonb" json:"options,omitempty"`   // JSON array of choices for single/multi choice
+	MinValue  *float64  `json:"min_value,omitempty"`                   // Number questions
+	MaxValue  *float64  `json:"max_value,omitempty"`                   // Number questions
+	MaxLength int       `gorm:"default:0" json:"max_length,omitempty"` // Text questions, 0 = no limit
+	Position  int       `gorm:"not null" json:"position"`
+
+	// Knockout rule: any of these answers, or a number outside the bounds, triggers the action
+	KnockoutAction string   `gorm:"size:20" json:"knockout_action,omitempty"`    // reject, flag or empty
+	KnockoutValues string   `gorm:"type:jsonb" json:"knockout_values,omitempty"` // JSON array of disqualifying answers
+	KnockoutBelow  *float64 `json:"knockout_below,omitempty"`
+	KnockoutAbove  *float64 `json:"knockout_above,omitempty"`
+}
+
+// ApplicationAnswer is a candidate's answer to one form question.
+// The question's key, label and type are copied so answers survive form changes.
+type ApplicationAnswer struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ApplicationID uuid.UUID `gorm:"type:uuid;not null;index" json:"application_id"`
+	QuestionID    uuid.UUID `gorm:"type:uuid;not null" json:"question_id"`
+	QuestionKey   string    `gorm:"size:100;not null;index" json:"question_key"`
+	QuestionLabel string    `gorm:"type:text" json:"question_label"`
+	Type          string    `gorm:"size:20;not null" json:"type"`
+	Value         string    `gorm:"type:text" json:"value"`           // Normalized; multi choice answers are a JSON array
+	NumberValue   *float64  `json:"number_value,omitempty"`           // Set for number questions, for range filters
+	KnockedOut    bool      `gorm:"default:false" json:"knocked_out"` // This answer triggered the knockout rule
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Actor types of an application status change
+const (
+	StatusActorAdmin     = "admin"
+	StatusActorCandidate = "candidate"
+	StatusActorSystem    = "system"
+)
+
+// ApplicationStatusChange is one entry of an application's stage history
+type ApplicationStatusChange struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ApplicationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"application_id"`
+	CompanyID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	FromStage     string     `gorm:"size:50" json:"from_stage"` // Empty for the initial stage
+	ToStage       string     `gorm:"size:50;not null" json:"to_stage"`
+	ActorType     string     `gorm:"size:20;not null" json:"actor_type"` // admin, candidate, system
+	ActorID       *uuid.UUID `gorm:"type:uuid" json:"actor_id,omitempty"`
+	Reason        string     `gorm:"type:text" json:"reason,omitempty"`
+	CreatedAt     time.Time  `gorm:"index" json:"created_at"`
+
+	// Relations
+	Actor *Admin `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CandidateMagicLink is a sign-in link emailed to a candidate. The signed token in the link carries
+// TokenID, so each link can be exchanged for a portal session only once.
+type CandidateMagicLink struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	Email     string     `gorm:"size:255;not null;index" json:"email"` // Lowercased
+	TokenID   string     `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"`
+	RequestIP string     `gorm:"size:45" json:"request_ip"`
+	CreatedAt time.Time  `json:"created_at"`
+}
@@ -15,6 +15,15 @@ type Company struct {
 	EmbedDomain       *string   `gorm:"size:255" json:"embed_domain,omitempty"` // Allowed domain for embedding
 	SubscriptionStatus string   `gorm:"size:50;default:'trial'" json:"subscription_status"`
 	SubscriptionTier  string   `gorm:"size:50;default:'starter'" json:"subscription_tier"`
+	ReapplyCooldownDays int    `gorm:"default:0" json:"reapply_cooldown_days"` // Days before a closed candidate may apply to the same job again
+	JobClosedApplicantPolicy string `gorm:"size:20;default:'none'" json:"job_closed_applicant_policy"` // none or notify: email pending applicants when a job closes automatically
+	RequireJobApproval bool  `gorm:"default:false" json:"require_job_approval"` // New jobs start as requisitions that must be approved before going live
+	RequisitionApproverIDs *string `gorm:"type:jsonb" json:"requisition_approver_ids,omitempty"` // Default approval chain, JSON array of admin IDs in order
+	Slug              *string   `gorm:"size:100;uniqueIndex" json:"slug,omitempty"` // Careers site URL, e.g. /careers/acme
+	LogoURL           string    `gorm:"size:500" json:"logo_url"`
+	PrimaryColor      string    `gorm:"size:7" json:"primary_color"` // Hex colour, e.g. #1d4ed8
+	AccentColor       string    `gorm:"size:7" json:"accent_color"`
+	AboutText         string    `gorm:"type:text" json:"about_text"` // Shown above the jobs on the careers page
 	CreatedAt         time.Time `json:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at"`
 }
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Export job statuses
+const (
+	ExportStatusPending    = "pending"
+	ExportStatusProcessing = "processing"
+	ExportStatusCompleted  = "completed"
+	ExportStatusFailed     = "failed"
+)
+
+// ExportJob is a large spreadsheet export built in the background.
+// The finished file is kept for a few days and downloaded through the API, since it holds candidate data.
+type ExportJob struct {
+	ID          uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	AdminID     uuid.UUID  `gorm:"type:uuid;not null" json:"admin_id"` // Who requested the export
+	Source      string     `gorm:"size:20;not null" json:"source"`     // applications or search
+	Format      string     `gorm:"size:10;not null" json:"format"`     // csv or xlsx
+	Columns     string     `gorm:"type:jsonb" json:"columns"`          // JSON array of column keys
+	Status      string     `gorm:"size:20;not null;default:'pending'" json:"status"`
+	RowCount    int        `gorm:"default:0" json:"row_count"`
+	Filename    string     `gorm:"size:255" json:"filename,omitempty"`
+	Data        []byte     `gorm:"type:bytea" json:"-"` // The finished file
+	Error       string     `gorm:"type:text" json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"` // The file is purged after this
+}
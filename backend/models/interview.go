@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Interview statuses
+const (
+	InterviewStatusScheduled = "scheduled"
+	InterviewStatusCompleted = "completed"
+	InterviewStatusCancelled = "cancelled"
+	InterviewStatusNoShow    = "no_show"
+)
+
+// Interview is a scheduled interview round for an application
+type Interview struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	ApplicationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"application_id"`
+	JobID         *uuid.UUID `gorm:"type:uuid" json:"job_id,omitempty"`
+	RoundName     string     `gorm:"size:255;not null" json:"round_name"` // e.g. "Tech Interview"
+	StartTime     time.Time  `gorm:"not null;index" json:"start_time"`
+	EndTime       time.Time  `gorm:"not null" json:"end_time"`
+	TimeZone      string     `gorm:"size:64;default:'UTC'" json:"time_zone"` // IANA name, e.g. "Europe/Berlin"
+	Location      string     `gorm:"size:255" json:"location,omitempty"`
+	VideoLink     string     `gorm:"type:text" json:"video_link,omitempty"`
+	Notes         string     `gorm:"type:text" json:"notes,omitempty"`
+	Status        string     `gorm:"size:20;default:'scheduled'" json:"status"` // scheduled, completed, cancelled, no_show
+	CalendarUID   string     `gorm:"size:255;uniqueIndex" json:"calendar_uid"`  // ICS UID, kept across updates
+	Sequence      int        `gorm:"default:0" json:"sequence"`                 // ICS SEQUENCE, bumped on every update
+	CreatedBy     *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	Application Application     `gorm:"foreignKey:ApplicationID" json:"application,omitempty"`
+	Slots       []InterviewSlot `gorm:"foreignKey:InterviewID" json:"slots,omitempty"`
+}
+
+// InterviewSlot is one interviewer's booking for an interview; used to detect double bookings
+type InterviewSlot struct {
+	ID          uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	InterviewID uuid.UUID `gorm:"type:uuid;not null;index" json:"interview_id"`
+	AdminID     uuid.UUID `gorm:"type:uuid;not null;index" json:"admin_id"` // Interviewer
+	StartTime   time.Time `gorm:"not null" json:"start_time"`
+	EndTime     time.Time `gorm:"not null" json:"end_time"`
+	Status      string    `gorm:"size:20;default:'scheduled'" json:"status"` // Mirrors the interview status
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relations
+	Admin Admin `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 // DateOnly is a custom type for date-only values (YYYY-MM-DD)
@@ -68,21 +69,41 @@ func (d *DateOnly) Scan(value interface{}) error {
 
 type Job struct {
 	ID               uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	CompanyID        uuid.UUID  `gorm:"type:uuid;not null" json:"company_id"`
+	CompanyID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_jobs_company_slug,priority:1" json:"company_id"`
 	Title            string     `gorm:"size:255;not null" json:"title"`
+	Slug             *string    `gorm:"size:120;uniqueIndex:idx_jobs_company_slug,priority:2" json:"slug,omitempty"` // Unique within the company, kept when the title changes
 	Description      string     `gorm:"type:text;not null" json:"description"`
 	Requirements     string     `gorm:"type:text" json:"requirements"`
 	Location         string     `gorm:"size:255" json:"location"`
 	JobType          string     `gorm:"size:50" json:"job_type"`
+	Department       string     `gorm:"size:100" json:"department"`
 	SalaryRange      string     `gorm:"size:100" json:"salary_range"`
 	Deadline         DateOnly   `gorm:"type:date;not null" json:"deadline"`
-	Status           string     `gorm:"size:50;default:'open'" json:"status"`
+	Status           string     `gorm:"size:50;default:'open'" json:"status"` // draft, scheduled, open or closed
+	PublishAt        *time.Time `json:"publish_at,omitempty"`                   // A scheduled job opens at this time
+	Headcount        int        `gorm:"default:0" json:"headcount"`             // Positions to fill, 0 = not limited
+	HiredCount       int        `gorm:"default:0" json:"hired_count"`           // Candidates moved to a hired stage
+	KeepOpenWhenFilled bool     `gorm:"default:false" json:"keep_open_when_filled"` // Don't close automatically once HiredCount reaches Headcount
+	FilledMessage    string     `gorm:"type:text" json:"filled_message,omitempty"` // Emailed to remaining active candidates when the job closes filled; empty uses the default
+	CreatedBy        *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`  // Job owner, notified when the job opens or closes
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"deleted_at,omitempty"`   // In the trash since; purged after the retention period
+	DeletedBy        *uuid.UUID `gorm:"type:uuid" json:"deleted_by,omitempty"`
+
+	// Requisition, when the company requires approval before jobs go live
+	RequisitionStatus string  `gorm:"size:30" json:"requisition_status,omitempty"` // draft, pending_approval, approved, rejected; empty = not required
+	Justification     string  `gorm:"type:text" json:"justification,omitempty"`
+	BudgetMin         float64 `gorm:"type:numeric(14,2);default:0" json:"budget_min,omitempty"` // Approved salary band
+	BudgetMax         float64 `gorm:"type:numeric(14,2);default:0" json:"budget_max,omitempty"`
+	BudgetCurrency    string  `gorm:"size:3" json:"budget_currency,omitempty"` // ISO 4217, e.g. EUR
+
 	AutoShortlist    bool       `gorm:"default:true" json:"auto_shortlist"`
 	ShortlistCriteria *string   `gorm:"type:jsonb" json:"shortlist_criteria,omitempty"`
+	PipelineTemplateID *uuid.UUID `gorm:"type:uuid" json:"pipeline_template_id,omitempty"` // Overrides the company's default pipeline
 	CreatedAt        time.Time  `json:"created_at"`
 	UpdatedAt        time.Time  `json:"updated_at"`
 
 	// Relations
 	Applications []Application `gorm:"foreignKey:JobID" json:"applications,omitempty"`
+	Approvals    []JobApproval `gorm:"foreignKey:JobID" json:"approvals,omitempty"`
 }
 
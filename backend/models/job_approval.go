@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Requisition statuses of a job at a company that requires approval before jobs go live
+const (
+	RequisitionDraft           = "draft"
+	RequisitionPendingApproval = "pending_approval"
+	RequisitionApproved        = "approved"
+	RequisitionRejected        = "rejected" // Turned down by an approver, can be edited and resubmitted
+)
+
+// Requisition approval step statuses
+const (
+	JobApprovalPending  = "pending"
+	JobApprovalApproved = "approved"
+	JobApprovalRejected = "rejected"
+)
+
+// JobApproval is one step of a job requisition's approval chain. Steps are approved in order.
+type JobApproval struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	JobID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"job_id"`
+	ApproverID uuid.UUID  `gorm:"type:uuid;not null;index" json:"approver_id"`
+	Step       int        `gorm:"not null" json:"step"`
+	Status     string     `gorm:"size:20;default:'pending'" json:"status"` // pending, approved, rejected
+	Comment    string     `gorm:"type:text" json:"comment,omitempty"`
+	DecidedAt  *time.Time `json:"decided_at,omitempty"`
+
+	// Relations
+	Approver Admin `gorm:"foreignKey:ApproverID" json:"approver,omitempty"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Admin roles. Company-wide admins see every job; members only the jobs whose hiring team they're on.
+const (
+	AdminRoleAdmin  = "admin"
+	AdminRoleMember = "member"
+)
+
+// Roles on a job's hiring team
+const (
+	JobMemberRecruiter     = "recruiter"
+	JobMemberHiringManager = "hiring_manager"
+	JobMemberInterviewer   = "interviewer"
+)
+
+// JobMember puts an admin on the hiring team of a job
+type JobMember struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	JobID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_job_members_job_admin" json:"job_id"`
+	AdminID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_job_members_job_admin;index" json:"admin_id"`
+	Role      string     `gorm:"size:30;not null" json:"role"` // recruiter, hiring_manager, interviewer
+	AddedBy   *uuid.UUID `gorm:"type:uuid" json:"added_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// Relations
+	Admin Admin `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
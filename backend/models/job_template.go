@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// JobTemplate is a saved starting point for jobs a company posts repeatedly
+type JobTemplate struct {
+	ID                 uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	Name               string     `gorm:"size:255;not null" json:"name"` // e.g. "Backend Engineer (EU)"
+	Title              string     `gorm:"size:255" json:"title"`         // Title of jobs created from it
+	Description        string     `gorm:"type:text" json:"description"`
+	Requirements       string     `gorm:"type:text" json:"requirements"`
+	Location           string     `gorm:"size:255" json:"location"`
+	JobType            string     `gorm:"size:50" json:"job_type"`
+	Department         string     `gorm:"size:100" json:"department"`
+	SalaryRange        string     `gorm:"size:100" json:"salary_range"`
+	AutoShortlist      bool       `gorm:"default:true" json:"auto_shortlist"`
+	ShortlistCriteria  *string    `gorm:"type:jsonb" json:"shortlist_criteria,omitempty"`
+	PipelineTemplateID *uuid.UUID `gorm:"type:uuid" json:"pipeline_template_id,omitempty"`
+	Questions          string     `gorm:"type:jsonb" json:"-"` // JSON array of application form questions
+	UsageCount         int        `gorm:"default:0" json:"usage_count"`
+	LastUsedAt         *time.Time `json:"last_used_at,omitempty"`
+	CreatedBy          *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+
+	// Decoded Questions, filled in when a template is returned
+	FormQuestions []FormQuestion `gorm:"-" json:"questions"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Notification is an in-app message for an admin (shown in the dashboard bell / digest)
+type Notification struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID  uuid.UUID  `gorm:"type:uuid;not null" json:"company_id"`
+	AdminID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"admin_id"` // Recipient
+	Type       string     `gorm:"size:50;not null" json:"type"`             // saved_search_match, etc.
+	Title      string     `gorm:"size:255;not null" json:"title"`
+	Message    string     `gorm:"type:text" json:"message"`
+	EntityType string     `gorm:"size:50" json:"entity_type,omitempty"` // saved_search, application, job, etc.
+	EntityID   *uuid.UUID `gorm:"type:uuid" json:"entity_id,omitempty"`
+	IsRead     bool       `gorm:"default:false" json:"is_read"`
+	ReadAt     *time.Time `json:"read_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,69 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Offer statuses
+const (
+	OfferStatusDraft           = "draft"
+	OfferStatusPendingApproval = "pending_approval"
+	OfferStatusApproved        = "approved"
+	OfferStatusRejected        = "rejected" // Turned down by an approver, can be edited and resubmitted
+	OfferStatusSent            = "sent"
+	OfferStatusAccepted        = "accepted"
+	OfferStatusDeclined        = "declined"
+	OfferStatusExpired         = "expired"
+	OfferStatusWithdrawn       = "withdrawn"
+)
+
+// Offer approval statuses
+const (
+	OfferApprovalPending  = "pending"
+	OfferApprovalApproved = "approved"
+	OfferApprovalRejected = "rejected"
+)
+
+// Offer is a job offer for an application. It goes through an approval chain before it's sent to the candidate.
+type Offer struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	ApplicationID uuid.UUID  `gorm:"type:uuid;not null;index" json:"application_id"`
+	JobID         *uuid.UUID `gorm:"type:uuid" json:"job_id,omitempty"`
+	Salary        float64    `gorm:"type:numeric(14,2);not null" json:"salary"`
+	Currency      string     `gorm:"size:3;not null" json:"currency"` // ISO 4217, e.g. EUR
+	Bonus         float64    `gorm:"type:numeric(14,2);default:0" json:"bonus"`
+	StartDate     *time.Time `gorm:"type:date" json:"start_date,omitempty"`
+	ExpiresAt     time.Time  `json:"expires_at"`                      // Candidate must respond before this
+	TermsTemplate string     `gorm:"type:text" json:"terms_template"` // With {{placeholders}}
+	Terms         string     `gorm:"type:text" json:"terms"`          // Rendered from TermsTemplate
+	Status        string     `gorm:"size:30;default:'draft'" json:"status"`
+	Token         *string    `gorm:"size:64;uniqueIndex" json:"-"` // Secret part of the candidate's link, set when sent
+	CreatedBy     uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	SentAt        *time.Time `json:"sent_at,omitempty"`
+	ViewedAt      *time.Time `json:"viewed_at,omitempty"`
+	RespondedAt   *time.Time `json:"responded_at,omitempty"`
+	DeclineReason string     `gorm:"type:text" json:"decline_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	Approvals   []OfferApproval `gorm:"foreignKey:OfferID" json:"approvals"`
+	Application Application     `gorm:"foreignKey:ApplicationID" json:"application,omitempty"`
+}
+
+// OfferApproval is one step of an offer's approval chain. Steps are approved in order.
+type OfferApproval struct {
+	ID         uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	OfferID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"offer_id"`
+	ApproverID uuid.UUID  `gorm:"type:uuid;not null" json:"approver_id"`
+	Step       int        `gorm:"not null" json:"step"`
+	Status     string     `gorm:"size:20;default:'pending'" json:"status"` // pending, approved, rejected
+	Comment    string     `gorm:"type:text" json:"comment,omitempty"`
+	DecidedAt  *time.Time `json:"decided_at,omitempty"`
+
+	// Relations
+	Approver Admin `gorm:"foreignKey:ApproverID" json:"approver,omitempty"`
+}
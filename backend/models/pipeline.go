@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Pipeline stage types. Every stage is one of these, whatever it is called.
+const (
+	StageTypeActive    = "active"    // Candidate is still in the process
+	StageTypeHired     = "hired"     // Terminal: candidate was hired
+	StageTypeRejected  = "rejected"  // Terminal: candidate is out of the process
+	StageTypeWithdrawn = "withdrawn" // Terminal: candidate withdrew their application
+)
+
+// PipelineTemplate is an ordered set of hiring stages a company (or a single job) uses
+type PipelineTemplate struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID uuid.UUID `gorm:"type:uuid;not null;index" json:"company_id"`
+	Name      string    `gorm:"size:255;not null" json:"name"`
+	IsDefault bool      `gorm:"default:false" json:"is_default"` // Used by jobs without their own pipeline
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Relations
+	Stages []PipelineStage `gorm:"foreignKey:PipelineTemplateID" json:"stages"`
+}
+
+// PipelineStage is a single step of a pipeline. Applications store the stage Key in Status.
+type PipelineStage struct {
+	ID                 uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	PipelineTemplateID uuid.UUID `gorm:"type:uuid;not null;index" json:"pipeline_template_id"`
+	Key                string    `gorm:"size:50;not null" json:"key"`                   // e.g. "tech_interview"
+	Name               string    `gorm:"size:255;not null" json:"name"`                 // e.g. "Tech Interview"
+	Type               string    `gorm:"size:20;not null;default:'active'" json:"type"` // active, hired, rejected
+	Position           int       `gorm:"not null" json:"position"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PrivacyNotice is one published version of a company's candidate privacy notice.
+// Versions are never edited; a change publishes the next version.
+type PrivacyNotice struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_privacy_notice_version,priority:1" json:"company_id"`
+	Version   int        `gorm:"not null;uniqueIndex:idx_privacy_notice_version,priority:2" json:"version"`
+	Title     string     `gorm:"size:255;not null" json:"title"`
+	Content   string     `gorm:"type:text;not null" json:"content"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	CreatedAt time.Time  `json:"created_at"` // Published at
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Retention policy actions
+const (
+	RetentionDelete    = "delete"    // Permanently delete the application and its files
+	RetentionAnonymise = "anonymise" // Keep the application for reporting without anything identifying the candidate
+)
+
+// RetentionPolicy removes a company's candidate data automatically once it is old enough,
+// e.g. "delete rejected candidates after 12 months"
+type RetentionPolicy struct {
+	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	Stage     string     `gorm:"size:50" json:"stage"`           // Applications in this stage
+	Months    int        `gorm:"not null" json:"months"`         // Counted from the last status update, or the application date
+	Action    string     `gorm:"size:20;not null" json:"action"` // delete or anonymise
+	IsActive  bool       `gorm:"not null" json:"is_active"`
+	CreatedBy *uuid.UUID `gorm:"type:uuid" json:"created_by,omitempty"`
+	LastRunAt *time.Time `json:"last_run_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
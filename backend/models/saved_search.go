@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearch is a named candidate search that can be re-run and alert its owner about new matches
+type SavedSearch struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	AdminID       uuid.UUID  `gorm:"type:uuid;not null" json:"admin_id"` // Owner of the search
+	Name          string     `gorm:"size:255;not null" json:"name"`
+	Criteria      string     `gorm:"type:jsonb;not null" json:"criteria"` // Saved SearchCandidatesRequest
+	IsShared      bool       `gorm:"default:false" json:"is_shared"`      // Visible to every admin of the company
+	AlertsEnabled bool       `gorm:"default:true" json:"alerts_enabled"`
+	AlertChannel  string     `gorm:"size:20;default:'email'" json:"alert_channel"` // email, in_app
+	LastRunAt     *time.Time `json:"last_run_at,omitempty"`                        // Last time alerts were evaluated
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+
+	// Relations
+	Admin Admin `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
+
+// SavedSearchMatch records a candidate already alerted for a saved search, so nobody is alerted twice
+type SavedSearchMatch struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SavedSearchID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_application" json:"saved_search_id"`
+	ApplicationID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_application" json:"application_id"`
+	CandidateEmail string    `gorm:"size:255;index" json:"candidate_email"` // Lowercased; a candidate applying to another job isn't alerted again
+	MatchScore     int       `json:"match_score"`
+	Channel        string    `gorm:"size:20" json:"channel"` // Channel the alert went out on
+	CreatedAt      time.Time `json:"created_at"`
+
+	// Relations
+	Application Application `gorm:"foreignKey:ApplicationID" json:"application,omitempty"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scheduling link statuses
+const (
+	SchedulingLinkOpen      = "open"
+	SchedulingLinkBooked    = "booked"
+	SchedulingLinkCancelled = "cancelled"
+)
+
+// SchedulingLink lets a candidate book one interview slot out of the availability a recruiter published
+type SchedulingLink struct {
+	ID              uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"company_id"`
+	ApplicationID   uuid.UUID  `gorm:"type:uuid;not null;index" json:"application_id"`
+	Token           string     `gorm:"size:64;uniqueIndex;not null" json:"token"` // Secret part of the candidate's link
+	RoundName       string     `gorm:"size:255;not null" json:"round_name"`
+	DurationMinutes int        `gorm:"not null" json:"duration_minutes"`
+	BufferMinutes   int        `gorm:"default:0" json:"buffer_minutes"` // Kept free around interviewers' other bookings
+	TimeZone        string     `gorm:"size:64;default:'UTC'" json:"time_zone"`
+	Location        string     `gorm:"size:255" json:"location,omitempty"`
+	VideoLink       string     `gorm:"type:text" json:"video_link,omitempty"`
+	InterviewerIDs  string     `gorm:"type:jsonb;not null" json:"interviewer_ids"` // JSON array of admin IDs
+	Status          string     `gorm:"size:20;default:'open'" json:"status"`       // open, booked, cancelled
+	ExpiresAt       time.Time  `json:"expires_at"`
+	InterviewID     *uuid.UUID `gorm:"type:uuid" json:"interview_id,omitempty"` // Set once booked
+	CreatedBy       uuid.UUID  `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+
+	// Relations
+	Windows []AvailabilityWindow `gorm:"foreignKey:SchedulingLinkID" json:"windows"`
+}
+
+// AvailabilityWindow is a period in which a scheduling link's interviewers can be booked
+type AvailabilityWindow struct {
+	ID               uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SchedulingLinkID uuid.UUID `gorm:"type:uuid;not null;index" json:"scheduling_link_id"`
+	StartTime        time.Time `gorm:"not null" json:"start_time"`
+	EndTime          time.Time `gorm:"not null" json:"end_time"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scorecard recommendations, from most negative to most positive
+const (
+	RecommendationStrongNo  = "strong_no"
+	RecommendationNo        = "no"
+	RecommendationYes       = "yes"
+	RecommendationStrongYes = "strong_yes"
+)
+
+// ScorecardTemplate defines what interviewers rate candidates of a job on
+type ScorecardTemplate struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID       uuid.UUID `gorm:"type:uuid;not null;index" json:"company_id"`
+	JobID           uuid.UUID `gorm:"type:uuid;not null;uniqueIndex" json:"job_id"` // One template per job
+	Name            string    `gorm:"size:255;not null" json:"name"`
+	RatingMin       int       `gorm:"default:1" json:"rating_min"`
+	RatingMax       int       `gorm:"default:5" json:"rating_max"`
+	RequireComments bool      `gorm:"default:false" json:"require_comments"` // Every rating needs a comment
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+
+	// Relations
+	Competencies []ScorecardCompetency `gorm:"foreignKey:TemplateID" json:"competencies"`
+}
+
+// ScorecardCompetency is one rated competency of a scorecard template
+type ScorecardCompetency struct {
+	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TemplateID      uuid.UUID `gorm:"type:uuid;not null;index" json:"template_id"`
+	Name            string    `gorm:"size:255;not null" json:"name"`
+	Description     string    `gorm:"type:text" json:"description,omitempty"`
+	CommentRequired bool      `gorm:"default:false" json:"comment_required"`
+	Position        int       `gorm:"not null" json:"position"`
+}
+
+// Scorecard is one interviewer's structured feedback for one interview
+type Scorecard struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CompanyID      uuid.UUID `gorm:"type:uuid;not null" json:"company_id"`
+	InterviewID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_scorecard_interview_admin" json:"interview_id"`
+	AdminID        uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_scorecard_interview_admin" json:"admin_id"` // Interviewer
+	ApplicationID  uuid.UUID `gorm:"type:uuid;not null;index" json:"application_id"`
+	TemplateID     uuid.UUID `gorm:"type:uuid;not null" json:"template_id"`
+	Recommendation string    `gorm:"size:20;not null" json:"recommendation"` // strong_no, no, yes, strong_yes
+	OverallComment string    `gorm:"type:text" json:"overall_comment,omitempty"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+
+	// Relations
+	Ratings []ScorecardRating `gorm:"foreignKey:ScorecardID" json:"ratings"`
+	Admin   Admin             `gorm:"foreignKey:AdminID" json:"admin,omitempty"`
+}
+
+// ScorecardRating is the rating of one competency on a scorecard
+type ScorecardRating struct {
+	ID             uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ScorecardID    uuid.UUID `gorm:"type:uuid;not null;index" json:"scorecard_id"`
+	CompetencyID   uuid.UUID `gorm:"type:uuid;not null" json:"competency_id"`
+	CompetencyName string    `gorm:"size:255;not null" json:"competency_name"` // Kept in case the template changes
+	Rating         int       `gorm:"not null" json:"rating"`
+	Comment        string    `gorm:"type:text" json:"comment,omitempty"`
+}
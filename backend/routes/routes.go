@@ -15,13 +15,41 @@ func SetupRoutes(router *gin.Engine) {
 		api.POST("/auth/login", controllers.Login)
 		api.GET("/jobs/public/:companyId", controllers.GetPublicJobs)
 		api.POST("/applications", controllers.SubmitApplication)
+		api.GET("/application-forms/:jobId", controllers.GetPublicApplicationForm)
+
+		// Careers site API (public); companies and jobs may be given by slug or ID
+		api.GET("/careers/:companySlug", controllers.GetCareersJobs)
+		api.GET("/careers/:companySlug/jobs/:jobSlug", controllers.GetCareersJob)
+		api.GET("/careers/:companySlug/privacy-notice", controllers.GetCareersPrivacyNotice)
+
+		// Job board syndication feeds (public); ?source= overrides the tracking source on apply links
+		api.GET("/feeds/:companyId/jobs.jsonld", controllers.GetJobPostingsFeed)
+		api.GET("/feeds/:companyId/jobs/:jobId/jsonld", controllers.GetJobPosting)
+		api.GET("/feeds/:companyId/indeed.xml", controllers.GetIndeedFeed)
+		api.GET("/feeds/:companyId/rss.xml", controllers.GetRSSFeed)
+		api.GET("/feeds/:companyId/atom.xml", controllers.GetAtomFeed)
 		
-		// Candidate Portal routes (public)
-		api.POST("/candidate/status", controllers.GetApplicationStatus)
-		api.GET("/candidate/applications", controllers.GetApplicationStatusByEmail)
-		api.POST("/candidate/messages/send", controllers.SendMessage)
-		api.GET("/candidate/messages", controllers.GetMessages)
-		
+		// Candidate Portal sign-in (public); a magic link emailed to the candidate is exchanged for a session
+		api.POST("/candidate/auth/request-link", controllers.RequestCandidateMagicLink)
+		api.POST("/candidate/auth/session", controllers.CreateCandidateSession)
+
+		// Candidate Portal routes (candidate session required, scoped to the candidate's email)
+		candidate := api.Group("/candidate")
+		candidate.Use(middleware.CandidateAuthMiddleware())
+		{
+			candidate.POST("/status", controllers.GetApplicationStatus)
+			candidate.POST("/withdraw", controllers.WithdrawApplication)
+			candidate.POST("/consent", controllers.UpdateConsent)
+			candidate.GET("/applications", controllers.GetCandidateApplications)
+			candidate.POST("/messages/send", controllers.SendMessage)
+			candidate.GET("/messages", controllers.GetMessages)
+			candidate.GET("/scheduling/:token", controllers.GetSchedulingSlots)
+			candidate.POST("/scheduling/:token/book", controllers.BookSchedulingSlot)
+			candidate.GET("/offers/:token", controllers.GetCandidateOffer)
+			candidate.POST("/offers/:token/accept", controllers.AcceptOffer)
+			candidate.POST("/offers/:token/decline", controllers.DeclineOffer)
+		}
+
 		// File upload routes (public for application submission)
 		api.POST("/upload/cv", controllers.UploadCV)
 		api.POST("/upload/portfolio", controllers.UploadPortfolio)
@@ -39,11 +67,41 @@ func SetupRoutes(router *gin.Engine) {
 			protected.GET("/jobs/:id", controllers.GetJob)
 			protected.PUT("/jobs/:id", controllers.UpdateJob)
 			protected.DELETE("/jobs/:id", controllers.DeleteJob)
+			protected.GET("/jobs/:id/pipeline", controllers.GetJobPipeline)
+			protected.PUT("/jobs/:id/pipeline", controllers.SetJobPipeline)
+			protected.GET("/jobs/:id/application-form", controllers.GetJobApplicationForm)
+			protected.PUT("/jobs/:id/application-form", controllers.SetJobApplicationForm)
+			protected.POST("/jobs/:id/clone", controllers.CloneJob)
+			protected.POST("/jobs/:id/save-as-template", controllers.SaveJobAsTemplate)
+
+			// Hiring team routes
+			protected.GET("/jobs/:id/members", controllers.GetJobMembers)
+			protected.POST("/jobs/:id/members", controllers.AddJobMember)
+			protected.PUT("/jobs/:id/members/:adminId", controllers.UpdateJobMember)
+			protected.DELETE("/jobs/:id/members/:adminId", controllers.RemoveJobMember)
+
+			// Job template routes (POST /jobs takes a template_id to start from one)
+			protected.POST("/job-templates", controllers.CreateJobTemplate)
+			protected.GET("/job-templates", controllers.GetJobTemplates)
+			protected.GET("/job-templates/:id", controllers.GetJobTemplate)
+			protected.PUT("/job-templates/:id", controllers.UpdateJobTemplate)
+			protected.DELETE("/job-templates/:id", controllers.DeleteJobTemplate)
+
+			// Pipeline routes
+			protected.POST("/pipelines", controllers.CreatePipelineTemplate)
+			protected.GET("/pipelines", controllers.GetPipelineTemplates)
+			protected.GET("/pipelines/:id", controllers.GetPipelineTemplate)
+			protected.PUT("/pipelines/:id", controllers.UpdatePipelineTemplate)
+			protected.DELETE("/pipelines/:id", controllers.DeletePipelineTemplate)
 
 			// Application routes
 			protected.GET("/applications", controllers.GetApplications)
-			protected.PUT("/applications/:id/shortlist", controllers.ShortlistApplication)
-			protected.PUT("/applications/:id/reject", controllers.RejectApplication)
+			protected.GET("/applications/export", controllers.ExportApplications) // CSV or XLSX of the filtered listing
+			protected.PUT("/applications/:id/stage", controllers.MoveApplicationStage)
+			protected.GET("/applications/:id/history", controllers.GetApplicationHistory)
+			protected.GET("/applications/:id/answers", controllers.GetApplicationAnswers)
+			protected.PUT("/applications/:id/shortlist", controllers.ShortlistApplication) // Alias of stage "shortlisted"
+			protected.PUT("/applications/:id/reject", controllers.RejectApplication) // Alias of the first rejected stage
 			protected.POST("/applications/:id/track-cv-view", controllers.TrackCVView)
 			protected.DELETE("/applications/:id", controllers.DeleteApplication)
 			protected.POST("/applications/bulk-delete", controllers.BulkDeleteApplications)
@@ -56,15 +114,104 @@ func SetupRoutes(router *gin.Engine) {
 			protected.POST("/applications/ai-shortlist", controllers.AIShortlistApplication)
 			protected.POST("/applications/ai-shortlist-batch", controllers.BatchAIShortlist)
 			
+			// Interview routes
+			protected.POST("/interviews", controllers.ScheduleInterview)
+			protected.GET("/interviews", controllers.GetInterviews)
+			protected.POST("/interviews/check-conflicts", controllers.CheckInterviewConflicts)
+			protected.GET("/interviews/:id", controllers.GetInterview)
+			protected.PUT("/interviews/:id", controllers.UpdateInterview)
+			protected.DELETE("/interviews/:id", controllers.CancelInterview)
+			protected.GET("/interviews/:id/ics", controllers.DownloadInterviewICS)
+
+			// Interview self-scheduling routes
+			protected.POST("/applications/:id/scheduling-links", controllers.CreateSchedulingLink)
+			protected.GET("/applications/:id/scheduling-links", controllers.GetSchedulingLinks)
+			protected.DELETE("/scheduling-links/:id", controllers.CancelSchedulingLink)
+			
+			// Interview scorecard routes
+			protected.GET("/jobs/:id/scorecard-template", controllers.GetJobScorecardTemplate)
+			protected.PUT("/jobs/:id/scorecard-template", controllers.SetJobScorecardTemplate)
+			protected.POST("/interviews/:id/scorecard", controllers.SubmitScorecard)
+			protected.GET("/interviews/:id/scorecards", controllers.GetInterviewScorecards)
+			protected.GET("/applications/:id/scorecards", controllers.GetApplicationScorecards)
+			
+			// Offer routes
+			protected.POST("/offers", controllers.CreateOffer)
+			protected.GET("/offers", controllers.GetOffers)
+			protected.GET("/offers/:id", controllers.GetOffer)
+			protected.PUT("/offers/:id", controllers.UpdateOffer)
+			protected.POST("/offers/:id/submit", controllers.SubmitOfferForApproval)
+			protected.POST("/offers/:id/approval", controllers.DecideOfferApproval)
+			protected.POST("/offers/:id/send", controllers.SendOffer)
+			protected.POST("/offers/:id/withdraw", controllers.WithdrawOffer)
+
+			// Job requisition approval routes
+			protected.PUT("/jobs/:id/requisition", controllers.UpdateJobRequisition)
+			protected.POST("/jobs/:id/requisition/submit", controllers.SubmitJobRequisition)
+			protected.POST("/jobs/:id/requisition/approval", controllers.DecideJobRequisition)
+			protected.GET("/requisitions/pending", controllers.GetPendingRequisitions)
+			protected.GET("/admins", controllers.GetAdmins)
+			protected.PUT("/admins/:id/approver", controllers.SetAdminApprover)
+			protected.PUT("/admins/:id/role", controllers.SetAdminRole)
+			
+			// Company settings routes
+			protected.GET("/company/reapplication-policy", controllers.GetReapplicationPolicy)
+			protected.PUT("/company/reapplication-policy", controllers.UpdateReapplicationPolicy)
+			protected.GET("/company/job-close-policy", controllers.GetJobClosePolicy)
+			protected.PUT("/company/job-close-policy", controllers.UpdateJobClosePolicy)
+			protected.GET("/company/requisition-policy", controllers.GetRequisitionPolicy)
+			protected.PUT("/company/requisition-policy", controllers.UpdateRequisitionPolicy)
+			protected.GET("/company/careers-site", controllers.GetCareersSite)
+			protected.PUT("/company/careers-site", controllers.UpdateCareersSite)
+			
+			// Analytics routes
+			protected.GET("/analytics/headcount", controllers.GetHeadcountAnalytics)
+
+			// Trash routes (deleted jobs and applications until they are purged)
+			protected.GET("/trash", controllers.GetTrash)
+			protected.POST("/trash/jobs/:id/restore", controllers.RestoreTrashedJob)
+			protected.POST("/trash/applications/:id/restore", controllers.RestoreTrashedApplication)
+
+			// Privacy routes (data subject access and erasure, retention policies)
+			protected.GET("/privacy/candidates/export", controllers.ExportCandidateData)
+			protected.POST("/privacy/candidates/erase", controllers.EraseCandidateData)
+			protected.GET("/privacy/retention-policies", controllers.GetRetentionPolicies)
+			protected.POST("/privacy/retention-policies", controllers.CreateRetentionPolicy)
+			protected.PUT("/privacy/retention-policies/:id", controllers.UpdateRetentionPolicy)
+			protected.DELETE("/privacy/retention-policies/:id", controllers.DeleteRetentionPolicy)
+			protected.GET("/privacy/notices", controllers.GetPrivacyNotices)
+			protected.POST("/privacy/notices", controllers.PublishPrivacyNotice)
+
 			// Activity Logs routes
 			protected.GET("/activity-logs", controllers.GetActivityLogs)
 			
 			// Candidate Search routes
 			protected.POST("/candidates/search", controllers.SearchCandidates)
+			protected.POST("/candidates/search/export", controllers.ExportSearchResults)
 			protected.GET("/candidates/:id", controllers.GetCandidateDetails)
 			
+			// Export routes (large exports run in the background)
+			protected.GET("/exports/columns", controllers.GetExportColumns)
+			protected.GET("/exports", controllers.GetExports)
+			protected.GET("/exports/:id", controllers.GetExport)
+			protected.GET("/exports/:id/download", controllers.DownloadExport)
+
+			// Saved Search routes
+			protected.POST("/saved-searches", controllers.CreateSavedSearch)
+			protected.GET("/saved-searches", controllers.GetSavedSearches)
+			protected.GET("/saved-searches/:id", controllers.GetSavedSearch)
+			protected.PUT("/saved-searches/:id", controllers.UpdateSavedSearch)
+			protected.DELETE("/saved-searches/:id", controllers.DeleteSavedSearch)
+			protected.GET("/saved-searches/:id/matches", controllers.GetSavedSearchMatches)
+
+			// Notification routes
+			protected.GET("/notifications", controllers.GetNotifications)
+			protected.PUT("/notifications/read-all", controllers.MarkAllNotificationsRead)
+			protected.PUT("/notifications/:id/read", controllers.MarkNotificationRead)
+			
 			// Manual Candidate routes
 			protected.POST("/candidates/manual", controllers.AddManualCandidate)
+			protected.POST("/candidates/import", controllers.ImportCandidates) // CSV plus optional ZIP of CVs
 			
 			// CV Reparsing routes (for fixing existing applications)
 			protected.POST("/candidates/reparse-all", controllers.ReparseAllCVs)
@@ -80,6 +227,8 @@ func SetupRoutes(router *gin.Engine) {
 			protected.POST("/crm/talent-pool", controllers.AddToTalentPool)
 			protected.DELETE("/crm/talent-pool/:id", controllers.RemoveFromTalentPool)
 			protected.PUT("/crm/applications/:id/referral", controllers.UpdateReferralInfo)
+			protected.PUT("/crm/applications/:id/tags", controllers.UpdateApplicationTags)
+			protected.GET("/crm/tags", controllers.GetApplicationTags)
 			protected.GET("/crm/applications/:id/timeline", controllers.GetRelationshipTimeline)
 		}
 
@@ -5,6 +5,8 @@ import (
 	"ats-backend/models"
 	"encoding/json"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -74,6 +76,196 @@ func LogJobCreated(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle strin
 	)
 }
 
+// LogRequisitionSubmitted logs when a job requisition is submitted for approval
+func LogRequisitionSubmitted(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle string, headcount int, approverCount int) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"requisition_submitted",
+		"job",
+		&jobID,
+		"Requisition submitted for approval: "+jobTitle,
+		map[string]interface{}{
+			"job_title":      jobTitle,
+			"headcount":      headcount,
+			"approver_count": approverCount,
+		},
+	)
+}
+
+// LogRequisitionDecision logs when an approver approves or rejects a job requisition
+func LogRequisitionDecision(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle, decision, comment string, step int) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"requisition_"+decision,
+		"job",
+		&jobID,
+		"Requisition for "+jobTitle+" "+decision,
+		map[string]interface{}{
+			"job_title": jobTitle,
+			"decision":  decision,
+			"comment":   comment,
+			"step":      step,
+		},
+	)
+}
+
+// LogRequisitionPolicyUpdated logs a change to the company's job approval requirement or default approvers
+func LogRequisitionPolicyUpdated(companyID, adminID uuid.UUID, requireApproval bool, approverIDs []string) {
+	description := "Job requisition approval turned off"
+	if requireApproval {
+		description = "Job requisition approval required with " + strconv.Itoa(len(approverIDs)) + " default approver(s)"
+	}
+	LogActivity(
+		&companyID,
+		&adminID,
+		"requisition_policy_updated",
+		"company",
+		&companyID,
+		description,
+		map[string]interface{}{
+			"require_approval": requireApproval,
+			"approver_ids":     approverIDs,
+		},
+	)
+}
+
+// LogAdminApproverRoleChanged logs when an admin is given or loses the requisition approver role
+func LogAdminApproverRoleChanged(companyID, adminID uuid.UUID, targetID uuid.UUID, targetName string, isApprover bool) {
+	description := targetName + " lost the approver role"
+	if isApprover {
+		description = targetName + " was given the approver role"
+	}
+	LogActivity(
+		&companyID,
+		&adminID,
+		"admin_approver_role_changed",
+		"admin",
+		&targetID,
+		description,
+		map[string]interface{}{
+			"admin_name":  targetName,
+			"is_approver": isApprover,
+		},
+	)
+}
+
+// LogAdminRoleChanged logs when an admin is made company-wide or limited to their hiring teams
+func LogAdminRoleChanged(companyID, adminID uuid.UUID, targetID uuid.UUID, targetName, oldRole, newRole string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"admin_role_changed",
+		"admin",
+		&targetID,
+		targetName+"'s role changed from "+oldRole+" to "+newRole,
+		map[string]interface{}{
+			"admin_name": targetName,
+			"old_role":   oldRole,
+			"new_role":   newRole,
+		},
+	)
+}
+
+// LogJobMemberChanged logs an admin joining, changing role on or leaving a job's hiring team.
+// oldRole is empty when they joined, newRole when they left.
+func LogJobMemberChanged(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle string, memberID uuid.UUID, memberName, oldRole, newRole string) {
+	actionType := "job_member_role_changed"
+	description := memberName + " is now " + newRole + " of " + jobTitle
+	switch {
+	case oldRole == "":
+		actionType = "job_member_added"
+		description = memberName + " added to the hiring team of " + jobTitle + " as " + newRole
+	case newRole == "":
+		actionType = "job_member_removed"
+		description = memberName + " removed from the hiring team of " + jobTitle
+	}
+	LogActivity(
+		&companyID,
+		&adminID,
+		actionType,
+		"job",
+		&jobID,
+		description,
+		map[string]interface{}{
+			"job_title":   jobTitle,
+			"member_id":   memberID.String(),
+			"member_name": memberName,
+			"old_role":    oldRole,
+			"new_role":    newRole,
+		},
+	)
+}
+
+// LogJobTemplateSaved logs when a job template is created or updated
+func LogJobTemplateSaved(companyID, adminID uuid.UUID, templateID uuid.UUID, templateName string, created bool) {
+	action, verb := "job_template_updated", "updated"
+	if created {
+		action, verb = "job_template_created", "created"
+	}
+	LogActivity(
+		&companyID,
+		&adminID,
+		action,
+		"job_template",
+		&templateID,
+		"Job template "+verb+": "+templateName,
+		map[string]interface{}{
+			"template_name": templateName,
+		},
+	)
+}
+
+// LogJobTemplateDeleted logs when a job template is deleted
+func LogJobTemplateDeleted(companyID, adminID uuid.UUID, templateID uuid.UUID, templateName string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"job_template_deleted",
+		"job_template",
+		&templateID,
+		"Job template deleted: "+templateName,
+		map[string]interface{}{
+			"template_name": templateName,
+		},
+	)
+}
+
+// LogJobTemplateUsed logs when a job is created from a template
+func LogJobTemplateUsed(companyID, adminID uuid.UUID, templateID uuid.UUID, templateName string, jobID uuid.UUID, jobTitle string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"job_template_used",
+		"job_template",
+		&templateID,
+		"Job "+jobTitle+" created from template "+templateName,
+		map[string]interface{}{
+			"template_name": templateName,
+			"job_id":        jobID.String(),
+			"job_title":     jobTitle,
+		},
+	)
+}
+
+// LogJobCloned logs when a job is created as a copy of another
+func LogJobCloned(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle string, sourceJobID uuid.UUID, sourceTitle string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"job_cloned",
+		"job",
+		&jobID,
+		"Job "+jobTitle+" cloned from "+sourceTitle,
+		map[string]interface{}{
+			"job_title":     jobTitle,
+			"source_job_id": sourceJobID.String(),
+			"source_title":  sourceTitle,
+		},
+	)
+}
+
 // LogJobUpdated logs when a job is updated
 func LogJobUpdated(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle string, changes map[string]interface{}) {
 	LogActivity(
@@ -87,7 +279,7 @@ func LogJobUpdated(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle strin
 	)
 }
 
-// LogJobDeleted logs when a job is deleted
+// LogJobDeleted logs when a job is moved to the trash
 func LogJobDeleted(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle string) {
 	LogActivity(
 		&companyID,
@@ -95,30 +287,98 @@ func LogJobDeleted(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle strin
 		"job_deleted",
 		"job",
 		&jobID,
-		"Job deleted: "+jobTitle,
+		"Job moved to the trash: "+jobTitle,
 		map[string]interface{}{
 			"job_title": jobTitle,
+			"purge_at":  PurgeDate(time.Now()),
 		},
 	)
 }
 
-// LogJobStatusChanged logs when a job status changes
-func LogJobStatusChanged(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle, oldStatus, newStatus string) {
+// LogJobRestored logs when a job is taken out of the trash
+func LogJobRestored(companyID, adminID uuid.UUID, jobID uuid.UUID, jobTitle string) {
 	LogActivity(
 		&companyID,
 		&adminID,
-		"job_status_changed",
+		"job_restored",
 		"job",
 		&jobID,
-		"Job status changed: "+jobTitle+" from "+oldStatus+" to "+newStatus,
+		"Job restored from the trash: "+jobTitle,
+		map[string]interface{}{
+			"job_title": jobTitle,
+		},
+	)
+}
+
+// LogJobPurged logs when the scheduler permanently deletes a job after the trash retention period
+func LogJobPurged(companyID uuid.UUID, jobID uuid.UUID, jobTitle string) {
+	LogActivity(
+		&companyID,
+		nil,
+		"job_purged",
+		"job",
+		&jobID,
+		"Job permanently deleted from the trash: "+jobTitle,
+		map[string]interface{}{
+			"job_title": jobTitle,
+		},
+	)
+}
+
+// LogApplicationRestored logs when an application is taken out of the trash
+func LogApplicationRestored(companyID, adminID uuid.UUID, applicationID uuid.UUID, applicantName string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"application_restored",
+		"application",
+		&applicationID,
+		"Application restored from the trash: "+applicantName,
+		map[string]interface{}{
+			"applicant_name": applicantName,
+		},
+	)
+}
+
+// LogApplicationPurged logs when an application is permanently deleted. adminID is nil when the scheduler
+// purged it, with reason saying why (e.g. "retention_expired").
+func LogApplicationPurged(companyID uuid.UUID, adminID *uuid.UUID, applicationID uuid.UUID, applicantName, reason string) {
+	LogActivity(
+		&companyID,
+		adminID,
+		"application_purged",
+		"application",
+		&applicationID,
+		"Application permanently deleted: "+applicantName,
 		map[string]interface{}{
-			"job_title":  jobTitle,
-			"old_status": oldStatus,
-			"new_status": newStatus,
+			"applicant_name": applicantName,
+			"reason":         reason,
 		},
 	)
 }
 
+// LogJobStatusChanged logs when a job status changes. adminID is nil when the scheduler changed it,
+// with reason saying why (e.g. "deadline_passed").
+func LogJobStatusChanged(companyID uuid.UUID, adminID *uuid.UUID, jobID uuid.UUID, jobTitle, oldStatus, newStatus, reason string) {
+	metadata := map[string]interface{}{
+		"job_title":  jobTitle,
+		"old_status": oldStatus,
+		"new_status": newStatus,
+	}
+	if reason != "" {
+		metadata["reason"] = reason
+	}
+	LogActivity(
+		&companyID,
+		adminID,
+		"job_status_changed",
+		"job",
+		&jobID,
+		"Job status changed: "+jobTitle+" from "+oldStatus+" to "+newStatus,
+		metadata,
+	)
+}
+
 // LogApplicationShortlisted logs when an application is shortlisted
 func LogApplicationShortlisted(companyID, adminID uuid.UUID, applicationID uuid.UUID, candidateName, jobTitle string) {
 	LogActivity(
@@ -169,3 +429,340 @@ func LogApplicationStatusChanged(companyID, adminID uuid.UUID, applicationID uui
 	)
 }
 
+// LogInterviewScheduled logs when an interview is scheduled
+func LogInterviewScheduled(companyID, adminID uuid.UUID, interviewID uuid.UUID, candidateName, roundName string, startTime time.Time) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"interview_scheduled",
+		"interview",
+		&interviewID,
+		"Interview scheduled: "+roundName+" with "+candidateName+" on "+startTime.UTC().Format("2006-01-02 15:04 UTC"),
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"round_name":     roundName,
+			"start_time":     startTime,
+		},
+	)
+}
+
+// LogInterviewRescheduled logs when an interview is moved or its details change
+func LogInterviewRescheduled(companyID, adminID uuid.UUID, interviewID uuid.UUID, candidateName, roundName string, oldStart, newStart time.Time) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"interview_rescheduled",
+		"interview",
+		&interviewID,
+		"Interview updated: "+roundName+" with "+candidateName+" now on "+newStart.UTC().Format("2006-01-02 15:04 UTC"),
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"round_name":     roundName,
+			"old_start_time": oldStart,
+			"new_start_time": newStart,
+		},
+	)
+}
+
+// LogInterviewCancelled logs when an interview is cancelled
+func LogInterviewCancelled(companyID, adminID uuid.UUID, interviewID uuid.UUID, candidateName, roundName string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"interview_cancelled",
+		"interview",
+		&interviewID,
+		"Interview cancelled: "+roundName+" with "+candidateName,
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"round_name":     roundName,
+		},
+	)
+}
+
+// LogScorecardSubmitted logs when an interviewer submits their scorecard.
+// The ratings aren't included so the log doesn't reveal them to interviewers who haven't submitted yet.
+func LogScorecardSubmitted(companyID, adminID uuid.UUID, scorecardID uuid.UUID, candidateName, roundName string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"scorecard_submitted",
+		"scorecard",
+		&scorecardID,
+		"Scorecard submitted for "+candidateName+" ("+roundName+")",
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"round_name":     roundName,
+		},
+	)
+}
+
+// LogOfferCreated logs when an offer is drafted for a candidate
+func LogOfferCreated(companyID, adminID uuid.UUID, offerID uuid.UUID, candidateName string, salary float64, currency string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"offer_created",
+		"offer",
+		&offerID,
+		"Offer drafted for "+candidateName,
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"salary":         salary,
+			"currency":       currency,
+		},
+	)
+}
+
+// LogOfferApprovalDecision logs when an approver approves or rejects an offer
+func LogOfferApprovalDecision(companyID, adminID uuid.UUID, offerID uuid.UUID, candidateName, decision, comment string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"offer_"+decision,
+		"offer",
+		&offerID,
+		"Offer for "+candidateName+" "+decision,
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"decision":       decision,
+			"comment":        comment,
+		},
+	)
+}
+
+// LogOfferStatusChanged logs when an offer is submitted, sent, withdrawn, answered or expires
+func LogOfferStatusChanged(companyID, adminID uuid.UUID, offerID uuid.UUID, candidateName, status string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"offer_"+status,
+		"offer",
+		&offerID,
+		"Offer for "+candidateName+" is now "+strings.ReplaceAll(status, "_", " "),
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"status":         status,
+		},
+	)
+}
+
+// LogApplicationWithdrawn logs when a candidate withdraws their application through the portal
+func LogApplicationWithdrawn(companyID uuid.UUID, applicationID uuid.UUID, candidateName, jobTitle, reason string) {
+	LogActivity(
+		&companyID,
+		nil, // Candidate action
+		"application_withdrawn",
+		"application",
+		&applicationID,
+		candidateName+" withdrew their application for "+jobTitle,
+		map[string]interface{}{
+			"candidate_name": candidateName,
+			"job_title":      jobTitle,
+			"reason":         reason,
+		},
+	)
+}
+
+// LogReapplicationPolicyUpdated logs when a company changes its reapplication cooldown
+func LogReapplicationPolicyUpdated(companyID, adminID uuid.UUID, oldDays, newDays int) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"reapplication_policy_updated",
+		"company",
+		&companyID,
+		"Reapplication cooldown changed to "+strconv.Itoa(newDays)+" day(s)",
+		map[string]interface{}{
+			"old_cooldown_days": oldDays,
+			"new_cooldown_days": newDays,
+		},
+	)
+}
+
+// LogJobClosePolicyUpdated logs a change to what pending applicants are told when a job closes automatically
+func LogJobClosePolicyUpdated(companyID, adminID uuid.UUID, oldPolicy, newPolicy string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"job_close_policy_updated",
+		"company",
+		&companyID,
+		"Applicant policy for automatically closed jobs changed to "+newPolicy,
+		map[string]interface{}{
+			"old_policy": oldPolicy,
+			"new_policy": newPolicy,
+		},
+	)
+}
+
+// LogCareersSiteUpdated logs a change to the company's careers slug or branding
+func LogCareersSiteUpdated(companyID, adminID uuid.UUID, fields []string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"careers_site_updated",
+		"company",
+		&companyID,
+		"Careers site updated: "+strings.Join(fields, ", "),
+		map[string]interface{}{
+			"fields": fields,
+		},
+	)
+}
+
+// LogDataExported logs a spreadsheet export of candidate data
+func LogDataExported(companyID, adminID uuid.UUID, source, format string, rowCount int, columns []string, exportID *uuid.UUID) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"data_exported",
+		"export",
+		exportID,
+		"Exported "+strconv.Itoa(rowCount)+" "+source+" row(s) as "+strings.ToUpper(format),
+		map[string]interface{}{
+			"source":     source,
+			"format":     format,
+			"row_count":  rowCount,
+			"columns":    columns,
+			"background": exportID != nil,
+		},
+	)
+}
+
+// LogCandidatesImported logs a bulk candidate import
+func LogCandidatesImported(companyID, adminID uuid.UUID, report ImportReport, csvName, archiveName string) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"candidates_imported",
+		"application",
+		nil,
+		"Imported "+strconv.Itoa(report.Imported)+" of "+strconv.Itoa(report.Total)+" candidate(s) from "+csvName,
+		map[string]interface{}{
+			"csv_file":        csvName,
+			"cv_archive":      archiveName,
+			"total":           report.Total,
+			"imported":        report.Imported,
+			"skipped":         report.Skipped,
+			"failed":          report.Failed,
+			"unmatched_files": len(report.UnmatchedFiles),
+		},
+	)
+}
+
+// LogCandidateDataExported logs a data subject access export of everything held about a candidate
+func LogCandidateDataExported(companyID, adminID uuid.UUID, email string, applicationCount int) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"candidate_data_exported",
+		"candidate",
+		nil,
+		"Exported the data of a candidate with "+strconv.Itoa(applicationCount)+" application(s)",
+		map[string]interface{}{
+			"email":        email,
+			"applications": applicationCount,
+		},
+	)
+}
+
+// LogCandidateErased logs an erasure request for a candidate. The candidate isn't named,
+// since the point of the request is that nothing identifying them is kept.
+func LogCandidateErased(companyID, adminID uuid.UUID, action string, applicationCount int) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"candidate_erased",
+		"candidate",
+		nil,
+		"Erased a candidate's data ("+action+") from "+strconv.Itoa(applicationCount)+" application(s)",
+		map[string]interface{}{
+			"action":       action,
+			"applications": applicationCount,
+		},
+	)
+}
+
+// LogApplicationErased logs when an application is deleted or anonymised for privacy. adminID is nil
+// when a retention policy erased it, with reason saying why (e.g. "retention_policy").
+func LogApplicationErased(companyID uuid.UUID, adminID *uuid.UUID, applicationID uuid.UUID, action, reason string) {
+	actionType := "application_anonymised"
+	description := "Application anonymised"
+	if action == models.RetentionDelete {
+		actionType = "application_purged"
+		description = "Application permanently deleted"
+	}
+	LogActivity(
+		&companyID,
+		adminID,
+		actionType,
+		"application",
+		&applicationID,
+		description,
+		map[string]interface{}{
+			"reason": reason,
+		},
+	)
+}
+
+// LogRetentionPolicyChanged logs when a retention policy is created, updated or deleted
+func LogRetentionPolicyChanged(companyID, adminID uuid.UUID, policy models.RetentionPolicy, change string) {
+	stage := policy.Stage
+	if stage == "" {
+		stage = "any stage"
+	}
+	LogActivity(
+		&companyID,
+		&adminID,
+		"retention_policy_"+change,
+		"retention_policy",
+		&policy.ID,
+		"Retention policy "+change+": "+policy.Action+" "+stage+" candidates after "+strconv.Itoa(policy.Months)+" month(s)",
+		map[string]interface{}{
+			"stage":     policy.Stage,
+			"months":    policy.Months,
+			"action":    policy.Action,
+			"is_active": policy.IsActive,
+		},
+	)
+}
+
+// LogPrivacyNoticePublished logs when a new version of the company's privacy notice is published
+func LogPrivacyNoticePublished(companyID, adminID uuid.UUID, notice models.PrivacyNotice) {
+	LogActivity(
+		&companyID,
+		&adminID,
+		"privacy_notice_published",
+		"privacy_notice",
+		&notice.ID,
+		"Privacy notice version "+strconv.Itoa(notice.Version)+" published: "+notice.Title,
+		map[string]interface{}{
+			"version": notice.Version,
+			"title":   notice.Title,
+		},
+	)
+}
+
+// LogCandidateConsentUpdated logs when a candidate changes their opt-ins from the candidate portal
+func LogCandidateConsentUpdated(companyID uuid.UUID, applicationID uuid.UUID, candidateName string, talentPool, marketing *bool, ip string) {
+	metadata := map[string]interface{}{
+		"candidate_name": candidateName,
+		"ip":             ip,
+	}
+	if talentPool != nil {
+		metadata["talent_pool_consent"] = *talentPool
+	}
+	if marketing != nil {
+		metadata["marketing_consent"] = *marketing
+	}
+	LogActivity(
+		&companyID,
+		nil,
+		"candidate_consent_updated",
+		"application",
+		&applicationID,
+		"Candidate updated their consent: "+candidateName,
+		metadata,
+	)
+}
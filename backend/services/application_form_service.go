@@ -0,0 +1,424 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// validQuestionTypes lists the supported form question types
+var validQuestionTypes = map[string]bool{
+	models.QuestionTypeText:         true,
+	models.QuestionTypeNumber:       true,
+	models.QuestionTypeSingleChoice: true,
+	models.QuestionTypeMultiChoice:  true,
+	models.QuestionTypeYesNo:        true,
+	models.QuestionTypeDate:         true,
+	models.QuestionTypeFile:         true,
+}
+
+var questionKeyPattern = regexp.MustCompile(`^[a-z0-9_]+$`)
+
+// FormAnswerError describes an invalid or missing answer
+type FormAnswerError struct {
+	QuestionKey string `json:"question_key"`
+	Message     string `json:"message"`
+}
+
+// ScreeningOutcome is the result of checking an application's answers against the knockout rules
+type ScreeningOutcome struct {
+	Result  string   `json:"result"`            // passed, flagged, knocked_out
+	Reasons []string `json:"reasons,omitempty"` // Labels of the questions that triggered a rule
+}
+
+// decodeStringList decodes a JSON array of strings stored in a jsonb column
+func decodeStringList(raw string) []string {
+	values := []string{}
+	if strings.TrimSpace(raw) == "" {
+		return values
+	}
+	_ = json.Unmarshal([]byte(raw), &values)
+	return values
+}
+
+// FormQuestionOptions returns the choices of a single or multi choice question
+func FormQuestionOptions(question models.FormQuestion) []string {
+	return decodeStringList(question.Options)
+}
+
+// ValidateFormQuestions checks a form definition before it's saved
+func ValidateFormQuestions(questions []models.FormQuestion) error {
+	keys := map[string]bool{}
+	for _, question := range questions {
+		if !questionKeyPattern.MatchString(question.Key) {
+			return fmt.Errorf("question key %q may only contain lowercase letters, digits and underscores", question.Key)
+		}
+		if keys[question.Key] {
+			return fmt.Errorf("question key %q is used twice", question.Key)
+		}
+		keys[question.Key] = true
+
+		if strings.TrimSpace(question.Label) == "" {
+			return fmt.Errorf("question %q needs a label", question.Key)
+		}
+		if !validQuestionTypes[question.Type] {
+			return fmt.Errorf("question %q has an unknown type %q", question.Key, question.Type)
+		}
+
+		options := FormQuestionOptions(question)
+		isChoice := question.Type == models.QuestionTypeSingleChoice || question.Type == models.QuestionTypeMultiChoice
+		if isChoice && len(options) < 2 {
+			return fmt.Errorf("question %q needs at least two options", question.Key)
+		}
+		if question.MinValue != nil && question.MaxValue != nil && *question.MinValue > *question.MaxValue {
+			return fmt.Errorf("question %q has min_value above max_value", question.Key)
+		}
+
+		switch question.KnockoutAction {
+		case "":
+			continue
+		case models.KnockoutActionReject, models.KnockoutActionFlag:
+		default:
+			return fmt.Errorf("question %q has an unknown knockout_action %q", question.Key, question.KnockoutAction)
+		}
+		knockoutValues := decodeStringList(question.KnockoutValues)
+		if len(knockoutValues) == 0 && question.KnockoutBelow == nil && question.KnockoutAbove == nil {
+			return fmt.Errorf("knockout question %q needs knockout_values, knockout_below or knockout_above", question.Key)
+		}
+		if (question.KnockoutBelow != nil || question.KnockoutAbove != nil) && question.Type != models.QuestionTypeNumber {
+			return fmt.Errorf("only number questions can use knockout_below and knockout_above")
+		}
+		for _, value := range knockoutValues {
+			if isChoice && !containsString(options, value) {
+				return fmt.Errorf("knockout value %q of question %q is not one of its options", value, question.Key)
+			}
+			if question.Type == models.QuestionTypeYesNo && value != "yes" && value != "no" {
+				return fmt.Errorf("knockout values of yes/no question %q must be yes or no", question.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// GetJobApplicationForm returns the application form of a job with its questions in order,
+// or nil when the job has none
+func GetJobApplicationForm(jobID uuid.UUID) (*models.ApplicationForm, error) {
+	var form models.ApplicationForm
+	err := config.DB.Where("job_id = ?", jobID).
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&form).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &form, nil
+}
+
+// ValidateFormAnswers checks the submitted answers (keyed by question key) against the form
+// and returns them normalized, ready to store
+func ValidateFormAnswers(form *models.ApplicationForm, submitted map[string]interface{}) ([]models.ApplicationAnswer, []FormAnswerError) {
+	answers := []models.ApplicationAnswer{}
+	errs := []FormAnswerError{}
+	if form == nil {
+		return answers, errs
+	}
+
+	known := map[string]bool{}
+	for _, question := range form.Questions {
+		known[question.Key] = true
+		raw, given := submitted[question.Key]
+		if !given || isBlankAnswer(raw) {
+			if question.Required {
+				errs = append(errs, FormAnswerError{QuestionKey: question.Key, Message: "This question is required"})
+			}
+			continue
+		}
+
+		answer, err := normalizeAnswer(question, raw)
+		if err != nil {
+			errs = append(errs, FormAnswerError{QuestionKey: question.Key, Message: err.Error()})
+			continue
+		}
+		answers = append(answers, *answer)
+	}
+	for key := range submitted {
+		if !known[key] {
+			errs = append(errs, FormAnswerError{QuestionKey: key, Message: "Unknown question"})
+		}
+	}
+	return answers, errs
+}
+
+// isBlankAnswer reports whether an answer was left empty
+func isBlankAnswer(raw interface{}) bool {
+	switch value := raw.(type) {
+	case nil:
+		return true
+	case string:
+		return strings.TrimSpace(value) == ""
+	case []interface{}:
+		return len(value) == 0
+	}
+	return false
+}
+
+// normalizeAnswer checks one answer against its question's type and converts it to its stored form
+func normalizeAnswer(question models.FormQuestion, raw interface{}) (*models.ApplicationAnswer, error) {
+	answer := &models.ApplicationAnswer{
+		QuestionID:    question.ID,
+		QuestionKey:   question.Key,
+		QuestionLabel: question.Label,
+		Type:          question.Type,
+	}
+
+	switch question.Type {
+	case models.QuestionTypeText:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected text")
+		}
+		text = strings.TrimSpace(text)
+		if question.MaxLength > 0 && len([]rune(text)) > question.MaxLength {
+			return nil, fmt.Errorf("must be at most %d characters", question.MaxLength)
+		}
+		answer.Value = text
+
+	case models.QuestionTypeNumber:
+		var number float64
+		switch value := raw.(type) {
+		case float64:
+			number = value
+		case string:
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil || math.IsNaN(parsed) || math.IsInf(parsed, 0) {
+				return nil, fmt.Errorf("expected a number")
+			}
+			number = parsed
+		default:
+			return nil, fmt.Errorf("expected a number")
+		}
+		if question.MinValue != nil && number < *question.MinValue {
+			return nil, fmt.Errorf("must be at least %g", *question.MinValue)
+		}
+		if question.MaxValue != nil && number > *question.MaxValue {
+			return nil, fmt.Errorf("must be at most %g", *question.MaxValue)
+		}
+		answer.Value = strconv.FormatFloat(number, 'f', -1, 64)
+		answer.NumberValue = &number
+
+	case models.QuestionTypeSingleChoice:
+		choice, ok := raw.(string)
+		if !ok || !containsString(FormQuestionOptions(question), choice) {
+			return nil, fmt.Errorf("must be one of: %s", strings.Join(FormQuestionOptions(question), ", "))
+		}
+		answer.Value = choice
+
+	case models.QuestionTypeMultiChoice:
+		items, ok := raw.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected a list of choices")
+		}
+		options := FormQuestionOptions(question)
+		choices := []string{}
+		for _, item := range items {
+			choice, ok := item.(string)
+			if !ok || !containsString(options, choice) {
+				return nil, fmt.Errorf("every choice must be one of: %s", strings.Join(options, ", "))
+			}
+			if !containsString(choices, choice) {
+				choices = append(choices, choice)
+			}
+		}
+		encoded, _ := json.Marshal(choices)
+		answer.Value = string(encoded)
+
+	case models.QuestionTypeYesNo:
+		switch value := raw.(type) {
+		case bool:
+			answer.Value = "no"
+			if value {
+				answer.Value = "yes"
+			}
+		case string:
+			value = strings.ToLower(strings.TrimSpace(value))
+			if value != "yes" && value != "no" {
+				return nil, fmt.Errorf("must be yes or no")
+			}
+			answer.Value = value
+		default:
+			return nil, fmt.Errorf("must be yes or no")
+		}
+
+	case models.QuestionTypeDate:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected a date (YYYY-MM-DD)")
+		}
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(text))
+		if err != nil {
+			return nil, fmt.Errorf("expected a date (YYYY-MM-DD)")
+		}
+		answer.Value = date.Format("2006-01-02")
+
+	case models.QuestionTypeFile:
+		text, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected the URL of an uploaded file")
+		}
+		parsed, err := url.Parse(strings.TrimSpace(text))
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return nil, fmt.Errorf("expected the URL of an uploaded file")
+		}
+		answer.Value = parsed.String()
+	}
+
+	return answer, nil
+}
+
+// answerValues returns the individual values of an answer (one per choice for multi choice)
+func answerValues(answer models.ApplicationAnswer) []string {
+	if answer.Type == models.QuestionTypeMultiChoice {
+		return decodeStringList(answer.Value)
+	}
+	return []string{answer.Value}
+}
+
+// ApplyKnockoutRules marks the answers that trigger their question's knockout rule and returns
+// the screening outcome. A "reject" rule wins over a "flag" rule.
+func ApplyKnockoutRules(form *models.ApplicationForm, answers []models.ApplicationAnswer) ScreeningOutcome {
+	outcome := ScreeningOutcome{Result: models.ScreeningPassed}
+	if form == nil {
+		return outcome
+	}
+
+	questions := map[string]models.FormQuestion{}
+	for _, question := range form.Questions {
+		questions[question.Key] = question
+	}
+
+	for i := range answers {
+		question, ok := questions[answers[i].QuestionKey]
+		if !ok || question.KnockoutAction == "" {
+			continue
+		}
+
+		triggered := false
+		knockoutValues := decodeStringList(question.KnockoutValues)
+		for _, value := range answerValues(answers[i]) {
+			if containsString(knockoutValues, value) {
+				triggered = true
+			}
+		}
+		if number := answers[i].NumberValue; number != nil {
+			if question.KnockoutBelow != nil && *number < *question.KnockoutBelow {
+				triggered = true
+			}
+			if question.KnockoutAbove != nil && *number > *question.KnockoutAbove {
+				triggered = true
+			}
+		}
+		if !triggered {
+			continue
+		}
+
+		answers[i].KnockedOut = true
+		outcome.Reasons = append(outcome.Reasons, question.Label)
+		if question.KnockoutAction == models.KnockoutActionReject {
+			outcome.Result = models.ScreeningKnockedOut
+		} else if outcome.Result == models.ScreeningPassed {
+			outcome.Result = models.ScreeningFlagged
+		}
+	}
+	return outcome
+}
+
+// AnswerFilter filters applications on a screening answer
+type AnswerFilter struct {
+	QuestionKey string   `json:"question_key"`
+	Equals      string   `json:"equals,omitempty"`   // Exact answer, or one of the choices for multi choice
+	Contains    string   `json:"contains,omitempty"` // Case-insensitive substring of the answer
+	Min         *float64 `json:"min,omitempty"`      // Number answers
+	Max         *float64 `json:"max,omitempty"`      // Number answers
+}
+
+// MatchesAnswer reports whether an answer passes the filter
+func (filter AnswerFilter) MatchesAnswer(answer models.ApplicationAnswer) bool {
+	if answer.QuestionKey != filter.QuestionKey {
+		return false
+	}
+	if filter.Equals != "" && !containsFold(answerValues(answer), filter.Equals) {
+		return false
+	}
+	if filter.Contains != "" && !strings.Contains(strings.ToLower(answer.Value), strings.ToLower(filter.Contains)) {
+		return false
+	}
+	if filter.Min != nil || filter.Max != nil {
+		if answer.NumberValue == nil {
+			return false
+		}
+		if filter.Min != nil && *answer.NumberValue < *filter.Min {
+			return false
+		}
+		if filter.Max != nil && *answer.NumberValue > *filter.Max {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyAnswerFilterSQL restricts a query on applications to those with an answer passing the filter
+func ApplyAnswerFilterSQL(query *gorm.DB, filter AnswerFilter) *gorm.DB {
+	sub := config.DB.Table("application_answers").
+		Select("1").
+		Where("application_answers.application_id = applications.id AND application_answers.question_key = ?", filter.QuestionKey)
+	if filter.Equals != "" {
+		// Multi choice answers are stored as a JSON array
+		sub = sub.Where(`(LOWER(application_answers.value) = LOWER(?) OR (application_answers.type = ? AND EXISTS (
+			SELECT 1 FROM jsonb_array_elements_text(application_answers.value::jsonb) AS choice WHERE LOWER(choice) = LOWER(?))))`,
+			filter.Equals, models.QuestionTypeMultiChoice, filter.Equals)
+	}
+	if filter.Contains != "" {
+		sub = sub.Where("application_answers.value ILIKE ?", "%"+filter.Contains+"%")
+	}
+	if filter.Min != nil {
+		sub = sub.Where("application_answers.number_value >= ?", *filter.Min)
+	}
+	if filter.Max != nil {
+		sub = sub.Where("application_answers.number_value <= ?", *filter.Max)
+	}
+	return query.Where("EXISTS (?)", sub)
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// containsFold reports whether values contains value, ignoring case
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"ats-backend/models"
+	"reflect"
+	"testing"
+)
+
+func TestApplyKnockoutRules(t *testing.T) {
+	three, ten := 3.0, 10.0
+	form := &models.ApplicationForm{
+		Questions: []models.FormQuestion{
+			{Key: "work_permit", Label: "Work permit", Type: models.QuestionTypeYesNo,
+				KnockoutAction: models.KnockoutActionReject, KnockoutValues: `["no"]`},
+			{Key: "years", Label: "Years of Go", Type: models.QuestionTypeNumber,
+				KnockoutAction: models.KnockoutActionFlag, KnockoutBelow: &three, KnockoutAbove: &ten},
+			{Key: "tools", Label: "Tools", Type: models.QuestionTypeMultiChoice,
+				KnockoutAction: models.KnockoutActionFlag, KnockoutValues: `["none"]`},
+			{Key: "notes", Label: "Notes", Type: models.QuestionTypeText},
+		},
+	}
+	number := func(value float64) *float64 { return &value }
+
+	tests := []struct {
+		name        string
+		answers     []models.ApplicationAnswer
+		want        string
+		wantReasons []string
+		knockedOut  []bool
+	}{
+		{
+			name: "no rule triggered",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "work_permit", Type: models.QuestionTypeYesNo, Value: "yes"},
+				{QuestionKey: "years", Type: models.QuestionTypeNumber, Value: "5", NumberValue: number(5)},
+			},
+			want:       models.ScreeningPassed,
+			knockedOut: []bool{false, false},
+		},
+		{
+			name: "reject value",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "work_permit", Type: models.QuestionTypeYesNo, Value: "no"},
+			},
+			want:        models.ScreeningKnockedOut,
+			wantReasons: []string{"Work permit"},
+			knockedOut:  []bool{true},
+		},
+		{
+			name: "number below the bound flags",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "years", Type: models.QuestionTypeNumber, Value: "1", NumberValue: number(1)},
+			},
+			want:        models.ScreeningFlagged,
+			wantReasons: []string{"Years of Go"},
+			knockedOut:  []bool{true},
+		},
+		{
+			name: "number above the bound flags",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "years", Type: models.QuestionTypeNumber, Value: "12", NumberValue: number(12)},
+			},
+			want:        models.ScreeningFlagged,
+			wantReasons: []string{"Years of Go"},
+			knockedOut:  []bool{true},
+		},
+		{
+			name: "number on the bound passes",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "years", Type: models.QuestionTypeNumber, Value: "3", NumberValue: number(3)},
+			},
+			want:       models.ScreeningPassed,
+			knockedOut: []bool{false},
+		},
+		{
+			name: "one of several choices",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "tools", Type: models.QuestionTypeMultiChoice, Value: `["vim","none"]`},
+			},
+			want:        models.ScreeningFlagged,
+			wantReasons: []string{"Tools"},
+			knockedOut:  []bool{true},
+		},
+		{
+			name: "reject wins over flag",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "years", Type: models.QuestionTypeNumber, Value: "1", NumberValue: number(1)},
+				{QuestionKey: "work_permit", Type: models.QuestionTypeYesNo, Value: "no"},
+			},
+			want:        models.ScreeningKnockedOut,
+			wantReasons: []string{"Years of Go", "Work permit"},
+			knockedOut:  []bool{true, true},
+		},
+		{
+			name: "question without a rule or not on the form",
+			answers: []models.ApplicationAnswer{
+				{QuestionKey: "notes", Type: models.QuestionTypeText, Value: "no"},
+				{QuestionKey: "removed", Type: models.QuestionTypeYesNo, Value: "no"},
+			},
+			want:       models.ScreeningPassed,
+			knockedOut: []bool{false, false},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome := ApplyKnockoutRules(form, tt.answers)
+			if outcome.Result != tt.want {
+				t.Errorf("Result = %q, want %q", outcome.Result, tt.want)
+			}
+			if !reflect.DeepEqual(outcome.Reasons, tt.wantReasons) {
+				t.Errorf("Reasons = %v, want %v", outcome.Reasons, tt.wantReasons)
+			}
+			for i, answer := range tt.answers {
+				if answer.KnockedOut != tt.knockedOut[i] {
+					t.Errorf("answer %q KnockedOut = %v, want %v", answer.QuestionKey, answer.KnockedOut, tt.knockedOut[i])
+				}
+			}
+		})
+	}
+}
+
+func TestApplyKnockoutRulesWithoutForm(t *testing.T) {
+	outcome := ApplyKnockoutRules(nil, []models.ApplicationAnswer{{QuestionKey: "work_permit", Value: "no"}})
+	if outcome.Result != models.ScreeningPassed || len(outcome.Reasons) != 0 {
+		t.Errorf("ApplyKnockoutRules(nil) = %+v, want passed without reasons", outcome)
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// StageTransitionError is returned when a stage change isn't allowed from the current stage
+type StageTransitionError struct {
+	From    string
+	To      string
+	Message string
+}
+
+func (e *StageTransitionError) Error() string {
+	return e.Message
+}
+
+// StatusChangeActor identifies who made a status change
+type StatusChangeActor struct {
+	Type string     // models.StatusActorAdmin, StatusActorCandidate or StatusActorSystem
+	ID   *uuid.UUID // Admin ID for admin changes
+}
+
+// AdminActor returns the actor for a change made by an admin
+func AdminActor(adminID uuid.UUID) StatusChangeActor {
+	if adminID == uuid.Nil {
+		return StatusChangeActor{Type: models.StatusActorAdmin}
+	}
+	return StatusChangeActor{Type: models.StatusActorAdmin, ID: &adminID}
+}
+
+// isTerminalStage reports whether a stage ends the process (hired, rejected or withdrawn)
+func isTerminalStage(stage *models.PipelineStage) bool {
+	return stage.Type == models.StageTypeHired || stage.Type == models.StageTypeRejected ||
+		stage.Type == models.StageTypeWithdrawn
+}
+
+// IsClosedStatus reports whether an application in the given stage has left the process
+func IsClosedStatus(stages []models.PipelineStage, status string) bool {
+	stage, found := FindPipelineStage(stages, status)
+	return found && isTerminalStage(stage)
+}
+
+// ValidateStageTransition checks a move between two stages of a pipeline.
+// Active stages can move to any other stage; leaving a hired or rejected stage needs an explicit reopen.
+func ValidateStageTransition(stages []models.PipelineStage, from, to string, reopen bool) error {
+	toStage, found := FindPipelineStage(stages, to)
+	if !found {
+		return &StageTransitionError{From: from, To: to, Message: fmt.Sprintf("stage %q is not part of this job's pipeline", to)}
+	}
+	if from == to {
+		return &StageTransitionError{From: from, To: to, Message: fmt.Sprintf("application is already in stage %q", toStage.Name)}
+	}
+
+	fromStage, known := FindPipelineStage(stages, from)
+	if !known {
+		// The stage was removed from the pipeline since - let the application move on
+		return nil
+	}
+	if isTerminalStage(fromStage) && !reopen {
+		return &StageTransitionError{
+			From:    from,
+			To:      to,
+			Message: fmt.Sprintf("cannot move from %q to %q: the application is closed, reopen it first", fromStage.Name, toStage.Name),
+		}
+	}
+	if reopen && !isTerminalStage(fromStage) {
+		return &StageTransitionError{From: from, To: to, Message: "only hired, rejected or withdrawn applications can be reopened"}
+	}
+	return nil
+}
+
+// AllowedStageTransitions returns the stage keys an application can move to without reopening
+func AllowedStageTransitions(stages []models.PipelineStage, from string) []string {
+	allowed := []string{}
+	for _, stage := range stages {
+		if ValidateStageTransition(stages, from, stage.Key, false) == nil {
+			allowed = append(allowed, stage.Key)
+		}
+	}
+	return allowed
+}
+
+// recordStatusChange writes a history entry for the application's current status
+func recordStatusChange(tx *gorm.DB, application *models.Application, fromStage string, actor StatusChangeActor, reason string, at time.Time) error {
+	change := models.ApplicationStatusChange{
+		ApplicationID: application.ID,
+		CompanyID:     application.CompanyID,
+		FromStage:     fromStage,
+		ToStage:       application.Status,
+		ActorType:     actor.Type,
+		ActorID:       actor.ID,
+		Reason:        reason,
+		CreatedAt:     at,
+	}
+	return tx.Create(&change).Error
+}
+
+// CreateApplicationWithHistory creates an application and its initial history entry in one transaction.
+// An application created straight into a hired stage counts towards its job's headcount.
+func CreateApplicationWithHistory(application *models.Application, actor StatusChangeActor) error {
+	hired := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(application).Error; err != nil {
+			return err
+		}
+		if err := recordStatusChange(tx, application, "", actor, "", application.AppliedAt); err != nil {
+			return err
+		}
+		if application.JobID == nil {
+			return nil
+		}
+		stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+		if err != nil {
+			return err
+		}
+		if isHiredStatus(stages, application.Status) {
+			hired = 1
+		}
+		return changeHiredCount(tx, application.JobID, hired)
+	})
+	if err == nil && hired > 0 {
+		go CloseJobIfFilled(*application.JobID)
+	}
+	return err
+}
+
+// isHiredStatus reports whether an application in the given stage counts as a hire
+func isHiredStatus(stages []models.PipelineStage, status string) bool {
+	stage, found := FindPipelineStage(stages, status)
+	return found && stage.Type == models.StageTypeHired
+}
+
+// hiredCountChange returns how a job's hired count changes when an application moves between two stages
+func hiredCountChange(stages []models.PipelineStage, fromStage, toStage string) int {
+	switch {
+	case !isHiredStatus(stages, fromStage) && isHiredStatus(stages, toStage):
+		return 1
+	case isHiredStatus(stages, fromStage) && !isHiredStatus(stages, toStage):
+		return -1
+	}
+	return 0
+}
+
+// changeHiredCount adds change to a job's hired count inside tx, never going below zero
+func changeHiredCount(tx *gorm.DB, jobID *uuid.UUID, change int) error {
+	if jobID == nil || change == 0 {
+		return nil
+	}
+	return tx.Model(&models.Job{}).Where("id = ?", *jobID).
+		Update("hired_count", gorm.Expr("GREATEST(hired_count + ?, 0)", change)).Error
+}
+
+// SaveApplicationStatus saves an application whose Status was changed from fromStage, writing the
+// history entry and updating its job's hired count in the same transaction. The stored application is
+// locked and the move validated inside the transaction, so concurrent moves can't both go through; a
+// move that lost the race gets a StageTransitionError. reopen allows leaving a closed stage. A job whose
+// last position was just filled is then closed, unless it's kept open when filled.
+func SaveApplicationStatus(application *models.Application, fromStage string, actor StatusChangeActor, reason string, reopen bool) error {
+	hired := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var err error
+		hired, err = saveApplicationStatusTx(tx, application, fromStage, actor, reason, reopen)
+		return err
+	})
+	if err == nil && hired > 0 {
+		go CloseJobIfFilled(*application.JobID)
+	}
+	return err
+}
+
+// saveApplicationStatusTx does the work of SaveApplicationStatus inside tx, for callers that change
+// more in the same transaction. It returns how the job's hired count changed; when it went up, the
+// caller closes the job with CloseJobIfFilled once tx is committed.
+func saveApplicationStatusTx(tx *gorm.DB, application *models.Application, fromStage string, actor StatusChangeActor, reason string, reopen bool) (int, error) {
+	if fromStage == application.Status {
+		return 0, tx.Save(application).Error
+	}
+
+	stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		return 0, err
+	}
+	var current models.Application
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id, status").
+		First(&current, "id = ?", application.ID).Error; err != nil {
+		return 0, err
+	}
+	if current.Status != fromStage {
+		return 0, &StageTransitionError{
+			From:    current.Status,
+			To:      application.Status,
+			Message: fmt.Sprintf("the application was moved to %q in the meantime", current.Status),
+		}
+	}
+	if err := ValidateStageTransition(stages, fromStage, application.Status, reopen); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Save(application).Error; err != nil {
+		return 0, err
+	}
+	if err := recordStatusChange(tx, application, fromStage, actor, reason, time.Now()); err != nil {
+		return 0, err
+	}
+	if application.JobID == nil {
+		return 0, nil
+	}
+	hired := hiredCountChange(stages, fromStage, application.Status)
+	return hired, changeHiredCount(tx, application.JobID, hired)
+}
+
+// GetApplicationStatusHistory returns an application's stage history, oldest first
+func GetApplicationStatusHistory(applicationID uuid.UUID) ([]models.ApplicationStatusChange, error) {
+	var history []models.ApplicationStatusChange
+	err := config.DB.Where("application_id = ?", applicationID).
+		Preload("Actor").
+		Order("created_at ASC").
+		Find(&history).Error
+	return history, err
+}
+
+// AdvanceApplicationStage moves an application forward to the given stage as a side effect of
+// another action (e.g. booking an interview). It never moves an application backwards or out of
+// a closed stage, and does nothing when the job's pipeline has no such stage.
+// Returns true when the application was moved.
+func AdvanceApplicationStage(application *models.Application, stageKey string, actor StatusChangeActor, reason string) (bool, error) {
+	stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		return false, err
+	}
+	target, found := FindPipelineStage(stages, stageKey)
+	if !found {
+		return false, nil
+	}
+	if current, known := FindPipelineStage(stages, application.Status); known && current.Position >= target.Position {
+		return false, nil
+	}
+	if err := ValidateStageTransition(stages, application.Status, stageKey, false); err != nil {
+		return false, nil
+	}
+
+	fromStage := application.Status
+	now := time.Now()
+	application.Status = stageKey
+	application.LastStatusUpdate = &now
+	if err := SaveApplicationStatus(application, fromStage, actor, reason, false); err != nil {
+		application.Status = fromStage
+		var transitionErr *StageTransitionError
+		if errors.As(err, &transitionErr) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
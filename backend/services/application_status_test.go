@@ -0,0 +1,101 @@
+package services
+
+import (
+	"ats-backend/models"
+	"errors"
+	"testing"
+)
+
+func TestValidateStageTransition(t *testing.T) {
+	stages := DefaultPipelineStages()
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		reopen  bool
+		wantErr bool
+	}{
+		{"active to active", "pending", "shortlisted", false, false},
+		{"active back to an earlier stage", "interview_scheduled", "cv_viewed", false, false},
+		{"active to hired", "offer", "hired", false, false},
+		{"active to rejected", "under_review", "rejected", false, false},
+		{"active to withdrawn", "pending", "withdrawn", false, false},
+		{"same stage", "shortlisted", "shortlisted", false, true},
+		{"unknown target stage", "pending", "phone_screen", false, true},
+		{"leaving hired without reopen", "hired", "offer", false, true},
+		{"leaving rejected without reopen", "rejected", "pending", false, true},
+		{"leaving withdrawn without reopen", "withdrawn", "pending", false, true},
+		{"reopening rejected", "rejected", "pending", true, false},
+		{"reopening hired", "hired", "offer", true, false},
+		{"reopening an active application", "pending", "shortlisted", true, true},
+		{"from a stage removed from the pipeline", "phone_screen", "shortlisted", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStageTransition(stages, tt.from, tt.to, tt.reopen)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ValidateStageTransition(%q, %q, reopen=%v) = %v, want error: %v", tt.from, tt.to, tt.reopen, err, tt.wantErr)
+			}
+			var transitionErr *StageTransitionError
+			if err != nil && !errors.As(err, &transitionErr) {
+				t.Errorf("error %v isn't a StageTransitionError", err)
+			}
+		})
+	}
+}
+
+func TestValidateStageTransitionCustomPipeline(t *testing.T) {
+	stages := withWithdrawnStage([]models.PipelineStage{
+		{Key: "new", Name: "New", Type: models.StageTypeActive, Position: 0},
+		{Key: "tech_interview", Name: "Tech Interview", Type: models.StageTypeActive, Position: 1},
+		{Key: "signed", Name: "Signed", Type: models.StageTypeHired, Position: 2},
+		{Key: "declined", Name: "Declined", Type: models.StageTypeRejected, Position: 3},
+	})
+
+	tests := []struct {
+		name    string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{"custom active stages", "new", "tech_interview", false},
+		{"custom hired stage closes the application", "signed", "tech_interview", true},
+		{"custom rejected stage closes the application", "declined", "new", true},
+		{"built-in key missing from the custom pipeline", "new", "shortlisted", true},
+		{"withdrawn stage added to the custom pipeline", "tech_interview", "withdrawn", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStageTransition(stages, tt.from, tt.to, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateStageTransition(%q, %q) = %v, want error: %v", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHiredCountChange(t *testing.T) {
+	stages := DefaultPipelineStages()
+
+	tests := []struct {
+		from string
+		to   string
+		want int
+	}{
+		{"offer", "hired", 1},
+		{"hired", "offer", -1},
+		{"hired", "rejected", -1},
+		{"pending", "rejected", 0},
+		{"hired", "hired", 0},
+		{"", "hired", 1},
+	}
+
+	for _, tt := range tests {
+		if got := hiredCountChange(stages, tt.from, tt.to); got != tt.want {
+			t.Errorf("hiredCountChange(%q, %q) = %d, want %d", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/utils"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// CandidateMagicLinkTTL is how long a sign-in link emailed to a candidate can be used
+	CandidateMagicLinkTTL = 15 * time.Minute
+	// CandidateSessionTTL is how long a candidate stays signed in to the portal
+	CandidateSessionTTL = 24 * time.Hour
+	// maxMagicLinksPerHour limits how many sign-in links one email address is sent per hour
+	maxMagicLinksPerHour = 5
+)
+
+// ErrInvalidMagicLink is returned when a sign-in link is invalid, expired or already used
+var ErrInvalidMagicLink = errors.New("this sign-in link is invalid or has expired, please request a new one")
+
+// CandidateSession is a signed-in candidate portal session
+type CandidateSession struct {
+	Token     string    `json:"token"`
+	Email     string    `json:"email"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NormalizeCandidateEmail returns the form of an email address candidate sessions are keyed by
+func NormalizeCandidateEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CandidateMagicLinkURL returns the candidate-facing URL that signs in with token
+func CandidateMagicLinkURL(token string) string {
+	return fmt.Sprintf("%s/application-status?token=%s", config.GetEnv("FRONTEND_URL", "http://localhost:3000"), url.QueryEscape(token))
+}
+
+// RequestCandidateMagicLink emails a sign-in link to a candidate. Nothing is sent when the email
+// has no applications or was sent too many links recently, and the caller isn't told either way
+// so the endpoint can't be used to find out who applied.
+func RequestCandidateMagicLink(email, ip string) error {
+	email = NormalizeCandidateEmail(email)
+
+	var applications int64
+	if err := config.DB.Model(&models.Application{}).Where("LOWER(email) = ?", email).Count(&applications).Error; err != nil {
+		return err
+	}
+	if applications == 0 {
+		return nil
+	}
+
+	var recent int64
+	if err := config.DB.Model(&models.CandidateMagicLink{}).
+		Where("email = ? AND created_at > ?", email, time.Now().Add(-time.Hour)).
+		Count(&recent).Error; err != nil {
+		return err
+	}
+	if recent >= maxMagicLinksPerHour {
+		log.Printf("Candidate sign-in link not sent: rate limit reached for %s", email)
+		return nil
+	}
+
+	tokenID, err := utils.GenerateSecureToken(24)
+	if err != nil {
+		return err
+	}
+	link := models.CandidateMagicLink{
+		Email:     email,
+		TokenID:   tokenID,
+		ExpiresAt: time.Now().Add(CandidateMagicLinkTTL),
+		RequestIP: ip,
+	}
+	token, err := utils.GenerateCandidateJWT(email, utils.CandidateMagicLinkPurpose, tokenID, CandidateMagicLinkTTL)
+	if err != nil {
+		return err
+	}
+	if err := config.DB.Create(&link).Error; err != nil {
+		return err
+	}
+
+	go func() {
+		if err := SendCandidateMagicLinkEmail(email, CandidateMagicLinkURL(token), link.ExpiresAt); err != nil {
+			log.Printf("ERROR: Failed to send candidate sign-in link to %s: %v", email, err)
+		} else {
+			log.Printf("SUCCESS: Candidate sign-in link sent to %s", email)
+		}
+	}()
+	return nil
+}
+
+// ExchangeCandidateMagicLink uses up a sign-in link and starts a portal session for its email
+func ExchangeCandidateMagicLink(token string) (CandidateSession, error) {
+	claims, err := utils.VerifyCandidateJWT(token, utils.CandidateMagicLinkPurpose)
+	if err != nil || claims.ID == "" {
+		return CandidateSession{}, ErrInvalidMagicLink
+	}
+
+	// Marking the link used only when it wasn't already lets each link start one session
+	result := config.DB.Model(&models.CandidateMagicLink{}).
+		Where("token_id = ? AND email = ? AND used_at IS NULL AND expires_at > ?", claims.ID, claims.CandidateEmail, time.Now()).
+		UpdateColumn("used_at", time.Now())
+	if result.Error != nil {
+		return CandidateSession{}, result.Error
+	}
+	if result.RowsAffected != 1 {
+		return CandidateSession{}, ErrInvalidMagicLink
+	}
+
+	sessionID, err := utils.GenerateSecureToken(24)
+	if err != nil {
+		return CandidateSession{}, err
+	}
+	session := CandidateSession{
+		Email:     claims.CandidateEmail,
+		ExpiresAt: time.Now().Add(CandidateSessionTTL),
+	}
+	session.Token, err = utils.GenerateCandidateJWT(claims.CandidateEmail, utils.CandidateSessionPurpose, sessionID, CandidateSessionTTL)
+	if err != nil {
+		return CandidateSession{}, err
+	}
+	return session, nil
+}
+
+// PurgeCandidateMagicLinks deletes sign-in links that expired more than a day ago
+func PurgeCandidateMagicLinks() error {
+	result := config.DB.Where("expires_at < ?", time.Now().Add(-24*time.Hour)).Delete(&models.CandidateMagicLink{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("SUCCESS: Deleted %d expired candidate sign-in link(s)", result.RowsAffected)
+	}
+	return nil
+}
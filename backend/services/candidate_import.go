@@ -0,0 +1,441 @@
+package services
+
+import (
+	"archive/zip"
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/mail"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxImportRows is the largest CSV a single import accepts
+const MaxImportRows = 2000
+
+// MaxImportCVSize is the largest CV file accepted from an import archive
+const MaxImportCVSize = 10 * 1024 * 1024
+
+// ImportFields are the application fields a CSV column can be mapped to
+var ImportFields = []string{
+	"full_name", "email", "phone", "current_position", "years_of_experience", "linkedin_url",
+	"portfolio_url", "cover_letter", "referral_source", "tags", "resume_url", "resume_file", "job_id",
+}
+
+// importFieldAliases are header names recognised without an explicit mapping
+var importFieldAliases = map[string]string{
+	"name":       "full_name",
+	"candidate":  "full_name",
+	"e_mail":     "email",
+	"mobile":     "phone",
+	"position":   "current_position",
+	"title":      "current_position",
+	"experience": "years_of_experience",
+	"linkedin":   "linkedin_url",
+	"portfolio":  "portfolio_url",
+	"source":     "referral_source",
+	"cv":         "resume_file",
+	"resume":     "resume_file",
+	"cv_file":    "resume_file",
+	"cv_url":     "resume_url",
+}
+
+// Import row outcomes
+const (
+	ImportRowImported = "imported"
+	ImportRowSkipped  = "skipped" // Duplicate of an existing application or an earlier row
+	ImportRowFailed   = "failed"
+)
+
+// ImportRow is one data row of an import CSV, keyed by application field
+type ImportRow struct {
+	Line   int // Line number in the CSV, the header being line 1
+	Fields map[string]string
+}
+
+// ImportRowResult is the outcome of one CSV row
+type ImportRowResult struct {
+	Line          int        `json:"line"`
+	Email         string     `json:"email,omitempty"`
+	Status        string     `json:"status"` // imported, skipped or failed
+	ApplicationID *uuid.UUID `json:"application_id,omitempty"`
+	CVFile        string     `json:"cv_file,omitempty"` // Archive entry used as the CV
+	Error         string     `json:"error,omitempty"`
+}
+
+// ImportReport summarises an import
+type ImportReport struct {
+	Total          int               `json:"total"`
+	Imported       int               `json:"imported"`
+	Skipped        int               `json:"skipped"`
+	Failed         int               `json:"failed"`
+	Rows           []ImportRowResult `json:"rows"`
+	UnmatchedFiles []string          `json:"unmatched_files"` // Archive CVs no row used
+}
+
+// ImportOptions are the settings shared by every row of an import
+type ImportOptions struct {
+	CompanyID uuid.UUID
+	AdminID   uuid.UUID
+	JobID     *uuid.UUID // Job for rows without a job_id column
+	Status    string     // Stage for every imported application, defaults to the pipeline's first stage
+	Scope     JobScope   // Jobs the importing admin may add candidates to
+}
+
+// ParseImportCSV reads an import CSV. mapping maps application fields to CSV headers;
+// headers that aren't mapped are matched to fields by name.
+func ParseImportCSV(r io.Reader, mapping map[string]string) ([]ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("the CSV is empty or unreadable")
+	}
+
+	columnFields := map[int]string{}
+	mappedHeaders := map[string]string{}
+	for field, headerName := range mapping {
+		if !containsString(ImportFields, field) {
+			return nil, fmt.Errorf("unknown import field %q", field)
+		}
+		mappedHeaders[strings.ToLower(strings.TrimSpace(headerName))] = field
+	}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if field, ok := mappedHeaders[name]; ok {
+			columnFields[i] = field
+			continue
+		}
+		if len(mapping) > 0 {
+			continue
+		}
+		key := NormalizeStageKey(name)
+		if alias, ok := importFieldAliases[key]; ok {
+			key = alias
+		}
+		if containsString(ImportFields, key) {
+			columnFields[i] = key
+		}
+	}
+
+	hasField := map[string]bool{}
+	for _, field := range columnFields {
+		hasField[field] = true
+	}
+	if !hasField["full_name"] || !hasField["email"] {
+		return nil, errors.New("the CSV needs a name and an email column")
+	}
+
+	rows := []ImportRow{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		fields := map[string]string{}
+		empty := true
+		for i, value := range record {
+			if field, ok := columnFields[i]; ok {
+				fields[field] = strings.TrimSpace(value)
+				if fields[field] != "" {
+					empty = false
+				}
+			}
+		}
+		if empty {
+			continue
+		}
+		if len(rows) == MaxImportRows {
+			return nil, fmt.Errorf("an import can have at most %d rows", MaxImportRows)
+		}
+		rows = append(rows, ImportRow{Line: line, Fields: fields})
+	}
+	return rows, nil
+}
+
+// CVArchive is a ZIP of CV files matched to import rows by filename or by the candidate's email
+type CVArchive struct {
+	byName map[string]*zip.File
+	used   map[string]bool
+}
+
+// OpenCVArchive indexes the CV files of a ZIP archive, ignoring folders and unsupported files
+func OpenCVArchive(r io.ReaderAt, size int64) (*CVArchive, error) {
+	reader, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, errors.New("the CV archive is not a valid ZIP file")
+	}
+	archive := &CVArchive{byName: map[string]*zip.File{}, used: map[string]bool{}}
+	for _, file := range reader.File {
+		name := path.Base(file.Name)
+		if file.FileInfo().IsDir() || strings.HasPrefix(name, ".") || strings.Contains(file.Name, "__MACOSX/") {
+			continue
+		}
+		switch strings.ToLower(path.Ext(name)) {
+		case ".pdf", ".doc", ".docx":
+			archive.byName[strings.ToLower(name)] = file
+		}
+	}
+	return archive, nil
+}
+
+// Find returns the CV named filename, or else the one named after the email (e.g. jane@example.com.pdf)
+func (a *CVArchive) Find(filename, email string) *zip.File {
+	if a == nil {
+		return nil
+	}
+	if filename != "" {
+		return a.byName[strings.ToLower(path.Base(filename))]
+	}
+	email = strings.ToLower(email)
+	for _, ext := range []string{".pdf", ".docx", ".doc"} {
+		if file, ok := a.byName[email+ext]; ok {
+			return file
+		}
+	}
+	return nil
+}
+
+// Unused lists the archive CVs that no row was matched to
+func (a *CVArchive) Unused() []string {
+	unused := []string{}
+	if a == nil {
+		return unused
+	}
+	for name, file := range a.byName {
+		if !a.used[name] {
+			unused = append(unused, path.Base(file.Name))
+		}
+	}
+	sort.Strings(unused)
+	return unused
+}
+
+// read returns the content of a CV, refusing files over MaxImportCVSize
+func (a *CVArchive) read(file *zip.File) ([]byte, error) {
+	if file.UncompressedSize64 > MaxImportCVSize {
+		return nil, fmt.Errorf("CV file %s is larger than %d MB", path.Base(file.Name), MaxImportCVSize/(1024*1024))
+	}
+	content, err := file.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CV file %s", path.Base(file.Name))
+	}
+	defer content.Close()
+	// The header size can't be trusted, so never read past the limit
+	data, err := io.ReadAll(io.LimitReader(content, MaxImportCVSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CV file %s", path.Base(file.Name))
+	}
+	if len(data) > MaxImportCVSize {
+		return nil, fmt.Errorf("CV file %s is larger than %d MB", path.Base(file.Name), MaxImportCVSize/(1024*1024))
+	}
+	a.used[strings.ToLower(path.Base(file.Name))] = true
+	return data, nil
+}
+
+// ImportCandidates creates an application per CSV row. CVs come from the archive (uploaded to
+// the storage service) or a resume_url column. Rows whose email already applied to the job,
+// in the database or earlier in the CSV, are skipped. Parsing and scoring are queued.
+func ImportCandidates(rows []ImportRow, archive *CVArchive, options ImportOptions) ImportReport {
+	report := ImportReport{Total: len(rows), Rows: []ImportRowResult{}}
+	jobs := map[uuid.UUID]*models.Job{}
+	seen := map[string]bool{}
+
+	for _, row := range rows {
+		result := importRow(row, archive, options, jobs, seen)
+		switch result.Status {
+		case ImportRowImported:
+			report.Imported++
+		case ImportRowSkipped:
+			report.Skipped++
+		default:
+			report.Failed++
+		}
+		report.Rows = append(report.Rows, result)
+	}
+	report.UnmatchedFiles = archive.Unused()
+	return report
+}
+
+// importRow imports a single row. jobs caches the company's jobs and seen the job/email pairs imported so far.
+func importRow(row ImportRow, archive *CVArchive, options ImportOptions, jobs map[uuid.UUID]*models.Job, seen map[string]bool) ImportRowResult {
+	fields := row.Fields
+	email := strings.ToLower(fields["email"])
+	result := ImportRowResult{Line: row.Line, Email: email, Status: ImportRowFailed}
+
+	if fields["full_name"] == "" {
+		result.Error = "Name is required"
+		return result
+	}
+	if address, err := mail.ParseAddress(email); err != nil || address.Address != email {
+		result.Error = "Invalid email address"
+		return result
+	}
+	years := 0
+	if value := fields["years_of_experience"]; value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 0 {
+			result.Error = "Years of experience must be a whole number"
+			return result
+		}
+		years = parsed
+	}
+
+	// Resolve the job: the row's job_id column or the import's job
+	jobID := options.JobID
+	if value := fields["job_id"]; value != "" {
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			result.Error = "Invalid job ID"
+			return result
+		}
+		jobID = &parsed
+	}
+	if jobID == nil {
+		result.Error = "No job given for this row"
+		return result
+	}
+	job, ok := jobs[*jobID]
+	if !ok {
+		var loaded models.Job
+		if err := config.DB.Where("id = ? AND company_id = ?", *jobID, options.CompanyID).First(&loaded).Error; err == nil {
+			job = &loaded
+		}
+		jobs[*jobID] = job
+	}
+	if job == nil || !options.Scope.Allows(jobID) {
+		result.Error = "Job not found or doesn't belong to your company"
+		return result
+	}
+
+	// De-duplicate against existing applications and earlier rows
+	seenKey := jobID.String() + "/" + email
+	if seen[seenKey] {
+		result.Status = ImportRowSkipped
+		result.Error = "Duplicate of an earlier row"
+		return result
+	}
+	var existing models.Application
+	if err := config.DB.Select("id").Where("job_id = ? AND LOWER(email) = ?", jobID, email).First(&existing).Error; err == nil {
+		result.Status = ImportRowSkipped
+		result.ApplicationID = &existing.ID
+		result.Error = "Candidate already applied for this job"
+		return result
+	}
+
+	status := options.Status
+	if status == "" {
+		status = InitialStageKey(options.CompanyID, jobID)
+	} else if stages, err := GetPipelineStages(options.CompanyID, jobID); err != nil {
+		result.Error = "Failed to load pipeline"
+		return result
+	} else if _, found := FindPipelineStage(stages, status); !found {
+		result.Error = "Status is not a stage of this job's pipeline"
+		return result
+	}
+
+	tags := ""
+	if value := fields["tags"]; value != "" {
+		normalized := NormalizeTags(strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }))
+		for _, tag := range normalized {
+			if len(tag) > MaxTagLength {
+				result.Error = fmt.Sprintf("Tags can be at most %d characters", MaxTagLength)
+				return result
+			}
+		}
+		if len(normalized) > 0 {
+			encoded, _ := json.Marshal(normalized)
+			tags = string(encoded)
+		}
+	}
+
+	// Find the CV: a file from the archive, or a URL from the CSV
+	resumeURL := fields["resume_url"]
+	if file := archive.Find(fields["resume_file"], email); file != nil {
+		data, err := archive.read(file)
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		filename := uuid.New().String()[:8] + "_" + SafeStorageFilename(path.Base(file.Name))
+		url, err := UploadBytesToSupabase(data, filename, "resumes")
+		if err != nil {
+			result.Error = "Failed to upload CV: " + err.Error()
+			return result
+		}
+		resumeURL = url
+		result.CVFile = path.Base(file.Name)
+	} else if fields["resume_file"] != "" {
+		result.Error = "CV file " + fields["resume_file"] + " is not in the archive"
+		return result
+	}
+	if resumeURL == "" {
+		result.Error = "No CV found for this candidate"
+		return result
+	}
+
+	source := fields["referral_source"]
+	if source == "" {
+		source = "import"
+	}
+
+	application := models.Application{
+		JobID:             jobID,
+		CompanyID:         options.CompanyID,
+		FullName:          fields["full_name"],
+		Email:             email,
+		Phone:             fields["phone"],
+		ResumeURL:         resumeURL,
+		CoverLetter:       fields["cover_letter"],
+		YearsOfExperience: years,
+		CurrentPosition:   fields["current_position"],
+		LinkedinURL:       fields["linkedin_url"],
+		PortfolioURL:      fields["portfolio_url"],
+		ReferralSource:    source,
+		Tags:              tags,
+		Status:            status,
+		AppliedAt:         time.Now(),
+	}
+	if err := CreateApplicationWithHistory(&application, AdminActor(options.AdminID)); err != nil {
+		result.Error = "Failed to save candidate"
+		return result
+	}
+	seen[seenKey] = true
+
+	QueueCVProcessing(application.ID)
+
+	result.Status = ImportRowImported
+	result.ApplicationID = &application.ID
+	return result
+}
+
+// SafeStorageFilename keeps letters, digits, dots, dashes and underscores of a filename
+func SafeStorageFilename(name string) string {
+	safe := strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r == ' ':
+			return '_'
+		}
+		return -1
+	}, name)
+	if safe == "" || strings.HasPrefix(safe, ".") {
+		safe = "cv" + safe
+	}
+	return safe
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"hash/crc32"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseImportCSV(t *testing.T) {
+	tests := []struct {
+		name      string
+		csv       string
+		mapping   map[string]string
+		wantErr   string
+		wantLines []int
+		wantRow   map[string]string // Fields of the first row
+	}{
+		{
+			name:      "field names and aliases",
+			csv:       "\ufeffName,E-mail,Mobile,Position,CV,Unknown\nJane Doe, jane@example.com ,123,Engineer,jane.pdf,x\n",
+			wantLines: []int{2},
+			wantRow: map[string]string{
+				"full_name": "Jane Doe", "email": "jane@example.com", "phone": "123",
+				"current_position": "Engineer", "resume_file": "jane.pdf",
+			},
+		},
+		{
+			name:      "explicit mapping ignores other headers",
+			csv:       "Candidate,Address,Email\nJane Doe,jane@example.com,other@example.com\n",
+			mapping:   map[string]string{"full_name": "candidate", "email": " ADDRESS "},
+			wantLines: []int{2},
+			wantRow:   map[string]string{"full_name": "Jane Doe", "email": "jane@example.com"},
+		},
+		{
+			name:    "unknown mapped field",
+			csv:     "name,email\n",
+			mapping: map[string]string{"salary": "name"},
+			wantErr: `unknown import field "salary"`,
+		},
+		{
+			name:    "missing email column",
+			csv:     "name,phone\nJane Doe,123\n",
+			wantErr: "the CSV needs a name and an email column",
+		},
+		{
+			name:    "empty file",
+			csv:     "",
+			wantErr: "the CSV is empty or unreadable",
+		},
+		{
+			name:      "blank rows are skipped but keep line numbers",
+			csv:       "name,email\nJane,jane@example.com\n , \nJohn,john@example.com\n",
+			wantLines: []int{2, 4},
+			wantRow:   map[string]string{"full_name": "Jane", "email": "jane@example.com"},
+		},
+		{
+			name:      "rows up to the limit",
+			csv:       "name,email\n" + strings.Repeat("Jane,jane@example.com\n", MaxImportRows),
+			wantLines: make([]int, MaxImportRows),
+		},
+		{
+			name:    "rows over the limit",
+			csv:     "name,email\n" + strings.Repeat("Jane,jane@example.com\n", MaxImportRows+1),
+			wantErr: fmt.Sprintf("an import can have at most %d rows", MaxImportRows),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ParseImportCSV(strings.NewReader(tt.csv), tt.mapping)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(rows) != len(tt.wantLines) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(tt.wantLines))
+			}
+			if tt.wantRow == nil {
+				return
+			}
+			for i, row := range rows {
+				if row.Line != tt.wantLines[i] {
+					t.Errorf("row %d Line = %d, want %d", i, row.Line, tt.wantLines[i])
+				}
+			}
+			if !reflect.DeepEqual(rows[0].Fields, tt.wantRow) {
+				t.Errorf("Fields = %v, want %v", rows[0].Fields, tt.wantRow)
+			}
+		})
+	}
+}
+
+// zipEntry is a file written to a test archive. size, when set, is the uncompressed
+// size claimed by the entry's header instead of the real one.
+type zipEntry struct {
+	name    string
+	content []byte
+	size    uint64
+}
+
+func buildCVArchive(t *testing.T, entries []zipEntry) *CVArchive {
+	t.Helper()
+	var buf bytes.Buffer
+	writer := zip.NewWriter(&buf)
+	for _, entry := range entries {
+		header := &zip.FileHeader{
+			Name:               entry.name,
+			Method:             zip.Store,
+			CRC32:              crc32.ChecksumIEEE(entry.content),
+			CompressedSize64:   uint64(len(entry.content)),
+			UncompressedSize64: uint64(len(entry.content)),
+		}
+		if entry.size > 0 {
+			header.UncompressedSize64 = entry.size
+		}
+		file, err := writer.CreateRaw(header)
+		if err != nil {
+			t.Fatalf("failed to add %s: %v", entry.name, err)
+		}
+		if _, err := file.Write(entry.content); err != nil {
+			t.Fatalf("failed to write %s: %v", entry.name, err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatalf("failed to close archive: %v", err)
+	}
+	archive, err := OpenCVArchive(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("OpenCVArchive() error = %v", err)
+	}
+	return archive
+}
+
+func TestCVArchiveFind(t *testing.T) {
+	archive := buildCVArchive(t, []zipEntry{
+		{name: "cvs/", content: nil},
+		{name: "cvs/Jane Doe.PDF", content: []byte("jane")},
+		{name: "john@example.com.docx", content: []byte("john")},
+		{name: "__MACOSX/cvs/._Jane Doe.PDF", content: []byte("meta")},
+		{name: ".hidden.pdf", content: []byte("hidden")},
+		{name: "notes.txt", content: []byte("notes")},
+	})
+
+	tests := []struct {
+		name     string
+		filename string
+		email    string
+		want     string // Entry name, empty for no match
+	}{
+		{"filename ignores case and folders", "../other/jane doe.pdf", "", "cvs/Jane Doe.PDF"},
+		{"named after the email", "", "John@Example.com", "john@example.com.docx"},
+		{"filename given but missing", "missing.pdf", "john@example.com", ""},
+		{"no email match", "", "jane@example.com", ""},
+		{"hidden file", ".hidden.pdf", "", ""},
+		{"unsupported extension", "notes.txt", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := archive.Find(tt.filename, tt.email)
+			got := ""
+			if file != nil {
+				got = file.Name
+			}
+			if got != tt.want {
+				t.Errorf("Find(%q, %q) = %q, want %q", tt.filename, tt.email, got, tt.want)
+			}
+		})
+	}
+
+	if got := (*CVArchive)(nil).Find("jane.pdf", "jane@example.com"); got != nil {
+		t.Errorf("nil archive Find() = %v, want nil", got)
+	}
+}
+
+func TestCVArchiveRead(t *testing.T) {
+	big := bytes.Repeat([]byte("x"), MaxImportCVSize+1)
+	archive := buildCVArchive(t, []zipEntry{
+		{name: "small.pdf", content: []byte("cv")},
+		{name: "oversized.pdf", content: big},
+		{name: "understated.pdf", content: big, size: 1024},
+		{name: "unused.pdf", content: []byte("cv")},
+	})
+
+	tests := []struct {
+		name    string
+		file    string
+		want    string
+		wantErr bool
+	}{
+		{"within the limit", "small.pdf", "cv", false},
+		{"oversized by its header", "oversized.pdf", "", true},
+		{"header understates the size", "understated.pdf", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := archive.Find(tt.file, "")
+			if file == nil {
+				t.Fatalf("Find(%q) = nil", tt.file)
+			}
+			data, err := archive.read(file)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("read(%q) error = %v, wantErr %v", tt.file, err, tt.wantErr)
+			}
+			if !tt.wantErr && string(data) != tt.want {
+				t.Errorf("read(%q) = %q, want %q", tt.file, data, tt.want)
+			}
+		})
+	}
+
+	want := []string{"oversized.pdf", "understated.pdf", "unused.pdf"}
+	if got := archive.Unused(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Unused() = %v, want %v", got, want)
+	}
+}
+
+func TestSafeStorageFilename(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"plain", "jane_doe-cv.pdf", "jane_doe-cv.pdf"},
+		{"spaces become underscores", "Jane Doe CV.pdf", "Jane_Doe_CV.pdf"},
+		{"path separators and symbols dropped", "../../etc/pass wd?.pdf", "cv....etcpass_wd.pdf"},
+		{"non-ASCII dropped", "Zoë Müller.docx", "Zo_Mller.docx"},
+		{"hidden file", ".pdf", "cv.pdf"},
+		{"nothing left", "简历", "cv"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SafeStorageFilename(tt.in); got != tt.want {
+				t.Errorf("SafeStorageFilename(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
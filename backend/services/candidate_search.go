@@ -0,0 +1,368 @@
+package services
+
+import (
+	"ats-backend/models"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// CandidateSearchCriteria holds the filters and scoring inputs of a candidate search
+type CandidateSearchCriteria struct {
+	Query           string         `json:"query"`            // General text search
+	Skills          []string       `json:"skills"`           // Required skills
+	MinExperience   *int           `json:"min_experience"`   // Minimum years of experience
+	MaxExperience   *int           `json:"max_experience"`   // Maximum years of experience
+	CurrentPosition string         `json:"current_position"` // Current position keyword
+	Languages       []string       `json:"languages"`        // Required languages
+	HasPortfolio    *bool          `json:"has_portfolio"`    // Has portfolio URL
+	HasLinkedIn     *bool          `json:"has_linkedin"`     // Has LinkedIn URL
+	InTalentPool    *bool          `json:"in_talent_pool"`   // Talent pool membership
+	Status          string         `json:"status"`           // Application status filter
+	JobID           string         `json:"job_id"`           // Restrict to a single job
+	Fuzzy           *FuzzyOptions  `json:"fuzzy,omitempty"`  // Approximate matching of skills and query terms
+	ScreeningResult string         `json:"screening_result"` // passed, flagged, knocked_out
+	Answers         []AnswerFilter `json:"answers"`          // Screening answer filters, all must match
+}
+
+// CandidateMatch is the relevance analysis of one application against a search
+type CandidateMatch struct {
+	MatchScore     int        `json:"match_score"`             // 0-100
+	MatchedSkills  []string   `json:"matched_skills"`          // Skills found
+	MatchedReasons []string   `json:"matched_reasons"`         // Why it matched
+	FuzzyMatches   []FuzzyHit `json:"fuzzy_matches,omitempty"` // Terms that only matched approximately
+}
+
+// Relevance weights for each search component. Only the components a search
+// actually uses count towards the total, so the score always stays within 0-100.
+const (
+	searchWeightQuery      = 35
+	searchWeightSkills     = 35
+	searchWeightExperience = 10
+	searchWeightLanguages  = 10
+	searchWeightPosition   = 10
+)
+
+// NeedsCVText reports whether the search can only be answered from CV text
+func (criteria CandidateSearchCriteria) NeedsCVText() bool {
+	return criteria.Query != "" || len(criteria.Skills) > 0 || len(criteria.Languages) > 0
+}
+
+// hasStructuredCriteria reports whether anything besides the free-text query was given
+func (criteria CandidateSearchCriteria) hasStructuredCriteria() bool {
+	return len(criteria.Skills) > 0 || criteria.MinExperience != nil || criteria.MaxExperience != nil ||
+		criteria.CurrentPosition != "" || len(criteria.Languages) > 0 || criteria.HasPortfolio != nil ||
+		criteria.HasLinkedIn != nil || criteria.InTalentPool != nil || criteria.Status != "" || criteria.JobID != "" ||
+		criteria.ScreeningResult != "" || len(criteria.Answers) > 0
+}
+
+// PassesCandidateFilters applies the hard (non-scoring) filters of a search to an application
+func PassesCandidateFilters(app models.Application, criteria CandidateSearchCriteria) bool {
+	if criteria.Status != "" && app.Status != criteria.Status {
+		return false
+	}
+	if criteria.JobID != "" && (app.JobID == nil || app.JobID.String() != criteria.JobID) {
+		return false
+	}
+	if criteria.HasPortfolio != nil && *criteria.HasPortfolio && app.PortfolioURL == "" {
+		return false
+	}
+	if criteria.HasLinkedIn != nil && *criteria.HasLinkedIn && app.LinkedinURL == "" {
+		return false
+	}
+	if criteria.InTalentPool != nil && app.InTalentPool != *criteria.InTalentPool {
+		return false
+	}
+	if criteria.MinExperience != nil && app.YearsOfExperience < *criteria.MinExperience {
+		return false
+	}
+	if criteria.MaxExperience != nil && app.YearsOfExperience > *criteria.MaxExperience {
+		return false
+	}
+	if criteria.CurrentPosition != "" &&
+		!strings.Contains(strings.ToLower(app.CurrentPosition), strings.ToLower(criteria.CurrentPosition)) {
+		return false
+	}
+	if criteria.ScreeningResult != "" && app.ScreeningResult != criteria.ScreeningResult {
+		return false
+	}
+	for _, filter := range criteria.Answers {
+		matched := false
+		for _, answer := range app.Answers {
+			if filter.MatchesAnswer(answer) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// MatchCandidate scores an application's CV text against a search.
+// The score is the weighted average of the components the search uses, so it never exceeds 100.
+// Returns false when the candidate should not be part of the results.
+func MatchCandidate(app models.Application, cvText string, criteria CandidateSearchCriteria) (*CandidateMatch, bool) {
+	cvLower := strings.ToLower(cvText)
+	match := &CandidateMatch{
+		MatchedSkills:  []string{},
+		MatchedReasons: []string{},
+	}
+
+	weightedSum := 0
+	totalWeight := 0
+
+	// CV tokens are only needed for fuzzy matching
+	var tokens []string
+	if criteria.Fuzzy.active() {
+		tokens = tokenizeText(cvText)
+	}
+
+	// 1. General text query search
+	if criteria.Query != "" {
+		// Match words longer than 2 characters (more reliable); shorter ones don't count towards the score
+		queryWords := []string{}
+		for _, word := range strings.Fields(strings.ToLower(strings.TrimSpace(criteria.Query))) {
+			if len(word) > 2 {
+				queryWords = append(queryWords, word)
+			}
+		}
+		matchedWords := 0
+		fuzzyWords := 0
+		for _, word := range queryWords {
+			wordPattern := regexp.MustCompile(`\b` + regexp.QuoteMeta(word) + `\b`)
+			if wordPattern.MatchString(cvLower) || strings.Contains(cvLower, word) {
+				matchedWords++
+			} else if hit, ok := fuzzyFindTerm(word, tokens, criteria.Fuzzy, true); ok {
+				fuzzyWords++
+				match.FuzzyMatches = append(match.FuzzyMatches, *hit)
+			}
+		}
+
+		// A query that matches nothing excludes the candidate
+		if matchedWords+fuzzyWords == 0 || len(queryWords) == 0 {
+			return nil, false
+		}
+
+		queryPoints := matchedWords*100 + fuzzyWords*fuzzyScorePercent
+		weightedSum += searchWeightQuery * (queryPoints / len(queryWords))
+		totalWeight += searchWeightQuery
+		if fuzzyWords > 0 {
+			match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Matched %d/%d search terms (%d approximate)", matchedWords+fuzzyWords, len(queryWords), fuzzyWords))
+		} else {
+			match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Matched %d/%d search terms", matchedWords, len(queryWords)))
+		}
+	}
+
+	// 2. Skills search
+	if len(criteria.Skills) > 0 {
+		foundSkills, fuzzySkills := ExtractSkillsWithOptions(cvText, criteria.Skills, criteria.Fuzzy)
+		// ExtractSkills also returns common skills that weren't asked for - keep only requested ones
+		requested := []string{}
+		for _, skill := range foundSkills {
+			if contains(criteria.Skills, skill) {
+				requested = append(requested, skill)
+			}
+		}
+		match.MatchedSkills = requested
+		match.FuzzyMatches = append(match.FuzzyMatches, fuzzySkills...)
+
+		skillPoints := (len(requested)-len(fuzzySkills))*100 + len(fuzzySkills)*fuzzyScorePercent
+		weightedSum += searchWeightSkills * (skillPoints / len(criteria.Skills))
+		totalWeight += searchWeightSkills
+		if len(requested) > 0 {
+			match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Found %d/%d required skills: %s", len(requested), len(criteria.Skills), strings.Join(requested, ", ")))
+		}
+		for _, hit := range fuzzySkills {
+			match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Approximate match for %s: found \"%s\"", hit.Term, hit.MatchedText))
+		}
+	}
+
+	// 3. Experience search (hard filter already enforced the range)
+	if criteria.MinExperience != nil {
+		weightedSum += searchWeightExperience * 100
+		totalWeight += searchWeightExperience
+		match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Has %d years of experience (required: %d+)", app.YearsOfExperience, *criteria.MinExperience))
+	}
+
+	// 4. Languages search
+	if len(criteria.Languages) > 0 {
+		foundLanguages := ExtractLanguages(cvText, criteria.Languages)
+		weightedSum += searchWeightLanguages * (len(foundLanguages) * 100 / len(criteria.Languages))
+		totalWeight += searchWeightLanguages
+		if len(foundLanguages) > 0 {
+			match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Found languages: %s", strings.Join(foundLanguages, ", ")))
+		}
+	}
+
+	// 5. Current position search (hard filter already enforced the match)
+	if criteria.CurrentPosition != "" {
+		weightedSum += searchWeightPosition * 100
+		totalWeight += searchWeightPosition
+		match.MatchedReasons = append(match.MatchedReasons, fmt.Sprintf("Current position matches: %s", app.CurrentPosition))
+	}
+
+	if totalWeight == 0 {
+		// Nothing to score on: filters only (or no criteria at all)
+		match.MatchScore = 50
+		return match, true
+	}
+
+	match.MatchScore = weightedSum / totalWeight
+	if match.MatchScore == 0 && criteria.hasStructuredCriteria() {
+		return nil, false
+	}
+	if match.MatchScore > 100 {
+		match.MatchScore = 100
+	}
+	return match, true
+}
+
+// FacetBucket is a single value of a search facet with the number of matching candidates
+type FacetBucket struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
+	Count int    `json:"count"`
+}
+
+// CandidateFacets summarises a candidate result set for drill-down filtering
+type CandidateFacets struct {
+	Status       []FacetBucket `json:"status"`
+	Jobs         []FacetBucket `json:"jobs"`
+	Skills       []FacetBucket `json:"skills"`
+	Experience   []FacetBucket `json:"experience"`
+	Languages    []FacetBucket `json:"languages"`
+	HasPortfolio []FacetBucket `json:"has_portfolio"`
+	HasLinkedIn  []FacetBucket `json:"has_linkedin"`
+	InTalentPool []FacetBucket `json:"in_talent_pool"`
+}
+
+// maxSkillFacets caps the number of skill buckets returned
+const maxSkillFacets = 25
+
+// experienceBuckets defines the experience facet ranges (inclusive bounds, -1 = open ended)
+var experienceBuckets = []struct {
+	Label string
+	Min   int
+	Max   int
+}{
+	{"0-1", 0, 1},
+	{"2-4", 2, 4},
+	{"5-9", 5, 9},
+	{"10+", 10, -1},
+}
+
+// ExperienceBucket returns the facet bucket label for a number of years of experience
+func ExperienceBucket(years int) string {
+	for _, bucket := range experienceBuckets {
+		if years >= bucket.Min && (bucket.Max == -1 || years <= bucket.Max) {
+			return bucket.Label
+		}
+	}
+	return experienceBuckets[0].Label
+}
+
+// facetCounter accumulates counts for a single facet
+type facetCounter struct {
+	counts map[string]int
+	labels map[string]string
+}
+
+func newFacetCounter() *facetCounter {
+	return &facetCounter{counts: map[string]int{}, labels: map[string]string{}}
+}
+
+func (f *facetCounter) add(value, label string) {
+	f.counts[value]++
+	if label != "" {
+		f.labels[value] = label
+	}
+}
+
+// buckets returns the facet values sorted by count (descending), then value
+func (f *facetCounter) buckets(limit int) []FacetBucket {
+	buckets := make([]FacetBucket, 0, len(f.counts))
+	for value, count := range f.counts {
+		buckets = append(buckets, FacetBucket{Value: value, Label: f.labels[value], Count: count})
+	}
+	sort.Slice(buckets, func(i, j int) bool {
+		if buckets[i].Count != buckets[j].Count {
+			return buckets[i].Count > buckets[j].Count
+		}
+		return buckets[i].Value < buckets[j].Value
+	})
+	if limit > 0 && len(buckets) > limit {
+		buckets = buckets[:limit]
+	}
+	return buckets
+}
+
+// BuildCandidateFacets computes facet counts for the given applications.
+// cvTexts maps application IDs to their CV text (used for skills and languages).
+func BuildCandidateFacets(apps []models.Application, cvTexts map[string]string) CandidateFacets {
+	status := newFacetCounter()
+	jobs := newFacetCounter()
+	skills := newFacetCounter()
+	experience := newFacetCounter()
+	languages := newFacetCounter()
+	portfolio := newFacetCounter()
+	linkedin := newFacetCounter()
+	talentPool := newFacetCounter()
+
+	knownLanguages := KnownLanguages()
+
+	for _, app := range apps {
+		status.add(app.Status, "")
+
+		if app.JobID != nil && app.Job.ID != uuid.Nil {
+			jobs.add(app.JobID.String(), app.Job.Title)
+		} else {
+			jobs.add("none", "Unknown Job (Job Deleted)")
+		}
+
+		experience.add(ExperienceBucket(app.YearsOfExperience), "")
+		portfolio.add(fmt.Sprintf("%t", app.PortfolioURL != ""), "")
+		linkedin.add(fmt.Sprintf("%t", app.LinkedinURL != ""), "")
+		talentPool.add(fmt.Sprintf("%t", app.InTalentPool), "")
+
+		cvText := cvTexts[app.ID.String()]
+		if cvText == "" {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, skill := range ExtractSkills(cvText, []string{}) {
+			key := strings.ToLower(skill)
+			if !seen[key] {
+				seen[key] = true
+				skills.add(key, "")
+			}
+		}
+		for _, lang := range ExtractLanguages(cvText, knownLanguages) {
+			languages.add(lang, "")
+		}
+	}
+
+	// Keep experience buckets in their natural order rather than by count
+	experienceFacet := []FacetBucket{}
+	for _, bucket := range experienceBuckets {
+		if count := experience.counts[bucket.Label]; count > 0 {
+			experienceFacet = append(experienceFacet, FacetBucket{Value: bucket.Label, Count: count})
+		}
+	}
+
+	return CandidateFacets{
+		Status:       status.buckets(0),
+		Jobs:         jobs.buckets(0),
+		Skills:       skills.buckets(maxSkillFacets),
+		Experience:   experienceFacet,
+		Languages:    languages.buckets(0),
+		HasPortfolio: portfolio.buckets(0),
+		HasLinkedIn:  linkedin.buckets(0),
+		InTalentPool: talentPool.buckets(0),
+	}
+}
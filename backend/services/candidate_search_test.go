@@ -0,0 +1,36 @@
+package services
+
+import (
+	"ats-backend/models"
+	"testing"
+)
+
+func TestMatchCandidateQueryScore(t *testing.T) {
+	cv := "Senior Go developer with PostgreSQL and Kubernetes experience"
+
+	tests := []struct {
+		name      string
+		query     string
+		wantMatch bool
+		wantScore int
+	}{
+		{"two of three terms match", "golang developer postgresql", true, 66},
+		{"all terms match", "developer postgresql kubernetes", true, 100},
+		{"short words don't lower the score", "a go developer in kubernetes", true, 100},
+		{"half the terms match", "developer java", true, 50},
+		{"nothing matches", "accountant", false, 0},
+		{"only short words", "go in a", false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, ok := MatchCandidate(models.Application{}, cv, CandidateSearchCriteria{Query: tt.query})
+			if ok != tt.wantMatch {
+				t.Fatalf("matched = %v, want %v", ok, tt.wantMatch)
+			}
+			if ok && match.MatchScore != tt.wantScore {
+				t.Errorf("score = %d, want %d", match.MatchScore, tt.wantScore)
+			}
+		})
+	}
+}
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// MaxSlugLength keeps careers URLs short; job slugs get room for a numeric suffix
+const MaxSlugLength = 80
+
+var (
+	slugPattern      = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+	hexColourPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+)
+
+// CareersBranding is what the public careers page needs to render a company
+type CareersBranding struct {
+	ID           uuid.UUID `json:"id"`
+	Slug         string    `json:"slug"`
+	Name         string    `json:"name"`
+	Website      string    `json:"website,omitempty"`
+	LogoURL      string    `json:"logo_url,omitempty"`
+	PrimaryColor string    `json:"primary_color,omitempty"`
+	AccentColor  string    `json:"accent_color,omitempty"`
+	AboutText    string    `json:"about_text,omitempty"`
+	EmbeddedMode bool      `json:"embedded_mode"`
+}
+
+// CompanyBranding returns the public branding of a company
+func CompanyBranding(company models.Company) CareersBranding {
+	branding := CareersBranding{
+		ID:           company.ID,
+		Name:         company.CompanyName,
+		Website:      company.CompanyWebsite,
+		LogoURL:      company.LogoURL,
+		PrimaryColor: company.PrimaryColor,
+		AccentColor:  company.AccentColor,
+		AboutText:    company.AboutText,
+		EmbeddedMode: company.EmbeddedMode,
+	}
+	if company.Slug != nil {
+		branding.Slug = *company.Slug
+	}
+	return branding
+}
+
+// Slugify turns a name or title into a URL slug: "Senior Go Engineer (Remote)" -> "senior-go-engineer-remote"
+func Slugify(text string) string {
+	var builder strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			builder.WriteRune(r)
+			dash = false
+		case builder.Len() > 0 && !dash:
+			builder.WriteByte('-')
+			dash = true
+		}
+	}
+	slug := strings.TrimRight(builder.String(), "-")
+	if len(slug) > MaxSlugLength {
+		slug = strings.TrimRight(slug[:MaxSlugLength], "-")
+	}
+	return slug
+}
+
+// IsValidSlug reports whether a slug uses lowercase letters, digits and single dashes only
+func IsValidSlug(slug string) bool {
+	return len(slug) <= MaxSlugLength && slugPattern.MatchString(slug)
+}
+
+// IsValidHexColour reports whether a colour is written as #rrggbb
+func IsValidHexColour(colour string) bool {
+	return hexColourPattern.MatchString(colour)
+}
+
+// uniqueSlug returns base, or base with the first free numeric suffix, according to taken
+func uniqueSlug(base, fallback string, taken func(slug string) (bool, error)) (string, error) {
+	if base == "" {
+		base = fallback
+	}
+	for i := 1; i <= 1000; i++ {
+		slug := base
+		if i > 1 {
+			suffix := fmt.Sprintf("-%d", i)
+			slug = strings.TrimRight(base[:min(len(base), MaxSlugLength-len(suffix))], "-") + suffix
+		}
+		exists, err := taken(slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+	}
+	return "", fmt.Errorf("no free slug for %q", base)
+}
+
+// UniqueCompanySlug returns a free careers slug for a company name, ignoring the company itself
+func UniqueCompanySlug(name string, companyID uuid.UUID) (string, error) {
+	return uniqueSlug(Slugify(name), "company", func(slug string) (bool, error) {
+		return IsCompanySlugTaken(slug, companyID)
+	})
+}
+
+// IsCompanySlugTaken reports whether another company already uses a careers slug
+func IsCompanySlugTaken(slug string, companyID uuid.UUID) (bool, error) {
+	var count int64
+	err := config.DB.Model(&models.Company{}).Where("slug = ? AND id <> ?", slug, companyID).Count(&count).Error
+	return count > 0, err
+}
+
+// UniqueJobSlug returns a slug for a job title that is free within the company, ignoring the job itself
+func UniqueJobSlug(companyID uuid.UUID, title string, jobID uuid.UUID) (string, error) {
+	return uniqueSlug(Slugify(title), "job", func(slug string) (bool, error) {
+		// Trashed jobs keep their slug so they can be restored
+		var count int64
+		err := config.DB.Unscoped().Model(&models.Job{}).
+			Where("company_id = ? AND slug = ? AND id <> ?", companyID, slug, jobID).
+			Count(&count).Error
+		return count > 0, err
+	})
+}
+
+// AssignJobSlug gives a job without a slug one based on its title
+func AssignJobSlug(job *models.Job) error {
+	if job.Slug != nil && *job.Slug != "" {
+		return nil
+	}
+	slug, err := UniqueJobSlug(job.CompanyID, job.Title, job.ID)
+	if err != nil {
+		return err
+	}
+	job.Slug = &slug
+	return nil
+}
+
+// BackfillSlugs gives companies and jobs created before careers slugs existed their slug
+func BackfillSlugs() {
+	var companies []models.Company
+	if err := config.DB.Select("id, company_name").Where("slug IS NULL OR slug = ''").Find(&companies).Error; err != nil {
+		log.Printf("ERROR: Failed to load companies without slug: %v", err)
+		return
+	}
+	for _, company := range companies {
+		slug, err := UniqueCompanySlug(company.CompanyName, company.ID)
+		if err == nil {
+			err = config.DB.Model(&models.Company{}).Where("id = ?", company.ID).Update("slug", slug).Error
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to set slug of company %s: %v", company.ID, err)
+		}
+	}
+
+	var jobs []models.Job
+	if err := config.DB.Select("id, company_id, title").Where("slug IS NULL OR slug = ''").Find(&jobs).Error; err != nil {
+		log.Printf("ERROR: Failed to load jobs without slug: %v", err)
+		return
+	}
+	for _, job := range jobs {
+		slug, err := UniqueJobSlug(job.CompanyID, job.Title, job.ID)
+		if err == nil {
+			err = config.DB.Model(&models.Job{}).Where("id = ?", job.ID).Update("slug", slug).Error
+		}
+		if err != nil {
+			log.Printf("ERROR: Failed to set slug of job %s: %v", job.ID, err)
+		}
+	}
+	if len(companies) > 0 || len(jobs) > 0 {
+		log.Printf("SUCCESS: Backfilled slugs for %d companies and %d jobs", len(companies), len(jobs))
+	}
+}
+
+// FindCareersCompany finds a company by careers slug or by ID
+func FindCareersCompany(ref string) (models.Company, error) {
+	var company models.Company
+	query := config.DB.Where("slug = ?", strings.ToLower(ref))
+	if id, err := uuid.Parse(ref); err == nil {
+		query = config.DB.Where("id = ?", id)
+	}
+	err := query.First(&company).Error
+	return company, err
+}
+
+// FindPublicJob finds an open job of a company by slug or by ID
+func FindPublicJob(companyID uuid.UUID, ref string) (models.Job, error) {
+	var job models.Job
+	query := publicJobsQuery(companyID.String())
+	if id, err := uuid.Parse(ref); err == nil {
+		query = query.Where("id = ?", id)
+	} else {
+		query = query.Where("slug = ?", strings.ToLower(ref))
+	}
+	err := query.First(&job).Error
+	return job, err
+}
+
+// publicJobsQuery selects a company's jobs that are open and still before their deadline
+func publicJobsQuery(companyID string) *gorm.DB {
+	return config.DB.Model(&models.Job{}).Where("company_id = ? AND status = ? AND deadline > ?",
+		companyID, "open", time.Now().Format("2006-01-02"))
+}
+
+// CareersJobFilters narrows the jobs of the public careers page
+type CareersJobFilters struct {
+	Keyword    string // Matched against title, description and requirements
+	Location   string // Substring of the location
+	JobType    string // Compared like stage keys, so "Full-time" matches "full_time"
+	Department string // Case-insensitive exact match
+	Remote     bool   // Only jobs whose location mentions remote
+}
+
+// SearchPublicJobs returns one page of a company's open jobs matching filters, newest first, and the total
+func SearchPublicJobs(companyID string, filters CareersJobFilters, offset, limit int) ([]models.Job, int64, error) {
+	query := publicJobsQuery(companyID)
+	if keyword := strings.TrimSpace(filters.Keyword); keyword != "" {
+		pattern := "%" + escapeLike(keyword) + "%"
+		query = query.Where("(title ILIKE ? OR description ILIKE ? OR requirements ILIKE ?)", pattern, pattern, pattern)
+	}
+	if location := strings.TrimSpace(filters.Location); location != "" {
+		query = query.Where("location ILIKE ?", "%"+escapeLike(location)+"%")
+	}
+	if jobType := NormalizeStageKey(filters.JobType); jobType != "" {
+		query = query.Where("LOWER(REGEXP_REPLACE(TRIM(job_type), '[ _-]+', '_', 'g')) = ?", jobType)
+	}
+	if department := strings.TrimSpace(filters.Department); department != "" {
+		query = query.Where("LOWER(department) = LOWER(?)", department)
+	}
+	if filters.Remote {
+		query = query.Where("location ILIKE ?", "%remote%")
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	var jobs []models.Job
+	err := query.Order("created_at DESC").Order("id").Offset(offset).Limit(limit).Find(&jobs).Error
+	return jobs, total, err
+}
+
+// CareersFacets lists the filter values available across a company's open jobs
+type CareersFacets struct {
+	Locations   []string `json:"locations"`
+	JobTypes    []string `json:"job_types"`
+	Departments []string `json:"departments"`
+}
+
+// PublicJobFacets returns the distinct locations, job types and departments of a company's open jobs
+func PublicJobFacets(companyID string) (CareersFacets, error) {
+	facets := CareersFacets{Locations: []string{}, JobTypes: []string{}, Departments: []string{}}
+	for column, values := range map[string]*[]string{
+		"location":   &facets.Locations,
+		"job_type":   &facets.JobTypes,
+		"department": &facets.Departments,
+	} {
+		err := publicJobsQuery(companyID).
+			Where(column+" <> ''").
+			Distinct(column).
+			Order(column).
+			Pluck(column, values).Error
+		if err != nil {
+			return facets, err
+		}
+	}
+	return facets, nil
+}
+
+// escapeLike escapes the LIKE wildcards in user input
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
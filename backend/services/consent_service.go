@@ -0,0 +1,127 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPrivacyNoticeNotAccepted is returned when a candidate applies without accepting the company's privacy notice
+	ErrPrivacyNoticeNotAccepted = errors.New("please accept the privacy notice to apply")
+	// ErrPrivacyNoticeOutdated is returned when a candidate accepted a privacy notice that has since been replaced
+	ErrPrivacyNoticeOutdated = errors.New("the privacy notice has changed, please review and accept the current version")
+	// ErrNoMarketingConsent is returned when a candidate would be emailed without opting in to job alerts
+	ErrNoMarketingConsent = errors.New("candidate hasn't opted in to job alerts and marketing emails")
+)
+
+// ApplicationConsent is what a candidate agrees to when applying
+type ApplicationConsent struct {
+	PrivacyNoticeVersion int  `json:"privacy_notice_version"` // Version of the notice shown on the form
+	TalentPool           bool `json:"talent_pool"`            // Keep my data in the talent pool for future jobs
+	Marketing            bool `json:"marketing"`              // Send me job alerts and other news
+}
+
+// CurrentPrivacyNotice returns the latest published privacy notice of a company, or nil when it has none
+func CurrentPrivacyNotice(companyID uuid.UUID) (*models.PrivacyNotice, error) {
+	var notice models.PrivacyNotice
+	err := config.DB.Where("company_id = ?", companyID).Order("version DESC").First(&notice).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notice, nil
+}
+
+// PublishPrivacyNotice publishes the next version of a company's privacy notice
+func PublishPrivacyNotice(companyID, adminID uuid.UUID, title, content string) (models.PrivacyNotice, error) {
+	notice := models.PrivacyNotice{
+		CompanyID: companyID,
+		Title:     title,
+		Content:   content,
+		CreatedBy: &adminID,
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&models.PrivacyNotice{}).
+			Where("company_id = ?", companyID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		notice.Version = latest + 1
+		return tx.Create(&notice).Error
+	})
+	return notice, err
+}
+
+// ApplyConsent checks what a candidate agreed to against the company's current privacy notice
+// (nil when it has none) and records it on their new application with the time and IP address
+func ApplyConsent(application *models.Application, notice *models.PrivacyNotice, consent ApplicationConsent, ip string) error {
+	application.PrivacyNoticeID = nil
+	application.PrivacyNoticeVersion = 0
+	application.ConsentedAt = nil
+	application.ConsentIP = ""
+	if notice != nil {
+		if consent.PrivacyNoticeVersion == 0 {
+			return ErrPrivacyNoticeNotAccepted
+		}
+		if consent.PrivacyNoticeVersion != notice.Version {
+			return ErrPrivacyNoticeOutdated
+		}
+		application.PrivacyNoticeID = &notice.ID
+		application.PrivacyNoticeVersion = notice.Version
+	}
+
+	application.TalentPoolConsent = consent.TalentPool
+	application.MarketingConsent = consent.Marketing
+	if notice != nil || consent.TalentPool || consent.Marketing {
+		now := time.Now()
+		application.ConsentedAt = &now
+		application.ConsentIP = ip
+	}
+	return nil
+}
+
+// UpdateCandidateConsent changes a candidate's opt-ins on all their applications to the company.
+// Withdrawing talent pool consent also takes them out of the talent pool. It returns how many
+// applications changed.
+func UpdateCandidateConsent(companyID uuid.UUID, email string, talentPool, marketing *bool, ip string) (int64, error) {
+	updates := map[string]interface{}{
+		"consented_at": time.Now(),
+		"consent_ip":   ip,
+	}
+	if talentPool != nil {
+		updates["talent_pool_consent"] = *talentPool
+		if !*talentPool {
+			updates["in_talent_pool"] = false
+		}
+	}
+	if marketing != nil {
+		updates["marketing_consent"] = *marketing
+	}
+
+	result := config.DB.Model(&models.Application{}).
+		Where("company_id = ? AND LOWER(email) = LOWER(?)", companyID, email).
+		UpdateColumns(updates)
+	return result.RowsAffected, result.Error
+}
+
+// checkMarketingConsent returns ErrNoMarketingConsent unless the candidate of an application
+// opted in to job alerts and marketing emails
+func checkMarketingConsent(applicationID string) error {
+	var application models.Application
+	if err := config.DB.Select("id, marketing_consent").First(&application, "id = ?", applicationID).Error; err != nil {
+		return err
+	}
+	if !application.MarketingConsent {
+		return ErrNoMarketingConsent
+	}
+	return nil
+}
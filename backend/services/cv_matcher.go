@@ -6,6 +6,7 @@ import (
 	"log"
 	"net/http"
 	"regexp"
+	"sort"
 	"strings"
 	"unicode"
 	"unicode/utf8"
@@ -19,6 +20,7 @@ type Criteria struct {
 	MatchJobDescription bool     `json:"match_job_description"`
 	JobDescription      string   `json:"job_description,omitempty"`
 	JobRequirements     string   `json:"job_requirements,omitempty"`
+	FuzzyMatching       *FuzzyOptions `json:"fuzzy_matching,omitempty"` // Approximate skill matching (off by default)
 }
 
 // MatchResult contains the matching analysis results
@@ -35,6 +37,7 @@ type MatchResult struct {
 	SkillsMatch     int      `json:"skills_match"`    // Skills match percentage
 	ExperienceMatch int      `json:"experience_match"` // Experience match percentage
 	LanguageMatch   int      `json:"language_match"`  // Language match percentage
+	FuzzySkills     []FuzzyHit `json:"fuzzy_skills,omitempty"` // Skills that only matched approximately
 }
 
 // ExtractTextFromURL downloads and extracts text from CV file
@@ -638,6 +641,31 @@ func ExtractSkills(cvText string, requiredSkills []string) []string {
 	return foundSkills
 }
 
+// ExtractSkillsWithOptions works like ExtractSkills, and when fuzzy matching is enabled
+// also accepts required skills that only appear misspelled (e.g. "Kubernates").
+// Approximate matches are included in the skills and reported separately as evidence.
+func ExtractSkillsWithOptions(cvText string, requiredSkills []string, opts *FuzzyOptions) ([]string, []FuzzyHit) {
+	foundSkills := ExtractSkills(cvText, requiredSkills)
+	fuzzyHits := []FuzzyHit{}
+	if !opts.active() {
+		return foundSkills, fuzzyHits
+	}
+	
+	tokens := tokenizeText(cvText)
+	for _, skill := range requiredSkills {
+		if contains(foundSkills, skill) {
+			continue
+		}
+		if hit, ok := fuzzyFindTerm(NormalizeSkill(skill), tokens, opts, false); ok {
+			hit.Term = skill
+			foundSkills = append(foundSkills, skill)
+			fuzzyHits = append(fuzzyHits, *hit)
+		}
+	}
+	
+	return foundSkills, fuzzyHits
+}
+
 // extractJobTitleFromCV extracts job title from CV text
 func extractJobTitleFromCV(cvText string) string {
 	cvLower := strings.ToLower(cvText)
@@ -662,25 +690,35 @@ func extractJobTitleFromCV(cvText string) string {
 	return ""
 }
 
+// languagePatterns maps known languages to the phrases that indicate them in a CV
+var languagePatterns = map[string][]string{
+	"english":    {"english", "fluent english", "native english"},
+	"spanish":    {"spanish", "español", "castellano"},
+	"french":     {"french", "français"},
+	"german":     {"german", "deutsch"},
+	"chinese":    {"chinese", "mandarin", "中文"},
+	"arabic":     {"arabic", "عربي"},
+	"hindi":      {"hindi", "हिंदी"},
+	"portuguese": {"portuguese", "português"},
+	"italian":    {"italian", "italiano"},
+	"japanese":   {"japanese", "日本語"},
+}
+
+// KnownLanguages returns the languages ExtractLanguages can recognise, sorted by name
+func KnownLanguages() []string {
+	languages := make([]string, 0, len(languagePatterns))
+	for lang := range languagePatterns {
+		languages = append(languages, lang)
+	}
+	sort.Strings(languages)
+	return languages
+}
+
 // ExtractLanguages extracts languages from CV text
 func ExtractLanguages(cvText string, requiredLanguages []string) []string {
 	foundLanguages := []string{}
 	cvLower := strings.ToLower(cvText)
 	
-	// Common language patterns
-	languagePatterns := map[string][]string{
-		"english":    {"english", "fluent english", "native english"},
-		"spanish":    {"spanish", "español", "castellano"},
-		"french":     {"french", "français"},
-		"german":     {"german", "deutsch"},
-		"chinese":    {"chinese", "mandarin", "中文"},
-		"arabic":     {"arabic", "عربي"},
-		"hindi":      {"hindi", "हिंदी"},
-		"portuguese": {"portuguese", "português"},
-		"italian":    {"italian", "italiano"},
-		"japanese":   {"japanese", "日本語"},
-	}
-	
 	// Check required languages
 	for _, lang := range requiredLanguages {
 		langLower := strings.ToLower(lang)
@@ -711,8 +749,11 @@ func MatchCV(cvText string, criteria Criteria, jobTitle string) *MatchResult {
 	
 	// 1. Extract and match skills (40% weight)
 	if len(criteria.RequiredSkills) > 0 {
-		result.Skills = ExtractSkills(cvText, criteria.RequiredSkills)
-		result.SkillsMatch = (len(result.Skills) * 100) / len(criteria.RequiredSkills)
+		result.Skills, result.FuzzySkills = ExtractSkillsWithOptions(cvText, criteria.RequiredSkills, criteria.FuzzyMatching)
+		// Approximate matches count for less than exact ones
+		skillPoints := (len(result.Skills) - len(result.FuzzySkills)) * 100
+		skillPoints += len(result.FuzzySkills) * fuzzyScorePercent
+		result.SkillsMatch = skillPoints / len(criteria.RequiredSkills)
 		if result.SkillsMatch > 100 {
 			result.SkillsMatch = 100
 		}
@@ -852,6 +893,14 @@ func generateMatchReason(result *MatchResult, criteria Criteria) string {
 		reasons = append(reasons, "Missing key skills")
 	}
 	
+	if len(result.FuzzySkills) > 0 {
+		approximate := []string{}
+		for _, hit := range result.FuzzySkills {
+			approximate = append(approximate, fmt.Sprintf("%s (found \"%s\")", hit.Term, hit.MatchedText))
+		}
+		reasons = append(reasons, "Approximate skill matches: "+strings.Join(approximate, ", "))
+	}
+	
 	if result.ExperienceMatch >= 100 {
 		reasons = append(reasons, "Meets experience requirement")
 	} else if result.ExperienceMatch > 0 {
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// cvProcessingWorkers is how many queued CVs are parsed and scored at the same time
+const cvProcessingWorkers = 3
+
+var (
+	cvProcessingQueue     = make(chan uuid.UUID, 10000)
+	startCVProcessingOnce sync.Once
+)
+
+// SaveParsedCVText stores extracted CV text for an application and records when it was parsed.
+// The parse timestamp lets background jobs (e.g. saved search alerts) pick up newly parsed CVs.
+func SaveParsedCVText(applicationID uuid.UUID, cvText string) error {
+	return config.DB.Model(&models.Application{}).
+		Where("id = ?", applicationID).
+		Updates(map[string]interface{}{
+			"parsed_cv_text": cvText,
+			"cv_parsed_at":   time.Now(),
+		}).Error
+}
+
+// JobMatchCriteria returns the criteria CVs are scored against for a job:
+// its shortlist criteria when set, otherwise a match against the job description.
+func JobMatchCriteria(job models.Job) Criteria {
+	if job.ShortlistCriteria != nil && *job.ShortlistCriteria != "" {
+		var criteria Criteria
+		if err := json.Unmarshal([]byte(*job.ShortlistCriteria), &criteria); err == nil {
+			criteria.JobDescription = job.Description
+			criteria.JobRequirements = job.Requirements
+			return criteria
+		}
+	}
+	return Criteria{
+		RequiredSkills:      []string{},
+		MinExperience:       0,
+		RequiredLanguages:   []string{},
+		MatchJobDescription: true,
+		JobDescription:      job.Description,
+		JobRequirements:     job.Requirements,
+	}
+}
+
+// ProcessApplicationCV extracts and stores the text of an application's CV,
+// then scores it against its job when the job still exists
+func ProcessApplicationCV(applicationID uuid.UUID) error {
+	var application models.Application
+	if err := config.DB.Preload("Job").First(&application, "id = ?", applicationID).Error; err != nil {
+		return err
+	}
+	if application.ResumeURL == "" {
+		return fmt.Errorf("application %s has no CV", applicationID)
+	}
+
+	cvText, err := ExtractTextFromURL(application.ResumeURL)
+	if err != nil {
+		return fmt.Errorf("failed to parse CV: %w", err)
+	}
+	if len(cvText) < 50 {
+		return fmt.Errorf("CV text too short (%d characters)", len(cvText))
+	}
+	if !utf8.ValidString(cvText) {
+		return fmt.Errorf("extracted CV text is not valid UTF-8")
+	}
+	if err := SaveParsedCVText(application.ID, cvText); err != nil {
+		return fmt.Errorf("failed to save parsed CV text: %w", err)
+	}
+
+	if application.JobID == nil || application.Job.ID == uuid.Nil {
+		return nil
+	}
+	result := MatchCV(cvText, JobMatchCriteria(application.Job), application.Job.Title)
+	analysisJSON, _ := json.Marshal(result)
+	return config.DB.Model(&models.Application{}).
+		Where("id = ?", application.ID).
+		Updates(map[string]interface{}{
+			"score":           result.MatchScore,
+			"analysis_result": string(analysisJSON),
+		}).Error
+}
+
+// QueueCVProcessing queues an application's CV to be parsed and scored in the background.
+// A few workers drain the queue so bulk imports don't download hundreds of CVs at once.
+func QueueCVProcessing(applicationID uuid.UUID) {
+	startCVProcessingOnce.Do(func() {
+		for i := 0; i < cvProcessingWorkers; i++ {
+			go runCVProcessingWorker()
+		}
+	})
+	cvProcessingQueue <- applicationID
+}
+
+func runCVProcessingWorker() {
+	for applicationID := range cvProcessingQueue {
+		if err := ProcessApplicationCV(applicationID); err != nil {
+			log.Printf("ERROR: Failed to process CV of application %s: %v", applicationID, err)
+			continue
+		}
+		log.Printf("SUCCESS: CV parsed and scored for application %s", applicationID)
+	}
+}
@@ -2,13 +2,16 @@ package services
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"html/template"
 	"io"
 	"log"
 	"net/http"
 	"os"
 	"strings"
+	"time"
 )
 
 // EmailProvider represents the email service provider
@@ -25,10 +28,25 @@ func isResendVerifiedDomain(email string) bool {
 }
 
 type EmailRequest struct {
-	From    string   `json:"from"`
-	To      []string `json:"to"`
-	Subject string   `json:"subject"`
-	HTML    string   `json:"html"`
+	From        string             `json:"from"`
+	To          []string           `json:"to"`
+	Subject     string             `json:"subject"`
+	HTML        string             `json:"html"`
+	Attachments []ResendAttachment `json:"attachments,omitempty"`
+}
+
+// ResendAttachment is a file attached to a Resend email (content is base64)
+type ResendAttachment struct {
+	Filename    string `json:"filename"`
+	Content     string `json:"content"`
+	ContentType string `json:"content_type,omitempty"`
+}
+
+// EmailAttachment is a file attached to an outgoing email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string // e.g. "text/calendar; method=REQUEST"
+	Content     []byte
 }
 
 // getEmailProvider returns the configured email provider (default: sendgrid)
@@ -42,7 +60,7 @@ func getEmailProvider() EmailProvider {
 }
 
 // sendEmailViaSendGrid sends email using SendGrid API
-func sendEmailViaSendGrid(to, subject, htmlBody string) error {
+func sendEmailViaSendGrid(to, subject, htmlBody string, attachments []EmailAttachment) error {
 	apiKey := os.Getenv("SENDGRID_API_KEY")
 	if apiKey == "" {
 		return fmt.Errorf("SENDGRID_API_KEY not set")
@@ -66,11 +84,18 @@ func sendEmailViaSendGrid(to, subject, htmlBody string) error {
 	type SendGridPersonalization struct {
 		To []map[string]string `json:"to"`
 	}
+	type SendGridAttachment struct {
+		Content     string `json:"content"`
+		Type        string `json:"type"`
+		Filename    string `json:"filename"`
+		Disposition string `json:"disposition"`
+	}
 	type SendGridEmail struct {
 		Personalizations []SendGridPersonalization `json:"personalizations"`
 		From             map[string]string          `json:"from"`
 		Subject          string                     `json:"subject"`
 		Content          []SendGridContent          `json:"content"`
+		Attachments      []SendGridAttachment       `json:"attachments,omitempty"`
 	}
 
 	emailReq := SendGridEmail{
@@ -90,6 +115,14 @@ func sendEmailViaSendGrid(to, subject, htmlBody string) error {
 			},
 		},
 	}
+	for _, attachment := range attachments {
+		emailReq.Attachments = append(emailReq.Attachments, SendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(attachment.Content),
+			Type:        attachment.ContentType,
+			Filename:    attachment.Filename,
+			Disposition: "attachment",
+		})
+	}
 
 	jsonData, err := json.Marshal(emailReq)
 	if err != nil {
@@ -125,7 +158,7 @@ func sendEmailViaSendGrid(to, subject, htmlBody string) error {
 }
 
 // sendEmailViaResend sends email using Resend API
-func sendEmailViaResend(to, subject, htmlBody string) error {
+func sendEmailViaResend(to, subject, htmlBody string, attachments []EmailAttachment) error {
 	apiKey := os.Getenv("RESEND_API_KEY")
 	if apiKey == "" {
 		return fmt.Errorf("RESEND_API_KEY not set")
@@ -148,6 +181,13 @@ func sendEmailViaResend(to, subject, htmlBody string) error {
 		Subject: subject,
 		HTML:    htmlBody,
 	}
+	for _, attachment := range attachments {
+		emailReq.Attachments = append(emailReq.Attachments, ResendAttachment{
+			Filename:    attachment.Filename,
+			Content:     base64.StdEncoding.EncodeToString(attachment.Content),
+			ContentType: attachment.ContentType,
+		})
+	}
 
 	jsonData, err := json.Marshal(emailReq)
 	if err != nil {
@@ -184,13 +224,18 @@ func sendEmailViaResend(to, subject, htmlBody string) error {
 
 // sendEmail sends email using the configured provider
 func sendEmail(to, subject, htmlBody string) error {
+	return sendEmailWithAttachments(to, subject, htmlBody, nil)
+}
+
+// sendEmailWithAttachments sends email with file attachments using the configured provider
+func sendEmailWithAttachments(to, subject, htmlBody string, attachments []EmailAttachment) error {
 	provider := getEmailProvider()
 	
 	switch provider {
 	case ProviderResend:
-		return sendEmailViaResend(to, subject, htmlBody)
+		return sendEmailViaResend(to, subject, htmlBody, attachments)
 	case ProviderSendGrid:
-		return sendEmailViaSendGrid(to, subject, htmlBody)
+		return sendEmailViaSendGrid(to, subject, htmlBody, attachments)
 	default:
 		return fmt.Errorf("unknown email provider: %s", provider)
 	}
@@ -203,7 +248,8 @@ func SendConfirmationEmail(to, name, jobTitle, applicationID string) error {
 		frontendURL = "http://localhost:3000"
 	}
 	
-	// Create direct link to application status with pre-filled email and application ID
+	// Create direct link to application status with pre-filled email and application ID.
+	// The candidate still signs in with a link sent to their email.
 	statusLink := fmt.Sprintf("%s/application-status?email=%s&applicationId=%s", frontendURL, to, applicationID)
 	
 	subject := "Application Received - " + jobTitle
@@ -228,7 +274,7 @@ func SendConfirmationEmail(to, name, jobTitle, applicationID string) error {
 				</p>
 				<p style="font-size: 12px; color: #666; margin-top: 20px;">
 					<strong>Application ID:</strong> %s<br>
-					You'll need this along with your email (%s) to check your status in the future.
+					To check your status, we'll email a secure sign-in link to %s.
 				</p>
 				<p>You will hear from us soon!</p>
 				<br>
@@ -313,3 +359,221 @@ func SendCustomEmail(to, subject, html string) error {
 	return sendEmail(to, subject, html)
 }
 
+
+// SendSavedSearchDigestEmail notifies a recruiter about new candidates matching one of their saved searches
+func SendSavedSearchDigestEmail(to, name, searchName, savedSearchID string, matches []SavedSearchAlertMatch) error {
+	frontendURL := os.Getenv("FRONTEND_URL")
+	if frontendURL == "" {
+		frontendURL = "http://localhost:3000"
+	}
+
+	rows := ""
+	for _, match := range matches {
+		jobTitle := "Unknown Job (Job Deleted)"
+		if match.Application.JobID != nil && match.Application.Job.Title != "" {
+			jobTitle = match.Application.Job.Title
+		}
+		rows += fmt.Sprintf(`
+					<tr>
+						<td style="padding: 8px; border-bottom: 1px solid #e5e7eb;">%s</td>
+						<td style="padding: 8px; border-bottom: 1px solid #e5e7eb;">%s</td>
+						<td style="padding: 8px; border-bottom: 1px solid #e5e7eb; text-align: right;">%d%%</td>
+					</tr>`, match.Application.FullName, jobTitle, match.MatchScore)
+	}
+
+	subject := fmt.Sprintf("%d new candidate(s) for your saved search \"%s\"", len(matches), searchName)
+	html := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #2563eb;">Hello %s,</h2>
+				<p>New candidates match your saved search <strong>%s</strong>:</p>
+				<table style="width: 100%%; border-collapse: collapse; margin: 20px 0;">
+					<tr>
+						<th style="padding: 8px; text-align: left; border-bottom: 2px solid #2563eb;">Candidate</th>
+						<th style="padding: 8px; text-align: left; border-bottom: 2px solid #2563eb;">Job</th>
+						<th style="padding: 8px; text-align: right; border-bottom: 2px solid #2563eb;">Match</th>
+					</tr>%s
+				</table>
+				<p style="text-align: center; margin: 20px 0;">
+					<a href="%s/admin/dashboard/find-candidates?savedSearch=%s" style="background-color: #2563eb; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">View Candidates</a>
+				</p>
+				<p style="font-size: 12px; color: #666;">You're receiving this because alerts are enabled for this saved search.</p>
+			</div>
+		</body>
+		</html>
+	`, name, searchName, rows, frontendURL, savedSearchID)
+
+	return sendEmail(to, subject, html)
+}
+
+// SendInterviewInviteEmail sends an interview invitation, update or cancellation with the .ics attached.
+// change is one of "scheduled", "rescheduled" or "cancelled".
+func SendInterviewInviteEmail(to, name, roundName, jobTitle, when, location, change string, ics []byte) error {
+	method := CalendarMethodRequest
+	subject := fmt.Sprintf("Interview invitation: %s - %s", roundName, jobTitle)
+	heading := "You're invited to an interview"
+	color := "#2563eb"
+	switch change {
+	case "rescheduled":
+		subject = fmt.Sprintf("Interview rescheduled: %s - %s", roundName, jobTitle)
+		heading = "Your interview has been rescheduled"
+	case "cancelled":
+		method = CalendarMethodCancel
+		subject = fmt.Sprintf("Interview cancelled: %s - %s", roundName, jobTitle)
+		heading = "Your interview has been cancelled"
+		color = "#dc2626"
+	}
+
+	locationRow := ""
+	if location != "" {
+		locationRow = fmt.Sprintf(`<p><strong>Where:</strong> %s</p>`, location)
+	}
+
+	html := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: %s;">Hello %s,</h2>
+				<p>%s: <strong>%s</strong> for the <strong>%s</strong> position.</p>
+				<p><strong>When:</strong> %s</p>
+				%s
+				<p>The calendar invitation is attached - open it to add the interview to your calendar.</p>
+				<br>
+				<p>Best regards,<br>The Hiring Team</p>
+			</div>
+		</body>
+		</html>
+	`, color, name, heading, roundName, jobTitle, when, locationRow)
+
+	return sendEmailWithAttachments(to, subject, html, []EmailAttachment{
+		{
+			Filename:    "invite.ics",
+			ContentType: "text/calendar; charset=utf-8; method=" + method,
+			Content:     ics,
+		},
+	})
+}
+
+// SendSchedulingLinkEmail invites a candidate to pick an interview time through their scheduling link
+func SendSchedulingLinkEmail(to, name, jobTitle, roundName, schedulingURL string, expiresAt time.Time) error {
+	subject := fmt.Sprintf("Pick a time for your %s - %s", roundName, jobTitle)
+	html := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #2563eb;">Hello %s,</h2>
+				<p>We'd like to invite you to a <strong>%s</strong> for the <strong>%s</strong> position.</p>
+				<p>Please choose the time that suits you best:</p>
+				<p style="text-align: center; margin: 20px 0;">
+					<a href="%s" style="background-color: #2563eb; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Choose Interview Time</a>
+				</p>
+				<p style="font-size: 12px; color: #666;">This link is personal and expires on %s.</p>
+				<br>
+				<p>Best regards,<br>The Hiring Team</p>
+			</div>
+		</body>
+		</html>
+	`, name, roundName, jobTitle, schedulingURL, expiresAt.UTC().Format("Mon, 02 Jan 2006 15:04 UTC"))
+
+	return sendEmail(to, subject, html)
+}
+
+// SendCandidateMagicLinkEmail sends a candidate the link that signs them in to the application status portal
+func SendCandidateMagicLinkEmail(to, loginURL string, expiresAt time.Time) error {
+	subject := "Your sign-in link to check your applications"
+	html := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #2563eb;">Hello,</h2>
+				<p>Use the button below to sign in and check the status of your applications:</p>
+				<p style="text-align: center; margin: 20px 0;">
+					<a href="%s" style="background-color: #2563eb; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">Sign In</a>
+				</p>
+				<p style="font-size: 12px; color: #666;">This link can only be used once and expires on %s. If you didn't ask to sign in, you can ignore this email.</p>
+				<br>
+				<p>Best regards,<br>The Hiring Team</p>
+			</div>
+		</body>
+		</html>
+	`, loginURL, expiresAt.UTC().Format("Mon, 02 Jan 2006 15:04 UTC"))
+
+	return sendEmail(to, subject, html)
+}
+
+// SendOfferEmail invites a candidate to review and respond to their job offer
+func SendOfferEmail(to, name, jobTitle, companyName, offerURL string, expiresAt time.Time) error {
+	subject := fmt.Sprintf("Your offer from %s - %s", companyName, jobTitle)
+	html := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #16a34a;">Congratulations %s!</h2>
+				<p>We're delighted to offer you the <strong>%s</strong> position at <strong>%s</strong>.</p>
+				<p>Please review the details of your offer and let us know your decision:</p>
+				<p style="text-align: center; margin: 20px 0;">
+					<a href="%s" style="background-color: #16a34a; color: white; padding: 12px 24px; text-decoration: none; border-radius: 5px; display: inline-block;">View Your Offer</a>
+				</p>
+				<p style="font-size: 12px; color: #666;">This link is personal. The offer is valid until %s.</p>
+				<br>
+				<p>Best regards,<br>The Hiring Team</p>
+			</div>
+		</body>
+		</html>
+	`, name, jobTitle, companyName, offerURL, expiresAt.UTC().Format("Mon, 02 Jan 2006 15:04 UTC"))
+
+	return sendEmail(to, subject, html)
+}
+
+// SendJobClosedEmail tells an applicant still in the process that a job stopped taking applications,
+// or that all its positions were filled
+func SendJobClosedEmail(to, name, jobTitle, companyName string, filled bool, message string) error {
+	subject := fmt.Sprintf("Update on the %s position at %s", jobTitle, companyName)
+	update := "Applications for this position have now closed. Your application is still with our team and we'll be in touch about next steps."
+	if filled {
+		update = "This position has now been filled. Thank you for the time you put into your application; we'd be glad to see you apply for future openings."
+	}
+	if message != "" {
+		update = strings.ReplaceAll(template.HTMLEscapeString(message), "\n", "<br>")
+	}
+	html := fmt.Sprintf(`
+		<!DOCTYPE html>
+		<html>
+		<head>
+			<meta charset="UTF-8">
+		</head>
+		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
+			<div style="max-width: 600px; margin: 0 auto; padding: 20px;">
+				<h2 style="color: #2563eb;">Hello %s,</h2>
+				<p>Thank you for applying for the <strong>%s</strong> position at <strong>%s</strong>.</p>
+				<p>%s</p>
+				<br>
+				<p>Best regards,<br>The Hiring Team</p>
+			</div>
+		</body>
+		</html>
+	`, name, jobTitle, companyName, update)
+
+	return sendEmail(to, subject, html)
+}
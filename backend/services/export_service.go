@@ -0,0 +1,342 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"ats-backend/utils"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Export file formats
+const (
+	ExportFormatCSV  = "csv"
+	ExportFormatXLSX = "xlsx"
+)
+
+// ExportInlineRowLimit is the largest export streamed straight back; bigger ones run as a background job
+const ExportInlineRowLimit = 1000
+
+// ExportRetention is how long a background export can be downloaded
+const ExportRetention = 7 * 24 * time.Hour
+
+// ExportRow is one application with the extra data export columns can show
+type ExportRow struct {
+	Application models.Application
+	StageName   string
+	NotesCount  int64
+	MatchScore  *int // Only set for candidate search exports
+	analysis    *MatchResult
+}
+
+// ExportColumn is a column callers can pick for an export
+type ExportColumn struct {
+	Key     string
+	Header  string
+	Numeric bool
+	Value   func(row ExportRow) string
+}
+
+// exportColumns lists every exportable column, in their default order
+var exportColumns = []ExportColumn{
+	{Key: "full_name", Header: "Name", Value: func(r ExportRow) string { return r.Application.FullName }},
+	{Key: "email", Header: "Email", Value: func(r ExportRow) string { return r.Application.Email }},
+	{Key: "phone", Header: "Phone", Value: func(r ExportRow) string { return r.Application.Phone }},
+	{Key: "current_position", Header: "Current Position", Value: func(r ExportRow) string { return r.Application.CurrentPosition }},
+	{Key: "years_of_experience", Header: "Years of Experience", Numeric: true, Value: func(r ExportRow) string {
+		return strconv.Itoa(r.Application.YearsOfExperience)
+	}},
+	{Key: "linkedin_url", Header: "LinkedIn", Value: func(r ExportRow) string { return r.Application.LinkedinURL }},
+	{Key: "portfolio_url", Header: "Portfolio", Value: func(r ExportRow) string { return r.Application.PortfolioURL }},
+	{Key: "resume_url", Header: "CV", Value: func(r ExportRow) string { return r.Application.ResumeURL }},
+	{Key: "job_title", Header: "Job", Value: func(r ExportRow) string {
+		if r.Application.JobID == nil || r.Application.Job.ID == uuid.Nil {
+			return "Unknown Job (Job Deleted)"
+		}
+		return r.Application.Job.Title
+	}},
+	{Key: "stage", Header: "Stage", Value: func(r ExportRow) string { return r.StageName }},
+	{Key: "stage_key", Header: "Stage Key", Value: func(r ExportRow) string { return r.Application.Status }},
+	{Key: "score", Header: "Score", Numeric: true, Value: func(r ExportRow) string { return strconv.Itoa(r.Application.Score) }},
+	{Key: "match_score", Header: "Search Match", Numeric: true, Value: func(r ExportRow) string {
+		if r.MatchScore == nil {
+			return ""
+		}
+		return strconv.Itoa(*r.MatchScore)
+	}},
+	{Key: "matched_skills", Header: "Matched Skills", Value: func(r ExportRow) string {
+		if r.analysis == nil {
+			return ""
+		}
+		return strings.Join(r.analysis.Skills, ", ")
+	}},
+	{Key: "missing_skills", Header: "Missing Skills", Value: func(r ExportRow) string {
+		if r.analysis == nil {
+			return ""
+		}
+		return strings.Join(r.analysis.MissingSkills, ", ")
+	}},
+	{Key: "source", Header: "Source", Value: func(r ExportRow) string { return r.Application.ReferralSource }},
+	{Key: "referred_by", Header: "Referred By", Value: func(r ExportRow) string { return r.Application.ReferredByName }},
+	{Key: "in_talent_pool", Header: "Talent Pool", Value: func(r ExportRow) string {
+		if r.Application.InTalentPool {
+			return "yes"
+		}
+		return "no"
+	}},
+	{Key: "tags", Header: "Tags", Value: func(r ExportRow) string { return strings.Join(decodeExportList(r.Application.Tags), ", ") }},
+	{Key: "screening_result", Header: "Screening", Value: func(r ExportRow) string { return r.Application.ScreeningResult }},
+	{Key: "applied_at", Header: "Applied At", Value: func(r ExportRow) string { return r.Application.AppliedAt.Format("2006-01-02 15:04") }},
+	{Key: "last_status_update", Header: "Last Update", Value: func(r ExportRow) string {
+		if r.Application.LastStatusUpdate == nil {
+			return ""
+		}
+		return r.Application.LastStatusUpdate.Format("2006-01-02 15:04")
+	}},
+	{Key: "notes_count", Header: "Notes", Numeric: true, Value: func(r ExportRow) string { return strconv.FormatInt(r.NotesCount, 10) }},
+}
+
+// DefaultExportColumns are exported when the caller doesn't pick any
+var DefaultExportColumns = []string{"full_name", "email", "phone", "job_title", "stage", "score", "source", "applied_at"}
+
+// ExportColumnKeys returns the keys of every exportable column
+func ExportColumnKeys() []string {
+	keys := make([]string, 0, len(exportColumns))
+	for _, column := range exportColumns {
+		keys = append(keys, column.Key)
+	}
+	return keys
+}
+
+// ResolveExportColumns looks up the requested columns, in the requested order
+func ResolveExportColumns(keys []string) ([]ExportColumn, error) {
+	if len(keys) == 0 {
+		keys = DefaultExportColumns
+	}
+	columns := make([]ExportColumn, 0, len(keys))
+	for _, key := range keys {
+		found := false
+		for _, column := range exportColumns {
+			if column.Key == key {
+				columns = append(columns, column)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown export column %q", key)
+		}
+	}
+	return columns, nil
+}
+
+// IsValidExportFormat reports whether format is csv or xlsx
+func IsValidExportFormat(format string) bool {
+	return format == ExportFormatCSV || format == ExportFormatXLSX
+}
+
+// ExportContentType returns the MIME type of an export format
+func ExportContentType(format string) string {
+	if format == ExportFormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ExportFilename names an export file, e.g. applications-2024-05-01.csv
+func ExportFilename(source, format string) string {
+	return fmt.Sprintf("%s-%s.%s", source, time.Now().Format("2006-01-02"), format)
+}
+
+// BuildExportRows adds stage names, notes counts and the parsed AI analysis to applications.
+// matchScores holds the search match score per application for candidate search exports.
+func BuildExportRows(applications []models.Application, matchScores map[uuid.UUID]int) []ExportRow {
+	notesCounts := map[uuid.UUID]int64{}
+	for start := 0; start < len(applications); start += 1000 {
+		end := start + 1000
+		if end > len(applications) {
+			end = len(applications)
+		}
+		ids := make([]uuid.UUID, 0, end-start)
+		for _, application := range applications[start:end] {
+			ids = append(ids, application.ID)
+		}
+
+		var counts []struct {
+			ApplicationID uuid.UUID
+			Count         int64
+		}
+		if err := config.DB.Model(&models.CandidateNote{}).
+			Select("application_id, COUNT(*) AS count").
+			Where("application_id IN ?", ids).
+			Group("application_id").
+			Scan(&counts).Error; err != nil {
+			log.Printf("ERROR: Failed to count notes for export: %v", err)
+		}
+		for _, count := range counts {
+			notesCounts[count.ApplicationID] = count.Count
+		}
+	}
+
+	// Pipelines are shared by every application of a job
+	pipelines := map[string][]models.PipelineStage{}
+	rows := make([]ExportRow, 0, len(applications))
+	for _, application := range applications {
+		pipelineKey := application.CompanyID.String()
+		if application.JobID != nil {
+			pipelineKey += "/" + application.JobID.String()
+		}
+		stages, ok := pipelines[pipelineKey]
+		if !ok {
+			stages, _ = GetPipelineStages(application.CompanyID, application.JobID)
+			pipelines[pipelineKey] = stages
+		}
+
+		row := ExportRow{
+			Application: application,
+			StageName:   application.Status,
+			NotesCount:  notesCounts[application.ID],
+		}
+		if stage, found := FindPipelineStage(stages, application.Status); found {
+			row.StageName = stage.Name
+		}
+		if score, ok := matchScores[application.ID]; ok {
+			row.MatchScore = &score
+		}
+		if application.AnalysisResult != nil && *application.AnalysisResult != "" {
+			var analysis MatchResult
+			if err := json.Unmarshal([]byte(*application.AnalysisResult), &analysis); err == nil {
+				row.analysis = &analysis
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// WriteExport writes rows as a CSV or XLSX file with a header row
+func WriteExport(w io.Writer, format string, columns []ExportColumn, rows []ExportRow) error {
+	header := make([]string, len(columns))
+	numeric := make([]bool, len(columns))
+	for i, column := range columns {
+		header[i] = column.Header
+		numeric[i] = column.Numeric
+	}
+
+	values := make([][]string, 0, len(rows))
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			record[i] = column.Value(row)
+		}
+		values = append(values, record)
+	}
+
+	if format == ExportFormatXLSX {
+		return utils.WriteXLSX(w, "Candidates", header, numeric, values)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, record := range values {
+		for i := range record {
+			if !numeric[i] {
+				record[i] = escapeSpreadsheetFormula(record[i])
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// escapeSpreadsheetFormula stops candidate-supplied text from running as a formula when a CSV is opened in a spreadsheet
+func escapeSpreadsheetFormula(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+// decodeExportList decodes a JSON array column, returning nothing when it is empty or invalid
+func decodeExportList(value string) []string {
+	var items []string
+	if value == "" || json.Unmarshal([]byte(value), &items) != nil {
+		return nil
+	}
+	return items
+}
+
+// RunExportJob builds a background export: it loads the rows, writes the file onto the job and
+// notifies the admin who asked for it. Meant to run in its own goroutine.
+func RunExportJob(job models.ExportJob, columns []ExportColumn, load func() ([]ExportRow, error)) {
+	config.DB.Model(&models.ExportJob{}).Where("id = ?", job.ID).Update("status", models.ExportStatusProcessing)
+
+	fail := func(err error) {
+		log.Printf("ERROR: Export %s failed: %v", job.ID, err)
+		config.DB.Model(&models.ExportJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+			"status": models.ExportStatusFailed,
+			"error":  err.Error(),
+		})
+		if notifyErr := CreateNotification(job.CompanyID, job.AdminID, "export_failed", "Export failed",
+			"Your "+strings.ToUpper(job.Format)+" export could not be created. Please try again.", "export", &job.ID); notifyErr != nil {
+			log.Printf("ERROR: Failed to notify admin %s about export %s: %v", job.AdminID, job.ID, notifyErr)
+		}
+	}
+
+	rows, err := load()
+	if err != nil {
+		fail(err)
+		return
+	}
+
+	var buffer bytes.Buffer
+	if err := WriteExport(&buffer, job.Format, columns, rows); err != nil {
+		fail(err)
+		return
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(ExportRetention)
+	if err := config.DB.Model(&models.ExportJob{}).Where("id = ?", job.ID).Updates(map[string]interface{}{
+		"status":       models.ExportStatusCompleted,
+		"row_count":    len(rows),
+		"data":         buffer.Bytes(),
+		"completed_at": now,
+		"expires_at":   expiresAt,
+	}).Error; err != nil {
+		fail(err)
+		return
+	}
+
+	log.Printf("SUCCESS: Export %s completed with %d row(s)", job.ID, len(rows))
+	if err := CreateNotification(job.CompanyID, job.AdminID, "export_ready", "Export ready",
+		fmt.Sprintf("Your %s export of %d row(s) is ready to download", strings.ToUpper(job.Format), len(rows)), "export", &job.ID); err != nil {
+		log.Printf("ERROR: Failed to notify admin %s about export %s: %v", job.AdminID, job.ID, err)
+	}
+}
+
+// PurgeExpiredExports deletes background exports whose download window has passed
+func PurgeExpiredExports() error {
+	result := config.DB.Where("expires_at <= ?", time.Now()).Delete(&models.ExportJob{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Purged %d expired export(s)", result.RowsAffected)
+	}
+	return nil
+}
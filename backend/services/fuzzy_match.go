@@ -0,0 +1,237 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// FuzzyOptions tunes approximate matching of skills and search terms.
+// Fuzzy matching is off unless Enabled is set; zero values fall back to the defaults below.
+type FuzzyOptions struct {
+	Enabled          bool    `json:"enabled"`
+	MaxDistance      int     `json:"max_distance"`      // Max edit distance (default: 1 for 5-7 chars, 2 for 8+)
+	MinTokenLength   int     `json:"min_token_length"`  // Shorter terms only match exactly (default 5)
+	TrigramThreshold float64 `json:"trigram_threshold"` // Min trigram similarity for search terms, 0-1 (default 0.45)
+}
+
+// FuzzyHit is evidence that a term matched approximately rather than exactly
+type FuzzyHit struct {
+	Term        string  `json:"term"`         // Skill or search term that was looked for
+	MatchedText string  `json:"matched_text"` // Text in the CV that matched it
+	Method      string  `json:"method"`       // edit_distance or trigram
+	Distance    int     `json:"distance,omitempty"`
+	Similarity  float64 `json:"similarity,omitempty"`
+}
+
+const (
+	defaultFuzzyMinTokenLength   = 5
+	defaultFuzzyTrigramThreshold = 0.45
+)
+
+// fuzzyScorePercent is how much an approximate hit counts compared to an exact one
+const fuzzyScorePercent = 75
+
+// minTokenLength returns the effective minimum term length for fuzzy matching
+func (opts *FuzzyOptions) minTokenLength() int {
+	if opts.MinTokenLength > 0 {
+		return opts.MinTokenLength
+	}
+	return defaultFuzzyMinTokenLength
+}
+
+// maxDistanceFor returns the allowed edit distance for a term of the given length
+func (opts *FuzzyOptions) maxDistanceFor(length int) int {
+	if length < opts.minTokenLength() {
+		return 0
+	}
+	allowed := 1
+	if length >= 8 {
+		allowed = 2
+	}
+	if opts.MaxDistance > 0 && opts.MaxDistance < allowed {
+		allowed = opts.MaxDistance
+	}
+	return allowed
+}
+
+// trigramThreshold returns the effective trigram similarity threshold
+func (opts *FuzzyOptions) trigramThreshold() float64 {
+	if opts.TrigramThreshold > 0 && opts.TrigramThreshold <= 1 {
+		return opts.TrigramThreshold
+	}
+	return defaultFuzzyTrigramThreshold
+}
+
+// active reports whether fuzzy matching should be attempted
+func (opts *FuzzyOptions) active() bool {
+	return opts != nil && opts.Enabled
+}
+
+// tokenizeText splits text into unique lowercase word tokens (keeping characters like + and # used in skill names)
+func tokenizeText(text string) []string {
+	seen := map[string]bool{}
+	tokens := []string{}
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '+' && r != '#'
+	})
+	for _, field := range fields {
+		if !seen[field] {
+			seen[field] = true
+			tokens = append(tokens, field)
+		}
+	}
+	return tokens
+}
+
+// levenshteinDistance returns the edit distance between a and b, or limit+1 once it is known to exceed limit
+func levenshteinDistance(a, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if abs(len(ra)-len(rb)) > limit {
+		return limit + 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		rowMin := current[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(minInt(previous[j]+1, current[j-1]+1), previous[j-1]+cost)
+			if current[j] < rowMin {
+				rowMin = current[j]
+			}
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+// trigrams returns the padded character trigrams of a word (same scheme as PostgreSQL pg_trgm)
+func trigrams(word string) map[string]bool {
+	padded := []rune("  " + word + " ")
+	grams := map[string]bool{}
+	for i := 0; i+3 <= len(padded); i++ {
+		grams[string(padded[i:i+3])] = true
+	}
+	return grams
+}
+
+// TrigramSimilarity returns the share of trigrams two words have in common (0-1)
+func TrigramSimilarity(a, b string) float64 {
+	ga, gb := trigrams(strings.ToLower(a)), trigrams(strings.ToLower(b))
+	if len(ga) == 0 || len(gb) == 0 {
+		return 0
+	}
+	shared := 0
+	for gram := range ga {
+		if gb[gram] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ga)+len(gb)-shared)
+}
+
+// fuzzyFindTerm looks for an approximate occurrence of term among the CV tokens.
+// Multi-word terms match when every word matches (short words must match exactly).
+// Trigram similarity is only used when useTrigrams is set (free-text search terms).
+func fuzzyFindTerm(term string, tokens []string, opts *FuzzyOptions, useTrigrams bool) (*FuzzyHit, bool) {
+	if !opts.active() {
+		return nil, false
+	}
+
+	words := strings.Fields(strings.ToLower(term))
+	if len(words) == 0 {
+		return nil, false
+	}
+
+	matched := []string{}
+	var best *FuzzyHit
+	for _, word := range words {
+		hit, ok := fuzzyFindWord(word, tokens, opts, useTrigrams)
+		if !ok {
+			return nil, false
+		}
+		matched = append(matched, hit.MatchedText)
+		if best == nil || fuzzyHitRank(hit) > fuzzyHitRank(best) {
+			best = hit
+		}
+	}
+
+	// Every word was present exactly, just not as a phrase - that's not an approximate match
+	if best.Method == "exact" {
+		return nil, false
+	}
+
+	best.Term = term
+	best.MatchedText = strings.Join(matched, " ")
+	return best, true
+}
+
+// fuzzyHitRank orders hits from most to least certain, so a phrase reports its weakest word
+func fuzzyHitRank(hit *FuzzyHit) int {
+	switch hit.Method {
+	case "exact":
+		return 0
+	case "edit_distance":
+		return hit.Distance
+	default:
+		return 100
+	}
+}
+
+// fuzzyFindWord finds the closest CV token for a single word
+func fuzzyFindWord(word string, tokens []string, opts *FuzzyOptions, useTrigrams bool) (*FuzzyHit, bool) {
+	length := len([]rune(word))
+	maxDistance := opts.maxDistanceFor(length)
+
+	var best *FuzzyHit
+	for _, token := range tokens {
+		if token == word {
+			return &FuzzyHit{Term: word, MatchedText: token, Method: "exact"}, true
+		}
+		if maxDistance == 0 {
+			continue
+		}
+		if distance := levenshteinDistance(word, token, maxDistance); distance <= maxDistance {
+			if best == nil || best.Method != "edit_distance" || distance < best.Distance {
+				best = &FuzzyHit{Term: word, MatchedText: token, Method: "edit_distance", Distance: distance}
+			}
+			continue
+		}
+		if useTrigrams && best == nil && len([]rune(token)) >= opts.minTokenLength() {
+			if similarity := TrigramSimilarity(word, token); similarity >= opts.trigramThreshold() {
+				best = &FuzzyHit{Term: word, MatchedText: token, Method: "trigram", Similarity: similarity}
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, false
+	}
+	return best, true
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		name  string
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"identical", "golang", "golang", 2, 0},
+		{"within the limit", "kitten", "sitting", 3, 3},
+		{"cut off once a row exceeds the limit", "kitten", "sitting", 1, 2},
+		{"cut off on length difference", "go", "golang", 1, 2},
+		{"empty string", "", "abc", 3, 3},
+		{"multi-byte rune is one edit", "müller", "muller", 1, 1},
+		{"multi-byte rune and an insertion", "straße", "strasse", 2, 2},
+		{"transposition is two edits", "python", "pyhton", 2, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := levenshteinDistance(tt.a, tt.b, tt.limit); got != tt.want {
+				t.Errorf("levenshteinDistance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrigramSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want float64
+	}{
+		{"identical", "postgres", "postgres", 1},
+		{"case-insensitive", "Java", "java", 1},
+		{"nothing in common", "abc", "xyz", 0},
+		{"shared prefix", "react", "reactjs", 5.0 / 9.0},
+		{"transposed letters", "python", "pyhton", 3.0 / 11.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrigramSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("TrigramSimilarity(%q, %q) = %.4f, want %.4f", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizeText(t *testing.T) {
+	got := tokenizeText("C++ and C#, Go/Rust and go")
+	want := []string{"c++", "and", "c#", "go", "rust"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenizeText() = %v, want %v", got, want)
+	}
+}
+
+func TestFuzzyFindTerm(t *testing.T) {
+	enabled := &FuzzyOptions{Enabled: true}
+
+	tests := []struct {
+		name        string
+		term        string
+		tokens      []string
+		opts        *FuzzyOptions
+		useTrigrams bool
+		wantOK      bool
+		wantMethod  string
+		wantMatched string
+		wantDist    int
+	}{
+		{"disabled", "kubernetes", []string{"kubernets"}, &FuzzyOptions{}, false, false, "", "", 0},
+		{"nil options", "kubernetes", []string{"kubernets"}, nil, false, false, "", "", 0},
+		{"one edit", "kubernetes", []string{"docker", "kubernets"}, enabled, false, true, "edit_distance", "kubernets", 1},
+		{"two edits for long terms", "javascript", []string{"javscrpt"}, enabled, false, true, "edit_distance", "javscrpt", 2},
+		{"beyond the limit", "python", []string{"pyhton"}, enabled, false, false, "", "", 0},
+		{"beyond the limit and below the trigram threshold", "python", []string{"pyhton"}, enabled, true, false, "", "", 0},
+		{"max distance option caps the limit", "kubernetes", []string{"kubernts"}, &FuzzyOptions{Enabled: true, MaxDistance: 1}, false, false, "", "", 0},
+		{"short token only matches exactly", "java", []string{"jawa"}, enabled, false, false, "", "", 0},
+		{"short token exact is not approximate", "java", []string{"java"}, enabled, false, false, "", "", 0},
+		{"min token length option", "java", []string{"jawa"}, &FuzzyOptions{Enabled: true, MinTokenLength: 3}, false, true, "edit_distance", "jawa", 1},
+		{"multi-byte runes", "müller", []string{"muller"}, enabled, false, true, "edit_distance", "muller", 1},
+		{"trigram when edits are too many", "reactjs", []string{"react"}, enabled, true, true, "trigram", "react", 0},
+		{"no trigram for skills", "reactjs", []string{"react"}, enabled, false, false, "", "", 0},
+		{"every word present exactly", "machine learning", []string{"learning", "machine"}, enabled, false, false, "", "", 0},
+		{"phrase with one approximate word", "machine learning", []string{"machine", "lerning"}, enabled, false, true, "edit_distance", "machine lerning", 1},
+		{"phrase with a missing word", "machine learning", []string{"machine"}, enabled, false, false, "", "", 0},
+		{"short word in a phrase must be exact", "go developer", []string{"gp", "developr"}, enabled, false, false, "", "", 0},
+		{"short word in a phrase present", "go developer", []string{"go", "developr"}, enabled, false, true, "edit_distance", "go developr", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit, ok := fuzzyFindTerm(tt.term, tt.tokens, tt.opts, tt.useTrigrams)
+			if ok != tt.wantOK {
+				t.Fatalf("fuzzyFindTerm(%q, %v) ok = %v, want %v (hit %+v)", tt.term, tt.tokens, ok, tt.wantOK, hit)
+			}
+			if !ok {
+				return
+			}
+			if hit.Term != tt.term || hit.Method != tt.wantMethod || hit.MatchedText != tt.wantMatched || hit.Distance != tt.wantDist {
+				t.Errorf("fuzzyFindTerm(%q, %v) = %+v, want method %s, matched %q, distance %d",
+					tt.term, tt.tokens, hit, tt.wantMethod, tt.wantMatched, tt.wantDist)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"ats-backend/models"
+
+	"gorm.io/gorm"
+)
+
+// HeadcountStats compares the positions jobs were opened for with the positions filled.
+// Jobs still being drafted are left out.
+type HeadcountStats struct {
+	Openings          int64   `json:"openings"`            // Positions of jobs with a headcount
+	Filled            int64   `json:"filled"`              // Of those, the positions filled
+	Remaining         int64   `json:"remaining"`           // Openings not filled yet
+	Hired             int64   `json:"hired"`               // All hires, including jobs without a headcount
+	JobsWithHeadcount int64   `json:"jobs_with_headcount"` // Jobs with a headcount
+	FilledJobs        int64   `json:"filled_jobs"`         // Of those, the jobs with every position filled
+	FillRate          float64 `json:"fill_rate"`           // Filled / openings, 0 to 1
+}
+
+// JobHeadcount is one job's openings and hires
+type JobHeadcount struct {
+	JobID      string `json:"job_id"`
+	Title      string `json:"title"`
+	Status     string `json:"status"`
+	Headcount  int    `json:"headcount"` // 0 = not limited
+	HiredCount int    `json:"hired_count"`
+	Remaining  int    `json:"remaining"`
+}
+
+// headcountJobs leaves drafts out of a jobs query
+func headcountJobs(jobs *gorm.DB) *gorm.DB {
+	return jobs.Model(&models.Job{}).Where("jobs.status <> ?", "draft")
+}
+
+// SummarizeHeadcount returns the openings and hires of the jobs a query selects
+func SummarizeHeadcount(jobs *gorm.DB) (HeadcountStats, error) {
+	var stats HeadcountStats
+	err := headcountJobs(jobs).
+		Select(`COALESCE(SUM(headcount), 0) AS openings,
+			COALESCE(SUM(LEAST(hired_count, headcount)), 0) AS filled,
+			COALESCE(SUM(hired_count), 0) AS hired,
+			COUNT(*) FILTER (WHERE headcount > 0) AS jobs_with_headcount,
+			COUNT(*) FILTER (WHERE headcount > 0 AND hired_count >= headcount) AS filled_jobs`).
+		Scan(&stats).Error
+	if err != nil {
+		return stats, err
+	}
+	stats.Remaining = stats.Openings - stats.Filled
+	if stats.Openings > 0 {
+		stats.FillRate = float64(stats.Filled) / float64(stats.Openings)
+	}
+	return stats, nil
+}
+
+// ListJobHeadcounts returns the openings and hires of each job a query selects, least filled first
+func ListJobHeadcounts(jobs *gorm.DB) ([]JobHeadcount, error) {
+	var rows []models.Job
+	err := headcountJobs(jobs).
+		Select("id, title, status, headcount, hired_count").
+		Order("(headcount > 0) DESC, (headcount - hired_count) DESC, title ASC").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	headcounts := make([]JobHeadcount, 0, len(rows))
+	for _, job := range rows {
+		remaining := 0
+		if job.Headcount > job.HiredCount {
+			remaining = job.Headcount - job.HiredCount
+		}
+		headcounts = append(headcounts, JobHeadcount{
+			JobID:      job.ID.String(),
+			Title:      job.Title,
+			Status:     job.Status,
+			Headcount:  job.Headcount,
+			HiredCount: job.HiredCount,
+			Remaining:  remaining,
+		})
+	}
+	return headcounts, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobScope is the set of jobs an admin may see and act on. The zero value is company-wide.
+type JobScope struct {
+	Restricted bool        // Only the jobs in JobIDs
+	JobIDs     []uuid.UUID // Jobs whose hiring team the admin is on
+}
+
+// IsValidAdminRole reports whether an admin role is known
+func IsValidAdminRole(role string) bool {
+	return role == models.AdminRoleAdmin || role == models.AdminRoleMember
+}
+
+// IsCompanyWideRole reports whether an admin role sees every job of the company
+func IsCompanyWideRole(role string) bool {
+	return role == models.AdminRoleAdmin
+}
+
+// IsValidJobMemberRole reports whether a hiring team role is known
+func IsValidJobMemberRole(role string) bool {
+	switch role {
+	case models.JobMemberRecruiter, models.JobMemberHiringManager, models.JobMemberInterviewer:
+		return true
+	}
+	return false
+}
+
+// LoadJobScope returns the jobs an admin may see: all of them for company-wide roles,
+// otherwise those whose hiring team they're on
+func LoadJobScope(adminID uuid.UUID) (JobScope, error) {
+	var admin models.Admin
+	if err := config.DB.Select("id, role").First(&admin, "id = ?", adminID).Error; err != nil {
+		return JobScope{}, err
+	}
+	if IsCompanyWideRole(admin.Role) {
+		return JobScope{}, nil
+	}
+
+	scope := JobScope{Restricted: true, JobIDs: []uuid.UUID{}}
+	err := config.DB.Model(&models.JobMember{}).
+		Where("admin_id = ?", adminID).
+		Pluck("job_id", &scope.JobIDs).Error
+	return scope, err
+}
+
+// Apply limits a query to the jobs of the scope, column being the job ID column to filter on
+func (s JobScope) Apply(query *gorm.DB, column string) *gorm.DB {
+	if !s.Restricted {
+		return query
+	}
+	if len(s.JobIDs) == 0 {
+		return query.Where("1 = 0")
+	}
+	return query.Where(fmt.Sprintf("%s IN ?", column), s.JobIDs)
+}
+
+// Allows reports whether the scope includes a job. Applications whose job was deleted
+// are only visible company-wide.
+func (s JobScope) Allows(jobID *uuid.UUID) bool {
+	if !s.Restricted {
+		return true
+	}
+	if jobID == nil {
+		return false
+	}
+	for _, id := range s.JobIDs {
+		if id == *jobID {
+			return true
+		}
+	}
+	return false
+}
+
+// jobMemberRoleLabel returns a readable name of a hiring team role
+func jobMemberRoleLabel(role string) string {
+	switch role {
+	case models.JobMemberHiringManager:
+		return "hiring manager"
+	case models.JobMemberInterviewer:
+		return "interviewer"
+	}
+	return "recruiter"
+}
+
+// NotifyJobMemberChange tells an admin they were added to a job's hiring team, given another role on it or removed.
+// role is empty when they were removed.
+func NotifyJobMemberChange(job models.Job, adminID uuid.UUID, role string) {
+	notificationType := "job_member_removed"
+	title := "Removed from hiring team"
+	message := fmt.Sprintf("You are no longer on the hiring team of %s", job.Title)
+	if role != "" {
+		notificationType = "job_member_assigned"
+		title = "Added to hiring team"
+		message = fmt.Sprintf("You are the %s of %s", jobMemberRoleLabel(role), job.Title)
+	}
+	if err := CreateNotification(job.CompanyID, adminID, notificationType, title, message, "job", &job.ID); err != nil {
+		log.Printf("ERROR: Failed to notify admin %s about the hiring team of job %s: %v", adminID, job.ID, err)
+	}
+}
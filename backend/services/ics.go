@@ -0,0 +1,154 @@
+package services
+
+import (
+	"ats-backend/models"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Calendar methods (RFC 5546) used for interview invitations
+const (
+	CalendarMethodRequest = "REQUEST" // New invite or update
+	CalendarMethodCancel  = "CANCEL"
+)
+
+// CalendarAttendee is a participant of a calendar event
+type CalendarAttendee struct {
+	Name  string
+	Email string
+}
+
+// CalendarEvent holds what goes into an .ics invitation
+type CalendarEvent struct {
+	UID         string
+	Sequence    int
+	Method      string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time
+	Organizer   CalendarAttendee
+	Attendees   []CalendarAttendee
+	Cancelled   bool
+}
+
+const icsTimeFormat = "20060102T150405Z"
+
+// escapeICSText escapes a TEXT value (RFC 5545 section 3.3.11)
+func escapeICSText(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return replacer.Replace(value)
+}
+
+// foldICSLine folds a content line at 75 octets (RFC 5545 section 3.1), without splitting UTF-8 characters
+func foldICSLine(line string) string {
+	if len(line) <= 75 {
+		return line
+	}
+	var folded strings.Builder
+	width := 0
+	limit := 75
+	for _, r := range line {
+		size := len(string(r))
+		if width+size > limit {
+			folded.WriteString("\r\n ")
+			width = 0
+			limit = 74 // Continuation lines start with a space
+		}
+		folded.WriteRune(r)
+		width += size
+	}
+	return folded.String()
+}
+
+// BuildICS renders a calendar event as an RFC 5545 iCalendar file
+func BuildICS(event CalendarEvent) []byte {
+	method := event.Method
+	if method == "" {
+		method = CalendarMethodRequest
+	}
+	status := "CONFIRMED"
+	if event.Cancelled || method == CalendarMethodCancel {
+		status = "CANCELLED"
+	}
+
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//ATS//Interview Scheduling//EN",
+		"CALSCALE:GREGORIAN",
+		"METHOD:" + method,
+		"BEGIN:VEVENT",
+		"UID:" + event.UID,
+		fmt.Sprintf("SEQUENCE:%d", event.Sequence),
+		"DTSTAMP:" + time.Now().UTC().Format(icsTimeFormat),
+		"DTSTART:" + event.Start.UTC().Format(icsTimeFormat),
+		"DTEND:" + event.End.UTC().Format(icsTimeFormat),
+		"SUMMARY:" + escapeICSText(event.Summary),
+		"STATUS:" + status,
+	}
+	if event.Description != "" {
+		lines = append(lines, "DESCRIPTION:"+escapeICSText(event.Description))
+	}
+	if event.Location != "" {
+		lines = append(lines, "LOCATION:"+escapeICSText(event.Location))
+	}
+	if event.URL != "" {
+		lines = append(lines, "URL:"+event.URL)
+	}
+	if event.Organizer.Email != "" {
+		lines = append(lines, fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", quoteICSParam(event.Organizer.Name), event.Organizer.Email))
+	}
+	for _, attendee := range event.Attendees {
+		lines = append(lines, fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:%s",
+			quoteICSParam(attendee.Name), attendee.Email))
+	}
+	lines = append(lines, "END:VEVENT", "END:VCALENDAR")
+
+	var ics strings.Builder
+	for _, line := range lines {
+		ics.WriteString(foldICSLine(line))
+		ics.WriteString("\r\n")
+	}
+	return []byte(ics.String())
+}
+
+// quoteICSParam quotes a parameter value (names may contain commas or colons)
+func quoteICSParam(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, "'") + `"`
+}
+
+// InterviewCalendarEvent builds the calendar event for an interview. The interview's internal notes
+// are left out: the same invitation goes to the candidate.
+func InterviewCalendarEvent(interview models.Interview, jobTitle string, organizer CalendarAttendee, attendees []CalendarAttendee, method string) CalendarEvent {
+	summary := interview.RoundName
+	if jobTitle != "" {
+		summary = fmt.Sprintf("%s - %s", interview.RoundName, jobTitle)
+	}
+	description := fmt.Sprintf("Interview: %s", summary)
+	if interview.VideoLink != "" {
+		description += "\nJoin: " + interview.VideoLink
+	}
+	location := interview.Location
+	if location == "" && interview.VideoLink != "" {
+		location = interview.VideoLink
+	}
+
+	return CalendarEvent{
+		UID:         interview.CalendarUID,
+		Sequence:    interview.Sequence,
+		Method:      method,
+		Summary:     summary,
+		Description: description,
+		Location:    location,
+		URL:         interview.VideoLink,
+		Start:       interview.StartTime,
+		End:         interview.EndTime,
+		Organizer:   organizer,
+		Attendees:   attendees,
+		Cancelled:   interview.Status == models.InterviewStatusCancelled,
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"ats-backend/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEscapeICSText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Tech Interview", "Tech Interview"},
+		{"Room 4; floor 2", `Room 4\; floor 2`},
+		{"Berlin, Germany", `Berlin\, Germany`},
+		{`C:\path`, `C:\\path`},
+		{"line one\nline two", `line one\nline two`},
+		{"line one\r\nline two", `line one\nline two`},
+	}
+
+	for _, tt := range tests {
+		if got := escapeICSText(tt.in); got != tt.want {
+			t.Errorf("escapeICSText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldICSLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short line", "SUMMARY:Tech Interview"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 67)},
+		{"long ASCII line", "DESCRIPTION:" + strings.Repeat("abcdefghij", 20)},
+		{"long multi-byte line", "DESCRIPTION:" + strings.Repeat("äöü€", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := foldICSLine(tt.line)
+			parts := strings.Split(folded, "\r\n")
+			for i, part := range parts {
+				if len(part) > 75 {
+					t.Errorf("part %d is %d octets long", i, len(part))
+				}
+				if i > 0 && !strings.HasPrefix(part, " ") {
+					t.Errorf("continuation line %d doesn't start with a space", i)
+				}
+			}
+			if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolding gave %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestBuildInterviewICS(t *testing.T) {
+	interview := models.Interview{
+		RoundName:   "Tech Interview",
+		StartTime:   time.Date(2026, 5, 4, 13, 0, 0, 0, time.UTC),
+		EndTime:     time.Date(2026, 5, 4, 14, 0, 0, 0, time.UTC),
+		VideoLink:   "https://meet.example.com/abc",
+		Notes:       "Internal: push on system design, salary expectations too high",
+		CalendarUID: "interview-1@ats.local",
+		Sequence:    2,
+		Status:      models.InterviewStatusScheduled,
+	}
+	organizer := CalendarAttendee{Name: "Dana Recruiter", Email: "dana@example.com"}
+	attendees := []CalendarAttendee{{Name: "Sam Candidate", Email: "sam@example.com"}}
+
+	tests := []struct {
+		name     string
+		method   string
+		status   string
+		want     []string
+		dontWant []string
+	}{
+		{
+			name:   "invitation",
+			method: CalendarMethodRequest,
+			status: models.InterviewStatusScheduled,
+			want: []string{
+				"METHOD:REQUEST",
+				"UID:interview-1@ats.local",
+				"SEQUENCE:2",
+				"DTSTART:20260504T130000Z",
+				"DTEND:20260504T140000Z",
+				"SUMMARY:Tech Interview - Backend Engineer",
+				"STATUS:CONFIRMED",
+				"LOCATION:https://meet.example.com/abc",
+				`ORGANIZER;CN="Dana Recruiter":mailto:dana@example.com`,
+				`ATTENDEE;CN="Sam Candidate";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:sam@example.com`,
+			},
+			dontWant: []string{"push on system design", "salary"},
+		},
+		{
+			name:   "cancellation",
+			method: CalendarMethodCancel,
+			status: models.InterviewStatusCancelled,
+			want:   []string{"METHOD:CANCEL", "STATUS:CANCELLED", "UID:interview-1@ats.local"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := interview
+			event.Status = tt.status
+			ics := string(BuildICS(InterviewCalendarEvent(event, "Backend Engineer", organizer, attendees, tt.method)))
+			unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+
+			if !strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\n") || !strings.HasSuffix(ics, "END:VCALENDAR\r\n") {
+				t.Errorf("ics isn't a CRLF-terminated VCALENDAR:\n%s", ics)
+			}
+			for _, line := range tt.want {
+				if !strings.Contains(unfolded, line+"\r\n") {
+					t.Errorf("ics is missing %q:\n%s", line, unfolded)
+				}
+			}
+			for _, text := range tt.dontWant {
+				if strings.Contains(unfolded, text) {
+					t.Errorf("ics leaks %q:\n%s", text, unfolded)
+				}
+			}
+		})
+	}
+}
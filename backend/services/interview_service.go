@@ -0,0 +1,220 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InterviewConflict is an existing booking that overlaps a requested interview time
+type InterviewConflict struct {
+	AdminID     uuid.UUID `json:"admin_id"`
+	AdminName   string    `json:"admin_name"`
+	InterviewID uuid.UUID `json:"interview_id"`
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+}
+
+// InterviewConflictError is returned when an interview would double-book its interviewers
+type InterviewConflictError struct {
+	Conflicts []InterviewConflict
+}
+
+func (e *InterviewConflictError) Error() string {
+	return "one or more interviewers are already booked at this time"
+}
+
+// FindInterviewerConflicts returns scheduled bookings of the given interviewers that overlap [start, end).
+// excludeInterviewID skips the interview being rescheduled.
+func FindInterviewerConflicts(adminIDs []uuid.UUID, start, end time.Time, excludeInterviewID *uuid.UUID) ([]InterviewConflict, error) {
+	return findInterviewerConflicts(config.DB, adminIDs, start, end, excludeInterviewID)
+}
+
+// LockInterviewerConflicts locks the interviewers' admin rows until tx ends and returns an
+// InterviewConflictError when any of them is booked during [start, end). Bookings of the same
+// interviewers are checked and written one at a time, so two concurrent ones can't both pass.
+func LockInterviewerConflicts(tx *gorm.DB, adminIDs []uuid.UUID, start, end time.Time, excludeInterviewID *uuid.UUID) error {
+	if len(adminIDs) == 0 {
+		return nil
+	}
+	var locked []uuid.UUID
+	if err := tx.Model(&models.Admin{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id IN ?", adminIDs).
+		Order("id").
+		Pluck("id", &locked).Error; err != nil {
+		return err
+	}
+
+	conflicts, err := findInterviewerConflicts(tx, adminIDs, start, end, excludeInterviewID)
+	if err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		return &InterviewConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+func findInterviewerConflicts(db *gorm.DB, adminIDs []uuid.UUID, start, end time.Time, excludeInterviewID *uuid.UUID) ([]InterviewConflict, error) {
+	conflicts := []InterviewConflict{}
+	if len(adminIDs) == 0 {
+		return conflicts, nil
+	}
+
+	query := db.Where("admin_id IN ? AND status = ? AND start_time < ? AND end_time > ?",
+		adminIDs, models.InterviewStatusScheduled, end, start).
+		Preload("Admin")
+	if excludeInterviewID != nil {
+		query = query.Where("interview_id <> ?", *excludeInterviewID)
+	}
+
+	var slots []models.InterviewSlot
+	if err := query.Order("start_time ASC").Find(&slots).Error; err != nil {
+		return nil, err
+	}
+
+	for _, slot := range slots {
+		conflicts = append(conflicts, InterviewConflict{
+			AdminID:     slot.AdminID,
+			AdminName:   slot.Admin.Name,
+			InterviewID: slot.InterviewID,
+			StartTime:   slot.StartTime,
+			EndTime:     slot.EndTime,
+		})
+	}
+	return conflicts, nil
+}
+
+// NewInterviewCalendarUID returns a globally unique ICS UID for a new interview
+func NewInterviewCalendarUID() string {
+	domain := config.GetEnv("CALENDAR_UID_DOMAIN", "ats.local")
+	return fmt.Sprintf("interview-%s@%s", uuid.New().String(), domain)
+}
+
+// FormatInterviewTime formats an interview's time range in its own time zone, e.g. "Mon, 02 Jan 2026 15:00 - 16:00 (Europe/Berlin)"
+func FormatInterviewTime(interview models.Interview) string {
+	location, err := time.LoadLocation(interview.TimeZone)
+	if err != nil || interview.TimeZone == "" {
+		location = time.UTC
+	}
+	start := interview.StartTime.In(location)
+	end := interview.EndTime.In(location)
+	return fmt.Sprintf("%s - %s (%s)", start.Format("Mon, 02 Jan 2006 15:04"), end.Format("15:04"), location.String())
+}
+
+// CancelInterviewTx cancels an interview and frees its interviewers' slots inside tx, bumping the
+// ICS sequence. Once tx is committed the caller sends the cancellation with SendInterviewInvitations.
+func CancelInterviewTx(tx *gorm.DB, interview *models.Interview) error {
+	interview.Status = models.InterviewStatusCancelled
+	interview.Sequence++
+	interview.UpdatedAt = time.Now()
+
+	if err := tx.Model(&models.Interview{}).Where("id = ?", interview.ID).Updates(map[string]interface{}{
+		"status":     interview.Status,
+		"sequence":   interview.Sequence,
+		"updated_at": interview.UpdatedAt,
+	}).Error; err != nil {
+		return err
+	}
+	return tx.Model(&models.InterviewSlot{}).Where("interview_id = ?", interview.ID).
+		Update("status", models.InterviewStatusCancelled).Error
+}
+
+// CancelUpcomingInterviewsTx cancels an application's interviews that haven't started yet inside tx
+// and returns their IDs, for the caller to send the cancellations once tx is committed
+func CancelUpcomingInterviewsTx(tx *gorm.DB, applicationID uuid.UUID) ([]uuid.UUID, error) {
+	var interviews []models.Interview
+	if err := tx.Where("application_id = ? AND status = ? AND start_time > ?", applicationID, models.InterviewStatusScheduled, time.Now()).
+		Find(&interviews).Error; err != nil {
+		return nil, err
+	}
+	cancelled := []uuid.UUID{}
+	for i := range interviews {
+		if err := CancelInterviewTx(tx, &interviews[i]); err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, interviews[i].ID)
+	}
+	return cancelled, nil
+}
+
+// SendInterviewInvitations emails the candidate and every interviewer an .ics for the interview.
+// change is "scheduled", "rescheduled" or "cancelled"; all of them reuse the interview's UID
+// so calendar clients update the existing event instead of adding a new one.
+func SendInterviewInvitations(interviewID uuid.UUID, change string) {
+	sendInterviewEmails(interviewID, change, nil)
+}
+
+// SendInterviewRemovalNotices sends a cancellation to interviewers taken off an interview
+func SendInterviewRemovalNotices(interviewID uuid.UUID, removed []models.Admin) {
+	if len(removed) == 0 {
+		return
+	}
+	recipients := []CalendarAttendee{}
+	for _, admin := range removed {
+		recipients = append(recipients, CalendarAttendee{Name: admin.Name, Email: admin.Email})
+	}
+	sendInterviewEmails(interviewID, "cancelled", recipients)
+}
+
+// sendInterviewEmails sends the interview's .ics to the given recipients (nil = everyone on the interview)
+func sendInterviewEmails(interviewID uuid.UUID, change string, recipients []CalendarAttendee) {
+	var interview models.Interview
+	if err := config.DB.Preload("Application.Job").Preload("Slots.Admin").First(&interview, "id = ?", interviewID).Error; err != nil {
+		log.Printf("ERROR: Failed to load interview %s for invitations: %v", interviewID, err)
+		return
+	}
+
+	jobTitle := "Unknown Job (Job Deleted)"
+	if interview.Application.JobID != nil && interview.Application.Job.ID != uuid.Nil {
+		jobTitle = interview.Application.Job.Title
+	}
+
+	attendees := []CalendarAttendee{{Name: interview.Application.FullName, Email: interview.Application.Email}}
+	for _, slot := range interview.Slots {
+		attendees = append(attendees, CalendarAttendee{Name: slot.Admin.Name, Email: slot.Admin.Email})
+	}
+	if recipients == nil {
+		recipients = attendees
+	} else {
+		// A cancellation sent to removed interviewers must name them as the attendees
+		attendees = recipients
+	}
+
+	organizer := CalendarAttendee{Name: "Hiring Team", Email: config.GetEnv("CALENDAR_ORGANIZER_EMAIL", "")}
+	if interview.CreatedBy != nil {
+		var creator models.Admin
+		if err := config.DB.First(&creator, "id = ?", *interview.CreatedBy).Error; err == nil {
+			organizer = CalendarAttendee{Name: creator.Name, Email: creator.Email}
+		}
+	}
+
+	method := CalendarMethodRequest
+	if change == "cancelled" {
+		method = CalendarMethodCancel
+	}
+	ics := BuildICS(InterviewCalendarEvent(interview, jobTitle, organizer, attendees, method))
+	when := FormatInterviewTime(interview)
+	location := interview.Location
+	if interview.VideoLink != "" {
+		location = interview.VideoLink
+	}
+
+	for _, recipient := range recipients {
+		if recipient.Email == "" {
+			continue
+		}
+		if err := SendInterviewInviteEmail(recipient.Email, recipient.Name, interview.RoundName, jobTitle, when, location, change, ics); err != nil {
+			log.Printf("ERROR: Failed to send interview %s email to %s: %v", change, recipient.Email, err)
+		} else {
+			log.Printf("SUCCESS: Interview %s email sent to %s", change, recipient.Email)
+		}
+	}
+}
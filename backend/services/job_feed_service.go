@@ -0,0 +1,251 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PublicOpenJobs returns a company's jobs that are open and still before their deadline, newest first
+func PublicOpenJobs(companyID string) ([]models.Job, error) {
+	var jobs []models.Job
+	err := publicJobsQuery(companyID).Order("created_at DESC").Find(&jobs).Error
+	return jobs, err
+}
+
+// TrackingSource cleans a feed's source parameter: lowercase letters, digits, dots, dashes
+// and underscores, at most 50 characters
+func TrackingSource(source string) string {
+	source = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		case r >= 'A' && r <= 'Z':
+			return r + ('a' - 'A')
+		}
+		return -1
+	}, source)
+	if len(source) > 50 {
+		source = source[:50]
+	}
+	return source
+}
+
+// JobApplyURL returns the public application page of a job. The source parameter
+// is stored as the application's ReferralSource.
+func JobApplyURL(jobID string, source string) string {
+	applyURL := fmt.Sprintf("%s/apply/%s", config.GetEnv("FRONTEND_URL", "http://localhost:3000"), jobID)
+	if source = TrackingSource(source); source != "" {
+		applyURL += "?source=" + url.QueryEscape(source)
+	}
+	return applyURL
+}
+
+// CareersPageURL returns the public list of a company's open jobs
+func CareersPageURL(companyID string) string {
+	return fmt.Sprintf("%s/jobs/%s", config.GetEnv("FRONTEND_URL", "http://localhost:3000"), companyID)
+}
+
+// JobValidThrough is when a job stops accepting applications: the start of its deadline day,
+// as applying and the public job list close the job once its deadline is reached
+func JobValidThrough(job models.Job) time.Time {
+	deadline := job.Deadline.Time
+	return time.Date(deadline.Year(), deadline.Month(), deadline.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// employmentTypes maps normalized JobType values to schema.org employmentType and Indeed jobtype
+var employmentTypes = map[string][2]string{
+	"full_time":  {"FULL_TIME", "fulltime"},
+	"fulltime":   {"FULL_TIME", "fulltime"},
+	"permanent":  {"FULL_TIME", "fulltime"},
+	"part_time":  {"PART_TIME", "parttime"},
+	"parttime":   {"PART_TIME", "parttime"},
+	"contract":   {"CONTRACTOR", "contract"},
+	"contractor": {"CONTRACTOR", "contract"},
+	"freelance":  {"CONTRACTOR", "contract"},
+	"temporary":  {"TEMPORARY", "temporary"},
+	"temp":       {"TEMPORARY", "temporary"},
+	"internship": {"INTERN", "internship"},
+	"intern":     {"INTERN", "internship"},
+	"volunteer":  {"VOLUNTEER", "volunteer"},
+	"per_diem":   {"PER_DIEM", "perdiem"},
+}
+
+// SchemaEmploymentType maps a job's JobType to a schema.org employmentType, OTHER when unknown
+func SchemaEmploymentType(jobType string) string {
+	if types, ok := employmentTypes[NormalizeStageKey(jobType)]; ok {
+		return types[0]
+	}
+	return "OTHER"
+}
+
+// IndeedJobType maps a job's JobType to an Indeed jobtype, empty when unknown
+func IndeedJobType(jobType string) string {
+	if types, ok := employmentTypes[NormalizeStageKey(jobType)]; ok {
+		return types[1]
+	}
+	return ""
+}
+
+// SalaryInfo is a salary range parsed from a job's free-text SalaryRange
+type SalaryInfo struct {
+	Min      float64
+	Max      float64
+	Currency string // ISO 4217, empty when the text doesn't say
+	Unit     string // schema.org unitText: HOUR, DAY, WEEK, MONTH or YEAR
+}
+
+var (
+	salaryAmountPattern = regexp.MustCompile(`(\d[\d,.]*)\s*([kK])?`)
+	currencyCodePattern = regexp.MustCompile(`\b([A-Z]{3})\b`)
+	locationNotePattern = regexp.MustCompile(`\s*\([^)]*\)`) // e.g. "(Remote)" or "(hybrid)"
+	currencySymbols     = map[string]string{"$": "USD", "€": "EUR", "£": "GBP", "¥": "JPY", "₹": "INR"}
+)
+
+// ParseSalaryRange reads amounts like "$50,000 - $70,000", "50k-70k EUR" or "€25/hour".
+// Returns false when the text has no amount.
+func ParseSalaryRange(text string) (SalaryInfo, bool) {
+	info := SalaryInfo{Unit: "YEAR"}
+	amounts := []float64{}
+	for _, match := range salaryAmountPattern.FindAllStringSubmatch(text, 2) {
+		raw := strings.TrimRight(match[1], ".,")
+		// Treat "50,000" and "50.000" as thousands separators, keeping a trailing ".5" style decimal
+		if i := strings.LastIndexAny(raw, ".,"); i >= 0 && len(raw)-i-1 != 3 {
+			raw = strings.ReplaceAll(strings.ReplaceAll(raw[:i], ",", ""), ".", "") + "." + raw[i+1:]
+		} else {
+			raw = strings.ReplaceAll(strings.ReplaceAll(raw, ",", ""), ".", "")
+		}
+		amount, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			continue
+		}
+		if match[2] != "" {
+			amount *= 1000
+		}
+		amounts = append(amounts, amount)
+	}
+	if len(amounts) == 0 {
+		return info, false
+	}
+	info.Min, info.Max = amounts[0], amounts[0]
+	if len(amounts) > 1 {
+		info.Max = amounts[1]
+		if info.Max < info.Min {
+			info.Min, info.Max = info.Max, info.Min
+		}
+	}
+
+	for symbol, code := range currencySymbols {
+		if strings.Contains(text, symbol) {
+			info.Currency = code
+		}
+	}
+	if match := currencyCodePattern.FindStringSubmatch(text); match != nil {
+		info.Currency = match[1]
+	}
+
+	lower := strings.ToLower(text)
+	switch {
+	case strings.Contains(lower, "hour") || strings.Contains(lower, "/hr"):
+		info.Unit = "HOUR"
+	case strings.Contains(lower, "day"):
+		info.Unit = "DAY"
+	case strings.Contains(lower, "week"):
+		info.Unit = "WEEK"
+	case strings.Contains(lower, "month"):
+		info.Unit = "MONTH"
+	}
+	return info, true
+}
+
+// JobLocation splits a free-text location like "Berlin, Germany" into city and country
+// and reports whether the job is remote
+func JobLocation(location string) (city, country string, remote bool) {
+	remote = strings.Contains(strings.ToLower(location), "remote")
+	parts := []string{}
+	for _, part := range strings.Split(locationNotePattern.ReplaceAllString(location, ""), ",") {
+		part = strings.TrimSpace(part)
+		if part != "" && !strings.EqualFold(part, "remote") {
+			parts = append(parts, part)
+		}
+	}
+	switch {
+	case len(parts) > 1:
+		city, country = parts[0], parts[len(parts)-1]
+	case len(parts) == 1 && remote:
+		// "Remote, Germany" names where candidates may live, not a city
+		country = parts[0]
+	case len(parts) == 1:
+		city = parts[0]
+	}
+	return city, country, remote
+}
+
+// JobPostingJSONLD builds the schema.org JobPosting of a job, as read by Google for Jobs
+func JobPostingJSONLD(job models.Job, company models.Company, source string) map[string]interface{} {
+	description := job.Description
+	if job.Requirements != "" {
+		description += "\n\nRequirements:\n" + job.Requirements
+	}
+
+	organization := map[string]interface{}{
+		"@type": "Organization",
+		"name":  company.CompanyName,
+	}
+	if company.CompanyWebsite != "" {
+		organization["sameAs"] = company.CompanyWebsite
+	}
+
+	posting := map[string]interface{}{
+		"@context":           "https://schema.org/",
+		"@type":              "JobPosting",
+		"title":              job.Title,
+		"description":        description,
+		"identifier":         map[string]interface{}{"@type": "PropertyValue", "name": company.CompanyName, "value": job.ID.String()},
+		"datePosted":         job.CreatedAt.Format("2006-01-02"),
+		"validThrough":       JobValidThrough(job).Format(time.RFC3339),
+		"employmentType":     SchemaEmploymentType(job.JobType),
+		"hiringOrganization": organization,
+		"url":                JobApplyURL(job.ID.String(), source),
+		"directApply":        true,
+	}
+
+	city, country, remote := JobLocation(job.Location)
+	if remote {
+		posting["jobLocationType"] = "TELECOMMUTE"
+		if country != "" {
+			posting["applicantLocationRequirements"] = map[string]interface{}{"@type": "Country", "name": country}
+		}
+	}
+	if city != "" || country != "" {
+		address := map[string]interface{}{"@type": "PostalAddress"}
+		if city != "" {
+			address["addressLocality"] = city
+		}
+		if country != "" {
+			address["addressCountry"] = country
+		}
+		posting["jobLocation"] = map[string]interface{}{"@type": "Place", "address": address}
+	}
+
+	if salary, ok := ParseSalaryRange(job.SalaryRange); ok {
+		value := map[string]interface{}{"@type": "QuantitativeValue", "unitText": salary.Unit}
+		if salary.Min == salary.Max {
+			value["value"] = salary.Min
+		} else {
+			value["minValue"] = salary.Min
+			value["maxValue"] = salary.Max
+		}
+		baseSalary := map[string]interface{}{"@type": "MonetaryAmount", "value": value}
+		if salary.Currency != "" {
+			baseSalary["currency"] = salary.Currency
+		}
+		posting["baseSalary"] = baseSalary
+	}
+	return posting
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"ats-backend/models"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSalaryRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   SalaryInfo
+		wantOK bool
+	}{
+		{"dollar range", "$50,000 - $70,000", SalaryInfo{Min: 50000, Max: 70000, Currency: "USD", Unit: "YEAR"}, true},
+		{"thousands with k and a code", "50k-70k EUR", SalaryInfo{Min: 50000, Max: 70000, Currency: "EUR", Unit: "YEAR"}, true},
+		{"dot thousands separator", "€50.000 - €60.000 a year", SalaryInfo{Min: 50000, Max: 60000, Currency: "EUR", Unit: "YEAR"}, true},
+		{"hourly with a symbol", "€25/hour", SalaryInfo{Min: 25, Max: 25, Currency: "EUR", Unit: "HOUR"}, true},
+		{"decimal amount", "45.50 per hour", SalaryInfo{Min: 45.5, Max: 45.5, Unit: "HOUR"}, true},
+		{"thousands and decimals", "$1,250.50/week", SalaryInfo{Min: 1250.5, Max: 1250.5, Currency: "USD", Unit: "WEEK"}, true},
+		{"monthly", "£3,000 per month", SalaryInfo{Min: 3000, Max: 3000, Currency: "GBP", Unit: "MONTH"}, true},
+		{"daily", "¥500/day", SalaryInfo{Min: 500, Max: 500, Currency: "JPY", Unit: "DAY"}, true},
+		{"reversed range", "70,000 - 50,000 GBP", SalaryInfo{Min: 50000, Max: 70000, Currency: "GBP", Unit: "YEAR"}, true},
+		{"code before the amount", "USD 100k+", SalaryInfo{Min: 100000, Max: 100000, Currency: "USD", Unit: "YEAR"}, true},
+		{"no amount", "Competitive", SalaryInfo{Unit: "YEAR"}, false},
+		{"empty", "", SalaryInfo{Unit: "YEAR"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseSalaryRange(tt.text)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("ParseSalaryRange(%q) = %+v, %v, want %+v, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestJobLocation(t *testing.T) {
+	tests := []struct {
+		location    string
+		wantCity    string
+		wantCountry string
+		wantRemote  bool
+	}{
+		{"Berlin, Germany", "Berlin", "Germany", false},
+		{"San Francisco, CA, USA", "San Francisco", "USA", false},
+		{"London (Hybrid)", "London", "", false},
+		{"Berlin, Germany (Remote)", "Berlin", "Germany", true},
+		{"Remote, Germany", "", "Germany", true},
+		{"Remote", "", "", true},
+		{"", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.location, func(t *testing.T) {
+			city, country, remote := JobLocation(tt.location)
+			if city != tt.wantCity || country != tt.wantCountry || remote != tt.wantRemote {
+				t.Errorf("JobLocation(%q) = %q, %q, %v, want %q, %q, %v",
+					tt.location, city, country, remote, tt.wantCity, tt.wantCountry, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func TestTrackingSource(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{"lowercased", "LinkedIn", "linkedin"},
+		{"allowed punctuation", "google_jobs.v2-test", "google_jobs.v2-test"},
+		{"spaces and symbols dropped", "Indeed Ads!", "indeedads"},
+		{"non-ASCII dropped", "ünïcode", "ncode"},
+		{"cut to 50 characters", strings.Repeat("a", 60), strings.Repeat("a", 50)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := TrackingSource(tt.source); got != tt.want {
+				t.Errorf("TrackingSource(%q) = %q, want %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobValidThrough(t *testing.T) {
+	job := models.Job{Deadline: models.DateOnly{Time: time.Date(2026, 11, 30, 15, 30, 0, 0, time.UTC)}}
+	want := time.Date(2026, 11, 30, 0, 0, 0, 0, time.UTC)
+	if got := JobValidThrough(job); !got.Equal(want) {
+		t.Errorf("JobValidThrough() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,257 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Why the scheduler changed a job's status, recorded in the activity log
+const (
+	JobStatusReasonPublished       = "publish_at_reached"
+	JobStatusReasonDeadline        = "deadline_passed"
+	JobStatusReasonHeadcountFilled = "headcount_filled"
+)
+
+// What happens to applicants still in the process when a job closes automatically
+const (
+	JobClosedApplicantsNone   = "none"   // Nothing, recruiters follow up themselves
+	JobClosedApplicantsNotify = "notify" // Email them that the job has closed
+)
+
+// IsValidJobClosedApplicantPolicy reports whether a company policy value is known
+func IsValidJobClosedApplicantPolicy(policy string) bool {
+	return policy == JobClosedApplicantsNone || policy == JobClosedApplicantsNotify
+}
+
+// RunJobLifecycle publishes scheduled jobs whose publish_at has come and closes open jobs
+// past their deadline or with their headcount filled
+func RunJobLifecycle() error {
+	return errors.Join(PublishScheduledJobs(), CloseExpiredJobs(), CloseFilledJobs())
+}
+
+// PublishScheduledJobs opens scheduled jobs whose publish_at has come
+func PublishScheduledJobs() error {
+	var jobs []models.Job
+	if err := config.DB.Where("status = ? AND publish_at <= ?", "scheduled", time.Now()).Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		changed, err := changeJobStatus(job, "scheduled", "open", JobStatusReasonPublished)
+		if err != nil {
+			log.Printf("ERROR: Failed to publish job %s: %v", job.ID, err)
+			continue
+		}
+		if changed {
+			NotifyJobOwner(job, "job_published", "Job published",
+				fmt.Sprintf("%s is now open for applications", job.Title))
+		}
+	}
+	return nil
+}
+
+// CloseExpiredJobs closes open jobs whose deadline day has passed
+func CloseExpiredJobs() error {
+	today := time.Now().UTC().Format("2006-01-02")
+	var jobs []models.Job
+	if err := config.DB.Where("status = ? AND deadline < ?", "open", today).Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		closeJob(job, JobStatusReasonDeadline)
+	}
+	return nil
+}
+
+// CloseFilledJobs closes open jobs that have hired as many candidates as their headcount,
+// unless they are kept open when filled
+func CloseFilledJobs() error {
+	var jobs []models.Job
+	if err := filledJobsQuery().Where("status = ?", "open").Find(&jobs).Error; err != nil {
+		return err
+	}
+	for _, job := range jobs {
+		closeJob(job, JobStatusReasonHeadcountFilled)
+	}
+	return nil
+}
+
+// CloseJobIfFilled closes a job right away when its last position was just filled
+func CloseJobIfFilled(jobID uuid.UUID) {
+	var job models.Job
+	err := filledJobsQuery().Where("id = ? AND status = ?", jobID, "open").First(&job).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return
+	}
+	if err != nil {
+		log.Printf("ERROR: Failed to check whether job %s is filled: %v", jobID, err)
+		return
+	}
+	closeJob(job, JobStatusReasonHeadcountFilled)
+}
+
+// filledJobsQuery selects jobs whose headcount is reached and that close automatically when filled
+func filledJobsQuery() *gorm.DB {
+	return config.DB.Where("headcount > 0 AND hired_count >= headcount AND keep_open_when_filled = ?", false)
+}
+
+// BackfillHiredCounts sets the hired count of jobs that have hires but none counted yet,
+// e.g. those created before hires were counted
+func BackfillHiredCounts() {
+	var jobs []models.Job
+	if err := config.DB.Where("hired_count = 0").
+		Where("EXISTS (SELECT 1 FROM applications WHERE applications.job_id = jobs.id)").
+		Find(&jobs).Error; err != nil {
+		log.Printf("ERROR: Failed to load jobs to count hires of: %v", err)
+		return
+	}
+	updated := 0
+	for _, job := range jobs {
+		hired, err := CountHiredApplications(job)
+		if err != nil {
+			log.Printf("ERROR: Failed to count hires of job %s: %v", job.ID, err)
+			continue
+		}
+		if hired == 0 {
+			continue
+		}
+		if err := config.DB.Model(&models.Job{}).Where("id = ?", job.ID).Update("hired_count", hired).Error; err != nil {
+			log.Printf("ERROR: Failed to save hired count of job %s: %v", job.ID, err)
+			continue
+		}
+		updated++
+	}
+	if updated > 0 {
+		log.Printf("SUCCESS: Counted hires of %d job(s)", updated)
+	}
+}
+
+// CountHiredApplications counts a job's applications sitting in a hired stage of its pipeline
+func CountHiredApplications(job models.Job) (int64, error) {
+	stages, err := GetPipelineStages(job.CompanyID, &job.ID)
+	if err != nil {
+		return 0, err
+	}
+	hiredKeys := []string{}
+	for _, stage := range stages {
+		if stage.Type == models.StageTypeHired {
+			hiredKeys = append(hiredKeys, stage.Key)
+		}
+	}
+	if len(hiredKeys) == 0 {
+		return 0, nil
+	}
+
+	var count int64
+	err = config.DB.Model(&models.Application{}).
+		Where("job_id = ? AND status IN ?", job.ID, hiredKeys).
+		Count(&count).Error
+	return count, err
+}
+
+// changeJobStatus moves a job from one status to another, unless someone changed it in the meantime.
+// Returns true when the job was changed.
+func changeJobStatus(job models.Job, from, to, reason string) (bool, error) {
+	result := config.DB.Model(&models.Job{}).
+		Where("id = ? AND status = ?", job.ID, from).
+		Updates(map[string]interface{}{"status": to, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+	LogJobStatusChanged(job.CompanyID, nil, job.ID, job.Title, from, to, reason)
+	return true, nil
+}
+
+// closeJob closes an open job, then tells its owner and, by company policy, its pending applicants
+func closeJob(job models.Job, reason string) {
+	changed, err := changeJobStatus(job, "open", "closed", reason)
+	if err != nil {
+		log.Printf("ERROR: Failed to close job %s: %v", job.ID, err)
+		return
+	}
+	if !changed {
+		return
+	}
+
+	message := fmt.Sprintf("%s closed automatically: its deadline has passed", job.Title)
+	if reason == JobStatusReasonHeadcountFilled {
+		message = fmt.Sprintf("%s closed automatically: all %d position(s) are filled", job.Title, job.Headcount)
+	}
+	NotifyJobOwner(job, "job_closed", "Job closed", message)
+	notifyPendingApplicants(job, reason)
+}
+
+// NotifyJobOwner sends an in-app notification to whoever created the job,
+// or to every admin of the company when the job has no known owner
+func NotifyJobOwner(job models.Job, notificationType, title, message string) {
+	recipients := []uuid.UUID{}
+	if job.CreatedBy != nil {
+		recipients = append(recipients, *job.CreatedBy)
+	} else if err := config.DB.Model(&models.Admin{}).
+		Where("company_id = ? AND role = ?", job.CompanyID, "admin").
+		Pluck("id", &recipients).Error; err != nil {
+		log.Printf("ERROR: Failed to load admins of company %s: %v", job.CompanyID, err)
+		return
+	}
+
+	for _, adminID := range recipients {
+		if err := CreateNotification(job.CompanyID, adminID, notificationType, title, message, "job", &job.ID); err != nil {
+			log.Printf("ERROR: Failed to notify admin %s about job %s: %v", adminID, job.ID, err)
+		}
+	}
+}
+
+// notifyPendingApplicants emails the applicants still in the process of a job that closed,
+// when the company's policy asks for it or, for a filled job, when the job has its own message for them
+func notifyPendingApplicants(job models.Job, reason string) {
+	var company models.Company
+	if err := config.DB.Select("id, company_name, job_closed_applicant_policy").First(&company, "id = ?", job.CompanyID).Error; err != nil {
+		log.Printf("ERROR: Failed to load company of job %s: %v", job.ID, err)
+		return
+	}
+	filled := reason == JobStatusReasonHeadcountFilled
+	message := ""
+	if filled {
+		message = strings.TrimSpace(job.FilledMessage)
+	}
+	if company.JobClosedApplicantPolicy != JobClosedApplicantsNotify && message == "" {
+		return
+	}
+
+	stages, err := GetPipelineStages(job.CompanyID, &job.ID)
+	if err != nil {
+		log.Printf("ERROR: Failed to load pipeline of job %s: %v", job.ID, err)
+		return
+	}
+	var applications []models.Application
+	if err := config.DB.Select("id, full_name, email, status").Where("job_id = ?", job.ID).Find(&applications).Error; err != nil {
+		log.Printf("ERROR: Failed to load applications of job %s: %v", job.ID, err)
+		return
+	}
+
+	sent := 0
+	for _, application := range applications {
+		if IsClosedStatus(stages, application.Status) {
+			continue
+		}
+		if err := SendJobClosedEmail(application.Email, application.FullName, job.Title, company.CompanyName,
+			filled, message); err != nil {
+			log.Printf("ERROR: Failed to email applicant %s about closed job %s: %v", application.ID, job.ID, err)
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("SUCCESS: Told %d pending applicant(s) that job %s closed", sent, job.ID)
+	}
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// JobSetup is what a job carries over to a copy besides its own fields:
+// its screening questions and scorecard template
+type JobSetup struct {
+	Questions []models.FormQuestion
+	Scorecard *models.ScorecardTemplate
+}
+
+// copyFormQuestions returns questions detached from their form, ready to be saved on another one
+func copyFormQuestions(questions []models.FormQuestion) []models.FormQuestion {
+	copies := make([]models.FormQuestion, len(questions))
+	for i, question := range questions {
+		question.ID = uuid.Nil
+		question.FormID = uuid.Nil
+		question.Position = i
+		copies[i] = question
+	}
+	return copies
+}
+
+// EncodeTemplateQuestions stores form questions on a job template as a JSON array, or empty when there are none
+func EncodeTemplateQuestions(questions []models.FormQuestion) string {
+	if len(questions) == 0 {
+		return ""
+	}
+	encoded, _ := json.Marshal(copyFormQuestions(questions))
+	return string(encoded)
+}
+
+// DecodeTemplateQuestions returns the form questions saved on a job template
+func DecodeTemplateQuestions(template models.JobTemplate) ([]models.FormQuestion, error) {
+	questions := []models.FormQuestion{}
+	if template.Questions == "" {
+		return questions, nil
+	}
+	if err := json.Unmarshal([]byte(template.Questions), &questions); err != nil {
+		return nil, err
+	}
+	return copyFormQuestions(questions), nil
+}
+
+// SaveApplicationForm creates or replaces the application form of a job within tx
+func SaveApplicationForm(tx *gorm.DB, job models.Job, questions []models.FormQuestion) (models.ApplicationForm, error) {
+	var form models.ApplicationForm
+	err := tx.Where("job_id = ?", job.ID).First(&form).Error
+	if err != nil && err != gorm.ErrRecordNotFound {
+		return form, err
+	}
+	form.CompanyID = job.CompanyID
+	form.JobID = job.ID
+	if err := tx.Save(&form).Error; err != nil {
+		return form, err
+	}
+
+	if err := tx.Where("form_id = ?", form.ID).Delete(&models.FormQuestion{}).Error; err != nil {
+		return form, err
+	}
+	if len(questions) == 0 {
+		return form, nil
+	}
+	for i := range questions {
+		questions[i].FormID = form.ID
+	}
+	if err := tx.Create(&questions).Error; err != nil {
+		return form, err
+	}
+	form.Questions = questions
+	return form, nil
+}
+
+// LoadJobSetup returns the screening questions and scorecard template of a job
+func LoadJobSetup(jobID uuid.UUID) (JobSetup, error) {
+	setup := JobSetup{Questions: []models.FormQuestion{}}
+
+	form, err := GetJobApplicationForm(jobID)
+	if err != nil {
+		return setup, err
+	}
+	if form != nil {
+		setup.Questions = copyFormQuestions(form.Questions)
+	}
+
+	var scorecard models.ScorecardTemplate
+	err = config.DB.Where("job_id = ?", jobID).
+		Preload("Competencies", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC")
+		}).
+		First(&scorecard).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return setup, err
+	}
+	if err == nil {
+		setup.Scorecard = &scorecard
+	}
+	return setup, nil
+}
+
+// CreateJobWithSetup creates a job with a slug, its creator as recruiter on its hiring team, then its
+// application form and scorecard template, all or nothing
+func CreateJobWithSetup(job *models.Job, setup JobSetup) error {
+	if err := AssignJobSlug(job); err != nil {
+		return err
+	}
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(job).Error; err != nil {
+			return err
+		}
+		// Whoever creates a job recruits for it
+		if job.CreatedBy != nil {
+			if err := tx.Create(&models.JobMember{
+				CompanyID: job.CompanyID,
+				JobID:     job.ID,
+				AdminID:   *job.CreatedBy,
+				Role:      models.JobMemberRecruiter,
+				AddedBy:   job.CreatedBy,
+			}).Error; err != nil {
+				return err
+			}
+		}
+		if len(setup.Questions) > 0 {
+			if _, err := SaveApplicationForm(tx, *job, copyFormQuestions(setup.Questions)); err != nil {
+				return err
+			}
+		}
+		if setup.Scorecard == nil {
+			return nil
+		}
+
+		scorecard := models.ScorecardTemplate{
+			CompanyID:       job.CompanyID,
+			JobID:           job.ID,
+			Name:            setup.Scorecard.Name,
+			RatingMin:       setup.Scorecard.RatingMin,
+			RatingMax:       setup.Scorecard.RatingMax,
+			RequireComments: setup.Scorecard.RequireComments,
+		}
+		if err := tx.Create(&scorecard).Error; err != nil {
+			return err
+		}
+		if len(setup.Scorecard.Competencies) == 0 {
+			return nil
+		}
+		competencies := make([]models.ScorecardCompetency, len(setup.Scorecard.Competencies))
+		for i, competency := range setup.Scorecard.Competencies {
+			competencies[i] = models.ScorecardCompetency{
+				TemplateID:      scorecard.ID,
+				Name:            competency.Name,
+				Description:     competency.Description,
+				CommentRequired: competency.CommentRequired,
+				Position:        i,
+			}
+		}
+		return tx.Create(&competencies).Error
+	})
+}
+
+// MarkJobTemplateUsed counts a job created from a template
+func MarkJobTemplateUsed(templateID uuid.UUID) error {
+	return config.DB.Model(&models.JobTemplate{}).
+		Where("id = ?", templateID).
+		Updates(map[string]interface{}{
+			"usage_count":  gorm.Expr("usage_count + 1"),
+			"last_used_at": time.Now(),
+		}).Error
+}
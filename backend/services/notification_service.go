@@ -0,0 +1,30 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CreateNotification stores an in-app notification for an admin
+func CreateNotification(companyID, adminID uuid.UUID, notificationType, title, message, entityType string, entityID *uuid.UUID) error {
+	notification := models.Notification{
+		CompanyID:  companyID,
+		AdminID:    adminID,
+		Type:       notificationType,
+		Title:      title,
+		Message:    message,
+		EntityType: entityType,
+		EntityID:   entityID,
+		CreatedAt:  time.Now(),
+	}
+
+	if err := config.DB.Create(&notification).Error; err != nil {
+		log.Printf("ERROR: Failed to create notification for admin %s: %v", adminID.String(), err)
+		return err
+	}
+	return nil
+}
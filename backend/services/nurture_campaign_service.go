@@ -10,8 +10,13 @@ import (
 	"github.com/google/uuid"
 )
 
-// SendJobAlert sends a job alert email to a candidate in the talent pool
+// SendJobAlert sends a job alert email to a candidate in the talent pool.
+// Candidates who haven't opted in to job alerts get ErrNoMarketingConsent instead.
 func SendJobAlert(applicationID, jobID string, candidateEmail, candidateName, jobTitle string) error {
+	if err := checkMarketingConsent(applicationID); err != nil {
+		return err
+	}
+
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:3000"
@@ -73,7 +78,12 @@ func SendJobAlert(applicationID, jobID string, candidateEmail, candidateName, jo
 }
 
 // SendMonthlyCheckIn sends a monthly check-in email to candidates in talent pool
+// who opted in to marketing emails
 func SendMonthlyCheckIn(applicationID, candidateEmail, candidateName string) error {
+	if err := checkMarketingConsent(applicationID); err != nil {
+		return err
+	}
+
 	frontendURL := os.Getenv("FRONTEND_URL")
 	if frontendURL == "" {
 		frontendURL = "http://localhost:3000"
@@ -131,11 +141,13 @@ func SendMonthlyCheckIn(applicationID, candidateEmail, candidateName string) err
 // ProcessMonthlyNurtureCampaigns processes all talent pool candidates and sends monthly check-ins
 // This should be run as a scheduled job (cron)
 func ProcessMonthlyNurtureCampaigns() error {
-	// Get all candidates in talent pool who haven't been contacted in the last 30 days
+	// Get all candidates in talent pool who haven't been contacted in the last 30 days.
+	// Only candidates who agreed to both the talent pool and marketing emails are contacted.
 	thirtyDaysAgo := time.Now().AddDate(0, 0, -30)
 
 	var applications []models.Application
 	err := config.DB.Where("in_talent_pool = true").
+		Where("talent_pool_consent = true AND marketing_consent = true").
 		Where("talent_pool_added_at < ? OR talent_pool_added_at IS NULL", thirtyDaysAgo).
 		Find(&applications).Error
 
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DefaultOfferTermsTemplate is used when an offer is created without its own terms
+const DefaultOfferTermsTemplate = `Dear {{candidate_name}},
+
+We are delighted to offer you the position of {{job_title}} at {{company_name}}.
+
+Your annual base salary will be {{salary}} {{currency}}, with a bonus of {{bonus}} {{currency}}. Your start date will be {{start_date}}.
+
+This offer is valid until {{expires_at}}.
+
+We look forward to welcoming you to the team.`
+
+// ErrOfferNotOpen is returned when a candidate responds to an offer that isn't waiting for a response
+var ErrOfferNotOpen = errors.New("this offer is no longer open")
+
+// OpenOfferStatuses are the statuses of offers that are still in progress
+var OpenOfferStatuses = []string{
+	models.OfferStatusDraft,
+	models.OfferStatusPendingApproval,
+	models.OfferStatusApproved,
+	models.OfferStatusRejected,
+	models.OfferStatusSent,
+}
+
+// OfferTermsData holds the values that can be used in an offer's terms template
+type OfferTermsData struct {
+	CandidateName string
+	JobTitle      string
+	CompanyName   string
+	Salary        float64
+	Currency      string
+	Bonus         float64
+	StartDate     *time.Time
+	ExpiresAt     time.Time
+}
+
+// RenderOfferTerms fills the {{placeholders}} of a terms template. Unknown placeholders are left as-is.
+func RenderOfferTerms(template string, data OfferTermsData) string {
+	startDate := "to be agreed"
+	if data.StartDate != nil {
+		startDate = data.StartDate.Format("January 2, 2006")
+	}
+	replacer := strings.NewReplacer(
+		"{{candidate_name}}", data.CandidateName,
+		"{{job_title}}", data.JobTitle,
+		"{{company_name}}", data.CompanyName,
+		"{{salary}}", formatOfferAmount(data.Salary),
+		"{{currency}}", data.Currency,
+		"{{bonus}}", formatOfferAmount(data.Bonus),
+		"{{start_date}}", startDate,
+		"{{expires_at}}", data.ExpiresAt.UTC().Format("January 2, 2006 15:04 UTC"),
+	)
+	return replacer.Replace(template)
+}
+
+// formatOfferAmount formats an amount with thousands separators, e.g. 65000 -> 65,000.00
+func formatOfferAmount(amount float64) string {
+	formatted := fmt.Sprintf("%.2f", amount)
+	whole, decimals := formatted[:len(formatted)-3], formatted[len(formatted)-3:]
+	negative := strings.HasPrefix(whole, "-")
+	whole = strings.TrimPrefix(whole, "-")
+	var grouped strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			grouped.WriteByte(',')
+		}
+		grouped.WriteRune(digit)
+	}
+	if negative {
+		return "-" + grouped.String() + decimals
+	}
+	return grouped.String() + decimals
+}
+
+// OfferURL returns the candidate-facing URL of an offer
+func OfferURL(token string) string {
+	return fmt.Sprintf("%s/offer/%s", config.GetEnv("FRONTEND_URL", "http://localhost:3000"), token)
+}
+
+// IsOfferOpen reports whether the candidate can still accept or decline the offer
+func IsOfferOpen(offer models.Offer) bool {
+	return offer.Status == models.OfferStatusSent && time.Now().Before(offer.ExpiresAt)
+}
+
+// NextOfferApproval returns the first approval step still waiting for a decision
+func NextOfferApproval(offer models.Offer) (*models.OfferApproval, bool) {
+	var next *models.OfferApproval
+	for i := range offer.Approvals {
+		approval := &offer.Approvals[i]
+		if approval.Status != models.OfferApprovalPending {
+			continue
+		}
+		if next == nil || approval.Step < next.Step {
+			next = approval
+		}
+	}
+	return next, next != nil
+}
+
+// OfferHiringTeam returns the admins involved in an offer's application:
+// the offer's creator, its approvers and the application's interviewers
+func OfferHiringTeam(offer models.Offer) []uuid.UUID {
+	team := []uuid.UUID{offer.CreatedBy}
+	for _, approval := range offer.Approvals {
+		team = append(team, approval.ApproverID)
+	}
+	var interviewerIDs []uuid.UUID
+	config.DB.Model(&models.InterviewSlot{}).
+		Joins("JOIN interviews ON interviews.id = interview_slots.interview_id").
+		Where("interviews.application_id = ?", offer.ApplicationID).
+		Distinct().
+		Pluck("interview_slots.admin_id", &interviewerIDs)
+	team = append(team, interviewerIDs...)
+
+	unique := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range team {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// NotifyOfferTeam sends an in-app notification about an offer to its hiring team
+func NotifyOfferTeam(offer models.Offer, notificationType, title, message string) {
+	for _, adminID := range OfferHiringTeam(offer) {
+		if err := CreateNotification(offer.CompanyID, adminID, notificationType, title, message, "offer", &offer.ID); err != nil {
+			log.Printf("ERROR: Failed to notify admin %s about offer %s: %v", adminID, offer.ID, err)
+		}
+	}
+}
+
+// RespondToOffer records the candidate's answer to a sent offer.
+// Accepting moves the application to the first hired stage of its pipeline in the same transaction;
+// an offer for an application that has left the process can't be accepted.
+func RespondToOffer(offer *models.Offer, accept bool, reason string) error {
+	if !IsOfferOpen(*offer) {
+		return ErrOfferNotOpen
+	}
+
+	status := models.OfferStatusDeclined
+	if accept {
+		status = models.OfferStatusAccepted
+	}
+	now := time.Now()
+
+	var application models.Application
+	var hiredStage *models.PipelineStage
+	if accept {
+		if err := config.DB.First(&application, "id = ?", offer.ApplicationID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrApplicationClosed
+			}
+			return err
+		}
+		stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+		if err != nil {
+			return err
+		}
+		if IsClosedStatus(stages, application.Status) {
+			return ErrApplicationClosed
+		}
+		if hired, found := FirstStageOfType(stages, models.StageTypeHired); found {
+			hiredStage = hired
+		} else {
+			log.Printf("ERROR: Pipeline of application %s has no hired stage, offer %s accepted without a stage change", application.ID, offer.ID)
+		}
+	}
+
+	hiredCount := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// Only one response can win, even if the candidate double-clicks
+		result := tx.Model(&models.Offer{}).
+			Where("id = ? AND status = ?", offer.ID, models.OfferStatusSent).
+			Updates(map[string]interface{}{
+				"status":         status,
+				"responded_at":   now,
+				"decline_reason": reason,
+				"updated_at":     now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrOfferNotOpen
+		}
+		if hiredStage == nil {
+			return nil
+		}
+
+		fromStage := application.Status
+		application.Status = hiredStage.Key
+		application.LastStatusUpdate = &now
+		var err error
+		hiredCount, err = saveApplicationStatusTx(tx, &application, fromStage, StatusChangeActor{Type: models.StatusActorCandidate}, "Offer accepted", false)
+		var transitionErr *StageTransitionError
+		if errors.As(err, &transitionErr) {
+			return ErrApplicationClosed
+		}
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if hiredCount > 0 {
+		go CloseJobIfFilled(*application.JobID)
+	}
+
+	offer.Status = status
+	offer.RespondedAt = &now
+	offer.DeclineReason = reason
+	return nil
+}
+
+// WithdrawOpenOffersTx withdraws an application's offers that are still in progress inside tx
+func WithdrawOpenOffersTx(tx *gorm.DB, applicationID uuid.UUID) error {
+	return tx.Model(&models.Offer{}).
+		Where("application_id = ? AND status IN ?", applicationID, OpenOfferStatuses).
+		Updates(map[string]interface{}{
+			"status":     models.OfferStatusWithdrawn,
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// ExpireOffers marks sent offers past their expiry date as expired and tells the hiring team
+func ExpireOffers() error {
+	var offers []models.Offer
+	if err := config.DB.Where("status = ? AND expires_at <= ?", models.OfferStatusSent, time.Now()).
+		Preload("Approvals").
+		Preload("Application", func(db *gorm.DB) *gorm.DB {
+			return db.Select("id, full_name")
+		}).
+		Find(&offers).Error; err != nil {
+		return err
+	}
+
+	for _, offer := range offers {
+		result := config.DB.Model(&models.Offer{}).
+			Where("id = ? AND status = ?", offer.ID, models.OfferStatusSent).
+			Updates(map[string]interface{}{"status": models.OfferStatusExpired, "updated_at": time.Now()})
+		if result.Error != nil {
+			log.Printf("ERROR: Failed to expire offer %s: %v", offer.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+		LogOfferStatusChanged(offer.CompanyID, offer.CreatedBy, offer.ID, offer.Application.FullName, models.OfferStatusExpired)
+		NotifyOfferTeam(offer, "offer_expired", "Offer expired",
+			fmt.Sprintf("The offer to %s expired without a response", offer.Application.FullName))
+	}
+	return nil
+}
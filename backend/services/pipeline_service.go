@@ -0,0 +1,242 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// stageKeyPattern is the allowed format of a stage key (stored in Application.Status)
+var stageKeyPattern = regexp.MustCompile(`^[a-z0-9_]{1,50}$`)
+
+// DefaultPipelineStages returns the built-in pipeline used when a company hasn't configured one.
+// Its keys are the statuses the application has always used, so existing data keeps working.
+func DefaultPipelineStages() []models.PipelineStage {
+	stages := []models.PipelineStage{
+		{Key: "pending", Name: "Applied", Type: models.StageTypeActive},
+		{Key: "cv_viewed", Name: "CV Viewed", Type: models.StageTypeActive},
+		{Key: "under_review", Name: "Under Review", Type: models.StageTypeActive},
+		{Key: "shortlisted", Name: "Shortlisted", Type: models.StageTypeActive},
+		{Key: "interview_scheduled", Name: "Interview Scheduled", Type: models.StageTypeActive},
+		{Key: "offer", Name: "Offer", Type: models.StageTypeActive},
+		{Key: "hired", Name: "Hired", Type: models.StageTypeHired},
+		{Key: "rejected", Name: "Not Selected", Type: models.StageTypeRejected},
+		WithdrawnStage(),
+	}
+	for i := range stages {
+		stages[i].Position = i
+	}
+	return stages
+}
+
+// WithdrawnStage is the stage candidates move to when they withdraw.
+// Every pipeline has one: it's added to custom pipelines that don't define their own.
+func WithdrawnStage() models.PipelineStage {
+	return models.PipelineStage{Key: "withdrawn", Name: "Withdrawn", Type: models.StageTypeWithdrawn}
+}
+
+// withWithdrawnStage appends the built-in withdrawn stage to a pipeline without one
+func withWithdrawnStage(stages []models.PipelineStage) []models.PipelineStage {
+	if _, found := FirstStageOfType(stages, models.StageTypeWithdrawn); found {
+		return stages
+	}
+	stage := WithdrawnStage()
+	if _, taken := FindPipelineStage(stages, stage.Key); taken {
+		stage.Key = "candidate_withdrawn"
+	}
+	stage.Position = len(stages)
+	if len(stages) > 0 && stages[len(stages)-1].Position >= stage.Position {
+		stage.Position = stages[len(stages)-1].Position + 1
+	}
+	return append(stages, stage)
+}
+
+// NormalizeStageKey turns a stage key or name into the stored key format ("Tech Interview" -> "tech_interview")
+func NormalizeStageKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(key))
+	key = strings.Join(strings.FieldsFunc(key, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+	return key
+}
+
+// ValidatePipelineStages checks a stage list before it is saved.
+// Keys must be unique slugs, and a pipeline needs at least one active and one rejected stage.
+func ValidatePipelineStages(stages []models.PipelineStage) error {
+	if len(stages) == 0 {
+		return errors.New("a pipeline needs at least one stage")
+	}
+
+	seen := map[string]bool{}
+	hasActive, hasRejected := false, false
+	for _, stage := range stages {
+		if !stageKeyPattern.MatchString(stage.Key) {
+			return fmt.Errorf("invalid stage key %q: use lowercase letters, digits and underscores", stage.Key)
+		}
+		if seen[stage.Key] {
+			return fmt.Errorf("duplicate stage key %q", stage.Key)
+		}
+		seen[stage.Key] = true
+
+		if strings.TrimSpace(stage.Name) == "" {
+			return fmt.Errorf("stage %q needs a name", stage.Key)
+		}
+
+		switch stage.Type {
+		case models.StageTypeActive:
+			hasActive = true
+		case models.StageTypeRejected:
+			hasRejected = true
+		case models.StageTypeHired, models.StageTypeWithdrawn:
+		default:
+			return fmt.Errorf("invalid type %q for stage %q. Must be: active, hired, rejected, or withdrawn", stage.Type, stage.Key)
+		}
+	}
+
+	if !hasActive {
+		return errors.New("a pipeline needs at least one active stage")
+	}
+	if !hasRejected {
+		return errors.New("a pipeline needs at least one rejected stage")
+	}
+	return nil
+}
+
+// GetPipelineStages returns the ordered stages that apply to a job:
+// the job's own pipeline, else the company default, else the built-in pipeline.
+func GetPipelineStages(companyID uuid.UUID, jobID *uuid.UUID) ([]models.PipelineStage, error) {
+	var templateID *uuid.UUID
+
+	if jobID != nil {
+		var job models.Job
+		err := config.DB.Select("id, pipeline_template_id").First(&job, "id = ?", *jobID).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		templateID = job.PipelineTemplateID
+	}
+
+	if templateID == nil {
+		var template models.PipelineTemplate
+		err := config.DB.Where("company_id = ? AND is_default = ?", companyID, true).First(&template).Error
+		if err == nil {
+			templateID = &template.ID
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+	}
+
+	if templateID == nil {
+		return DefaultPipelineStages(), nil
+	}
+
+	var stages []models.PipelineStage
+	if err := config.DB.Where("pipeline_template_id = ?", *templateID).Order("position ASC").Find(&stages).Error; err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 {
+		return DefaultPipelineStages(), nil
+	}
+	return withWithdrawnStage(stages), nil
+}
+
+// FindPipelineStage returns the stage with the given key
+func FindPipelineStage(stages []models.PipelineStage, key string) (*models.PipelineStage, bool) {
+	for i := range stages {
+		if stages[i].Key == key {
+			return &stages[i], true
+		}
+	}
+	return nil, false
+}
+
+// FirstStageOfType returns the first stage (by position) of the given type
+func FirstStageOfType(stages []models.PipelineStage, stageType string) (*models.PipelineStage, bool) {
+	sorted := make([]models.PipelineStage, len(stages))
+	copy(sorted, stages)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Position < sorted[j].Position })
+	for i := range sorted {
+		if sorted[i].Type == stageType {
+			return &sorted[i], true
+		}
+	}
+	return nil, false
+}
+
+// InitialStageKey returns the stage new applications start in for a job
+func InitialStageKey(companyID uuid.UUID, jobID *uuid.UUID) string {
+	stages, err := GetPipelineStages(companyID, jobID)
+	if err != nil {
+		return "pending"
+	}
+	if stage, ok := FirstStageOfType(stages, models.StageTypeActive); ok {
+		return stage.Key
+	}
+	return "pending"
+}
+
+// PipelineCache resolves the pipelines of many applications, loading each job's pipeline once
+type PipelineCache map[string][]models.PipelineStage
+
+// Stages returns the pipeline of a company's job, or the company's default pipeline when jobID is nil
+func (cache PipelineCache) Stages(companyID uuid.UUID, jobID *uuid.UUID) ([]models.PipelineStage, error) {
+	key := companyID.String()
+	if jobID != nil {
+		key += "/" + jobID.String()
+	}
+	if stages, ok := cache[key]; ok {
+		return stages, nil
+	}
+	stages, err := GetPipelineStages(companyID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	cache[key] = stages
+	return stages, nil
+}
+
+// IsValidStageType reports whether stageType is one of the pipeline stage types
+func IsValidStageType(stageType string) bool {
+	switch stageType {
+	case models.StageTypeActive, models.StageTypeHired, models.StageTypeRejected, models.StageTypeWithdrawn:
+		return true
+	}
+	return false
+}
+
+// CountApplicationsByStageType counts applications by the type of the stage they are in, each resolved
+// against its own job's pipeline. Applications in a stage their pipeline no longer has aren't counted.
+func CountApplicationsByStageType(db *gorm.DB) (map[string]int64, error) {
+	var rows []struct {
+		CompanyID uuid.UUID
+		JobID     *uuid.UUID
+		Status    string
+		Count     int64
+	}
+	if err := db.Model(&models.Application{}).
+		Select("company_id, job_id, status, COUNT(*) AS count").
+		Group("company_id, job_id, status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := map[string]int64{}
+	cache := PipelineCache{}
+	for _, row := range rows {
+		stages, err := cache.Stages(row.CompanyID, row.JobID)
+		if err != nil {
+			return nil, err
+		}
+		if stage, found := FindPipelineStage(stages, row.Status); found {
+			counts[stage.Type] += row.Count
+		}
+	}
+	return counts, nil
+}
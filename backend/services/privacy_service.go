@@ -0,0 +1,457 @@
+package services
+
+import (
+	"archive/zip"
+	"ats-backend/config"
+	"ats-backend/models"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AnonymisedCandidateName replaces the name of candidates whose data was erased
+const AnonymisedCandidateName = "Anonymised candidate"
+
+// retentionBatchSize caps how many applications one policy erases per run
+const retentionBatchSize = 500
+
+// IsValidRetentionAction reports whether action is a known retention policy action
+func IsValidRetentionAction(action string) bool {
+	return action == models.RetentionDelete || action == models.RetentionAnonymise
+}
+
+// CandidateApplications returns every application made to the company with an email address,
+// including those in the trash
+func CandidateApplications(companyID uuid.UUID, email string) ([]models.Application, error) {
+	var applications []models.Application
+	err := config.DB.Unscoped().
+		Where("company_id = ? AND LOWER(email) = LOWER(?)", companyID, strings.TrimSpace(email)).
+		Preload("Job", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Answers").
+		Order("applied_at ASC").
+		Find(&applications).Error
+	return applications, err
+}
+
+// candidateEntityIDs returns the applications and the interviews, offers and scorecards about them,
+// i.e. everything activity logs about a candidate point at
+func candidateEntityIDs(applicationIDs []uuid.UUID) []uuid.UUID {
+	ids := append([]uuid.UUID{}, applicationIDs...)
+	for _, model := range []interface{}{&models.Interview{}, &models.Offer{}, &models.Scorecard{}} {
+		var related []uuid.UUID
+		if err := config.DB.Model(model).Where("application_id IN ?", applicationIDs).Pluck("id", &related).Error; err != nil {
+			log.Printf("ERROR: Failed to load records of applications %v: %v", applicationIDs, err)
+			continue
+		}
+		ids = append(ids, related...)
+	}
+	return ids
+}
+
+// writeZipJSON adds v to a ZIP archive as an indented JSON file
+func writeZipJSON(archive *zip.Writer, name string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	w, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// downloadStoredFile fetches an uploaded file by its public URL
+func downloadStoredFile(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// BuildCandidateExport zips everything the company holds about a candidate: their applications with
+// parsed CVs and answers, stage history, notes, messages, interviews, offers, scorecards, nurture records,
+// emails sent, activity logs and the uploaded files
+func BuildCandidateExport(email string, applications []models.Application) ([]byte, error) {
+	applicationIDs := []uuid.UUID{}
+	for _, application := range applications {
+		applicationIDs = append(applicationIDs, application.ID)
+	}
+
+	var history []models.ApplicationStatusChange
+	var notes []models.CandidateNote
+	var messages []models.Message
+	var interviews []models.Interview
+	var offers []models.Offer
+	var scorecards []models.Scorecard
+	var campaigns []models.NurtureCampaign
+	var preferences []models.NurturePreference
+	var emails []models.EmailLog
+	var activity []models.ActivityLog
+	queries := []struct {
+		dest  interface{}
+		db    *gorm.DB
+		order string
+	}{
+		{&history, config.DB, "created_at ASC"},
+		{&notes, config.DB, "created_at ASC"},
+		{&messages, config.DB, "created_at ASC"},
+		{&interviews, config.DB.Preload("Slots"), "start_time ASC"},
+		{&offers, config.DB, "created_at ASC"},
+		{&scorecards, config.DB.Preload("Ratings"), "submitted_at ASC"},
+		{&campaigns, config.DB, "email_sent_at ASC"},
+		{&preferences, config.DB, "created_at ASC"},
+		{&emails, config.DB, "sent_at ASC"},
+	}
+	for _, q := range queries {
+		if err := q.db.Where("application_id IN ?", applicationIDs).Order(q.order).Find(q.dest).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := config.DB.Where("entity_id IN ?", candidateEntityIDs(applicationIDs)).
+		Order("created_at ASC").
+		Find(&activity).Error; err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+
+	files := []string{}
+	missingFiles := []string{}
+	for _, application := range applications {
+		for _, url := range applicationFiles(config.DB, application) {
+			name := fmt.Sprintf("files/%s/%s", application.ID, path.Base(url))
+			data, err := downloadStoredFile(url)
+			if err != nil {
+				log.Printf("ERROR: Failed to download %s for candidate export: %v", url, err)
+				missingFiles = append(missingFiles, url)
+				continue
+			}
+			w, err := archive.Create(name)
+			if err != nil {
+				return nil, err
+			}
+			if _, err := w.Write(data); err != nil {
+				return nil, err
+			}
+			files = append(files, name)
+		}
+	}
+
+	contents := []struct {
+		name string
+		v    interface{}
+	}{
+		{"export.json", map[string]interface{}{
+			"email":         email,
+			"generated_at":  time.Now(),
+			"applications":  len(applications),
+			"files":         files,
+			"missing_files": missingFiles,
+		}},
+		{"applications.json", applications},
+		{"status_history.json", history},
+		{"notes.json", notes},
+		{"messages.json", messages},
+		{"interviews.json", interviews},
+		{"offers.json", offers},
+		{"scorecards.json", scorecards},
+		{"nurture.json", map[string]interface{}{
+			"campaigns":   campaigns,
+			"preferences": preferences,
+		}},
+		{"emails.json", emails},
+		{"activity_logs.json", activity},
+	}
+	for _, content := range contents {
+		if err := writeZipJSON(archive, content.name, content.v); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// anonymisedRows are the tables holding personal data of an application that anonymising removes.
+// Each condition takes the application ID.
+var anonymisedRows = []struct {
+	Model interface{}
+	Where string
+}{
+	{&models.ScorecardRating{}, "scorecard_id IN (SELECT id FROM scorecards WHERE application_id = ?)"},
+	{&models.Scorecard{}, "application_id = ?"},
+	{&models.AvailabilityWindow{}, "scheduling_link_id IN (SELECT id FROM scheduling_links WHERE application_id = ?)"},
+	{&models.SchedulingLink{}, "application_id = ?"},
+	{&models.ApplicationAnswer{}, "application_id = ?"},
+	{&models.CandidateNote{}, "application_id = ?"},
+	{&models.Message{}, "application_id = ?"},
+	{&models.NurtureCampaign{}, "application_id = ?"},
+	{&models.NurturePreference{}, "application_id = ?"},
+	{&models.SavedSearchMatch{}, "application_id = ?"},
+	{&models.EmailLog{}, "application_id = ?"},
+	{&models.Notification{}, "entity_type = 'application' AND entity_id = ?"},
+	{&models.Notification{}, "entity_type = 'interview' AND entity_id IN (SELECT id FROM interviews WHERE application_id = ?)"},
+	{&models.Notification{}, "entity_type = 'offer' AND entity_id IN (SELECT id FROM offers WHERE application_id = ?)"},
+}
+
+// AnonymiseApplication erases everything identifying the candidate of an application and its files.
+// The application, its stage history, interviews and offers stay for reporting.
+func AnonymiseApplication(application models.Application) error {
+	files := applicationFiles(config.DB, application)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, rows := range anonymisedRows {
+			if err := tx.Where(rows.Where, application.ID).Delete(rows.Model).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Model(&models.ApplicationStatusChange{}).Where("application_id = ?", application.ID).
+			UpdateColumn("reason", "").Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Interview{}).Where("application_id = ?", application.ID).
+			UpdateColumns(map[string]interface{}{"notes": "", "video_link": ""}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Offer{}).Where("application_id = ?", application.ID).
+			UpdateColumns(map[string]interface{}{"terms": "", "terms_template": "", "decline_reason": "", "token": nil}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Application{}).Where("id = ?", application.ID).
+			UpdateColumns(map[string]interface{}{
+				"full_name":            AnonymisedCandidateName,
+				"email":                fmt.Sprintf("anonymised-%s@anonymised.invalid", application.ID),
+				"phone":                "",
+				"resume_url":           "",
+				"cover_letter":         "",
+				"current_position":     "",
+				"linkedin_url":         "",
+				"portfolio_url":        "",
+				"analysis_result":      nil,
+				"parsed_cv_text":       nil,
+				"cv_parsed_at":         nil,
+				"referred_by_name":     "",
+				"referred_by_email":    "",
+				"referred_by_phone":    "",
+				"in_talent_pool":       false,
+				"talent_pool_added_at": nil,
+				"talent_pool_added_by": nil,
+				"tags":                 "[]",
+				"consent_ip":           "",
+				"talent_pool_consent":  false,
+				"marketing_consent":    false,
+				"anonymised_at":        time.Now(),
+			}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteApplicationFiles(application.ID, files)
+	return nil
+}
+
+// scrubActivityLogs removes the details of activity logs about erased candidate records,
+// keeping the entries themselves as an audit trail
+func scrubActivityLogs(entityIDs []uuid.UUID) error {
+	return config.DB.Model(&models.ActivityLog{}).
+		Where("entity_id IN ?", entityIDs).
+		UpdateColumns(map[string]interface{}{
+			"description": "Candidate data erased",
+			"metadata":    nil,
+		}).Error
+}
+
+// EraseApplication deletes or anonymises an application, as action says, together with
+// its files and the details of the activity logs about it
+func EraseApplication(application models.Application, action string) error {
+	entityIDs := candidateEntityIDs([]uuid.UUID{application.ID})
+	// Erasing removes the matches, which tell which saved search digests name the candidate
+	var matches []models.SavedSearchMatch
+	if err := config.DB.Where("application_id = ?", application.ID).Find(&matches).Error; err != nil {
+		return err
+	}
+
+	var err error
+	if action == models.RetentionDelete {
+		err = PurgeApplication(application)
+	} else {
+		err = AnonymiseApplication(application)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := scrubActivityLogs(entityIDs); err != nil {
+		log.Printf("ERROR: Failed to scrub activity logs of application %s: %v", application.ID, err)
+	}
+	if err := forgetCandidateIdentity(application, matches); err != nil {
+		log.Printf("ERROR: Failed to erase sign-in records of application %s: %v", application.ID, err)
+	}
+	return nil
+}
+
+// forgetCandidateIdentity removes what's kept about an erased application's candidate outside its own
+// records: their entry in the saved search digests that alerted about the application (matches) and,
+// once no application with their email is left, the portal sign-in links sent to it
+func forgetCandidateIdentity(application models.Application, matches []models.SavedSearchMatch) error {
+	if application.FullName != "" && application.FullName != AnonymisedCandidateName {
+		for _, match := range matches {
+			if err := anonymiseDigestEntry(application, match); err != nil {
+				return err
+			}
+		}
+	}
+
+	email := NormalizeCandidateEmail(application.Email)
+	var remaining int64
+	if err := config.DB.Unscoped().Model(&models.Application{}).Where("LOWER(email) = ?", email).Count(&remaining).Error; err != nil {
+		return err
+	}
+	if remaining > 0 {
+		return nil
+	}
+	return config.DB.Where("email = ?", email).Delete(&models.CandidateMagicLink{}).Error
+}
+
+// digestEntryPattern matches a candidate's "Name (85%)" entry in a saved search digest
+func digestEntryPattern(name string, score int) *regexp.Regexp {
+	return regexp.MustCompile(fmt.Sprintf(`(^|, )%s \(%d%%\)`, regexp.QuoteMeta(name), score))
+}
+
+// anonymiseDigestEntry replaces the candidate's entry in the in-app digests of the saved search that
+// matched them. Digests go out after the match is recorded, and other candidates' entries stay as they are.
+func anonymiseDigestEntry(application models.Application, match models.SavedSearchMatch) error {
+	var notifications []models.Notification
+	if err := config.DB.Select("id, message").
+		Where("company_id = ? AND type = ? AND entity_type = ? AND entity_id = ? AND created_at >= ?",
+			application.CompanyID, "saved_search_match", "saved_search", match.SavedSearchID, match.CreatedAt).
+		Find(&notifications).Error; err != nil {
+		return err
+	}
+
+	pattern := digestEntryPattern(application.FullName, match.MatchScore)
+	replacement := fmt.Sprintf("${1}%s (%d%%)", AnonymisedCandidateName, match.MatchScore)
+	for _, notification := range notifications {
+		message := pattern.ReplaceAllString(notification.Message, replacement)
+		if message == notification.Message {
+			continue
+		}
+		if err := config.DB.Model(&models.Notification{}).Where("id = ?", notification.ID).
+			UpdateColumn("message", message).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EraseCandidate erases every application of the company made with an email address.
+// It returns how many were erased; applications already anonymised are skipped when anonymising.
+func EraseCandidate(companyID uuid.UUID, adminID uuid.UUID, email, action string) (int, error) {
+	applications, err := CandidateApplications(companyID, email)
+	if err != nil {
+		return 0, err
+	}
+
+	erased := 0
+	for _, application := range applications {
+		if action == models.RetentionAnonymise && application.AnonymisedAt != nil {
+			continue
+		}
+		if err := EraseApplication(application, action); err != nil {
+			return erased, err
+		}
+		erased++
+		LogApplicationErased(application.CompanyID, &adminID, application.ID, action, "erasure_request")
+	}
+
+	// Earlier exports of this candidate name them in their log entries
+	if err := config.DB.Model(&models.ActivityLog{}).
+		Where("company_id = ? AND action_type = ? AND LOWER(metadata->>'email') = LOWER(?)", companyID, "candidate_data_exported", strings.TrimSpace(email)).
+		UpdateColumns(map[string]interface{}{
+			"description": "Candidate data erased",
+			"metadata":    nil,
+		}).Error; err != nil {
+		log.Printf("ERROR: Failed to scrub export logs of an erased candidate of company %s: %v", companyID, err)
+	}
+	return erased, nil
+}
+
+// retentionCutoff returns the date before which a policy erases applications that haven't moved since
+func retentionCutoff(policy models.RetentionPolicy, now time.Time) time.Time {
+	return now.AddDate(0, -policy.Months, 0)
+}
+
+// retentionCandidates returns the query for the next batch of applications a policy erases, oldest first.
+// Applications already anonymised are left to anonymising policies but can still be deleted.
+// A policy saved without a stage erases nothing rather than every candidate.
+func retentionCandidates(db *gorm.DB, policy models.RetentionPolicy, now time.Time) *gorm.DB {
+	query := db.Unscoped().Model(&models.Application{}).
+		Where("company_id = ?", policy.CompanyID).
+		Where("status = ?", policy.Stage).
+		Where("COALESCE(last_status_update, applied_at) <= ?", retentionCutoff(policy, now))
+	if policy.Stage == "" {
+		query = query.Where("1 = 0")
+	}
+	if policy.Action == models.RetentionAnonymise {
+		query = query.Where("anonymised_at IS NULL")
+	}
+	return query.Order("applied_at ASC").Limit(retentionBatchSize)
+}
+
+// ApplyRetentionPolicies erases the applications that active retention policies no longer allow keeping
+func ApplyRetentionPolicies() error {
+	var policies []models.RetentionPolicy
+	if err := config.DB.Where("is_active = ?", true).Find(&policies).Error; err != nil {
+		return err
+	}
+
+	erased := 0
+	for _, policy := range policies {
+		var applications []models.Application
+		if err := retentionCandidates(config.DB, policy, time.Now()).Find(&applications).Error; err != nil {
+			log.Printf("ERROR: Failed to load applications for retention policy %s: %v", policy.ID, err)
+			continue
+		}
+		for _, application := range applications {
+			if err := EraseApplication(application, policy.Action); err != nil {
+				log.Printf("ERROR: Retention policy %s failed to erase application %s: %v", policy.ID, application.ID, err)
+				continue
+			}
+			erased++
+			LogApplicationErased(application.CompanyID, nil, application.ID, policy.Action, "retention_policy")
+		}
+
+		if err := config.DB.Model(&policy).UpdateColumn("last_run_at", time.Now()).Error; err != nil {
+			log.Printf("ERROR: Failed to record run of retention policy %s: %v", policy.ID, err)
+		}
+	}
+
+	if erased > 0 {
+		log.Printf("SUCCESS: Retention policies erased %d application(s)", erased)
+	}
+	return nil
+}
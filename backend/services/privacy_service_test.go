@@ -0,0 +1,129 @@
+package services
+
+import (
+	"ats-backend/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestRetentionCutoff(t *testing.T) {
+	tests := []struct {
+		name   string
+		months int
+		now    time.Time
+		want   time.Time
+	}{
+		{"six months", 6, time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC), time.Date(2026, 4, 18, 12, 0, 0, 0, time.UTC)},
+		{"across a year", 12, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"month end rolls over", 1, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := retentionCutoff(models.RetentionPolicy{Months: tt.months}, tt.now)
+			if !got.Equal(tt.want) {
+				t.Errorf("retentionCutoff(%d months, %v) = %v, want %v", tt.months, tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetentionCandidates(t *testing.T) {
+	// DryRun builds the SQL without a database connection
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("failed to open dry run database: %v", err)
+	}
+	companyID := uuid.New()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		policy         models.RetentionPolicy
+		wantAnonymised bool // Skips applications already anonymised
+		wantNothing    bool // Policies without a stage erase nothing
+	}{
+		{"anonymise one stage", models.RetentionPolicy{Months: 6, Action: models.RetentionAnonymise, Stage: "rejected"}, true, false},
+		{"delete includes anonymised", models.RetentionPolicy{Months: 24, Action: models.RetentionDelete, Stage: "withdrawn"}, false, false},
+		{"anonymise without a stage", models.RetentionPolicy{Months: 6, Action: models.RetentionAnonymise}, true, true},
+		{"delete without a stage", models.RetentionPolicy{Months: 24, Action: models.RetentionDelete}, false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.policy.CompanyID = companyID
+			var applications []models.Application
+			stmt := retentionCandidates(db, tt.policy, now).Find(&applications).Statement
+			sql := stmt.SQL.String()
+
+			if !strings.Contains(sql, "company_id = ") || stmt.Vars[0] != companyID {
+				t.Errorf("query isn't limited to the policy's company: %s %v", sql, stmt.Vars)
+			}
+			if strings.Contains(sql, "deleted_at") {
+				t.Errorf("query skips trashed applications, which must be erased too: %s", sql)
+			}
+			if got := strings.Contains(sql, "anonymised_at IS NULL"); got != tt.wantAnonymised {
+				t.Errorf("skips anonymised = %v, want %v: %s", got, tt.wantAnonymised, sql)
+			}
+			if !strings.Contains(sql, "status = ") {
+				t.Errorf("query doesn't filter on the stage: %s", sql)
+			}
+			if got := strings.Contains(sql, "1 = 0"); got != tt.wantNothing {
+				t.Errorf("selects nothing = %v, want %v: %s", got, tt.wantNothing, sql)
+			}
+			if !strings.Contains(sql, "ORDER BY applied_at ASC LIMIT") {
+				t.Errorf("query isn't the oldest batch first: %s", sql)
+			}
+
+			cutoff := retentionCutoff(tt.policy, now)
+			foundCutoff, foundStage := false, false
+			for _, v := range stmt.Vars {
+				if at, ok := v.(time.Time); ok && at.Equal(cutoff) {
+					foundCutoff = true
+				}
+				if v == tt.policy.Stage {
+					foundStage = true
+				}
+			}
+			if !foundCutoff {
+				t.Errorf("cutoff %v not in query vars %v", cutoff, stmt.Vars)
+			}
+			if !foundStage {
+				t.Errorf("stage %q not in query vars %v", tt.policy.Stage, stmt.Vars)
+			}
+		})
+	}
+}
+
+func TestDigestEntryPattern(t *testing.T) {
+	replacement := "${1}" + AnonymisedCandidateName + " (85%)"
+
+	tests := []struct {
+		name    string
+		message string
+		want    string
+	}{
+		{"only entry", "Jane Doe (85%)", AnonymisedCandidateName + " (85%)"},
+		{"later entry", "John Roe (90%), Jane Doe (85%)", "John Roe (90%), " + AnonymisedCandidateName + " (85%)"},
+		{"other score", "Jane Doe (70%)", "Jane Doe (70%)"},
+		{"name inside a longer name", "Mary Jane Doe (85%), Jane Doe Smith (85%)", "Mary Jane Doe (85%), Jane Doe Smith (85%)"},
+		{"name in the title text", "Jane Doe (85%) and Jane Doe", AnonymisedCandidateName + " (85%) and Jane Doe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := digestEntryPattern("Jane Doe", 85).ReplaceAllString(tt.message, replacement); got != tt.want {
+				t.Errorf("replaced %q = %q, want %q", tt.message, got, tt.want)
+			}
+		})
+	}
+
+	if got := digestEntryPattern("J. (Doe)", 85).ReplaceAllString("J. (Doe) (85%)", replacement); got != AnonymisedCandidateName+" (85%)" {
+		t.Errorf("name with regexp characters not matched literally: %q", got)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReapplicationError explains why a candidate can't apply to a job (again) yet
+type ReapplicationError struct {
+	Message    string
+	RetryAfter *time.Time // When the candidate may apply again, if ever
+}
+
+func (e *ReapplicationError) Error() string {
+	return e.Message
+}
+
+// CheckReapplication enforces the company's reapplication rules for an email applying to a job.
+// An email can't have two open applications for the same job; after an application closes,
+// the company's cooldown must pass before the same email applies to that job again.
+func CheckReapplication(companyID, jobID uuid.UUID, email string) error {
+	var previous []models.Application
+	if err := config.DB.Select("id, status, applied_at, last_status_update, reviewed_at").
+		Where("job_id = ? AND LOWER(email) = ?", jobID, strings.ToLower(strings.TrimSpace(email))).
+		Order("applied_at DESC").
+		Find(&previous).Error; err != nil {
+		return err
+	}
+	if len(previous) == 0 {
+		return nil
+	}
+
+	stages, err := GetPipelineStages(companyID, &jobID)
+	if err != nil {
+		return err
+	}
+
+	var company models.Company
+	if err := config.DB.Select("id, reapply_cooldown_days").First(&company, "id = ?", companyID).Error; err != nil {
+		return err
+	}
+
+	for _, application := range previous {
+		stage, known := FindPipelineStage(stages, application.Status)
+		if !known || !isTerminalStage(stage) {
+			return &ReapplicationError{Message: "You have already applied for this position. You can follow your application in the candidate portal."}
+		}
+		if stage.Type == models.StageTypeHired {
+			return &ReapplicationError{Message: "You have already been hired for this position."}
+		}
+
+		closedAt := application.AppliedAt
+		if application.LastStatusUpdate != nil {
+			closedAt = *application.LastStatusUpdate
+		} else if application.ReviewedAt != nil {
+			closedAt = *application.ReviewedAt
+		}
+		retryAfter := closedAt.AddDate(0, 0, company.ReapplyCooldownDays)
+		if company.ReapplyCooldownDays > 0 && time.Now().Before(retryAfter) {
+			return &ReapplicationError{
+				Message:    fmt.Sprintf("You recently applied for this position. You can apply again from %s.", retryAfter.Format("January 2, 2006")),
+				RetryAfter: &retryAfter,
+			}
+		}
+	}
+	return nil
+}
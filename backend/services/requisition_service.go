@@ -0,0 +1,136 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+)
+
+// ErrRequisitionNotApproved is returned when a job whose requisition isn't approved yet is published
+var ErrRequisitionNotApproved = errors.New("this job's requisition must be approved before it can be published")
+
+// RequiresJobApproval reports whether the company's new jobs must be approved before going live
+func RequiresJobApproval(companyID uuid.UUID) bool {
+	var company models.Company
+	if err := config.DB.Select("id, require_job_approval").First(&company, "id = ?", companyID).Error; err != nil {
+		log.Printf("ERROR: Failed to load job approval policy of company %s: %v", companyID, err)
+		return false
+	}
+	return company.RequireJobApproval
+}
+
+// StartRequisition turns a new job into a draft requisition when its company requires approval
+func StartRequisition(job *models.Job) {
+	if !RequiresJobApproval(job.CompanyID) {
+		return
+	}
+	job.Status = "draft"
+	job.RequisitionStatus = models.RequisitionDraft
+}
+
+// CheckJobPublishable returns ErrRequisitionNotApproved when a job can't go live yet
+func CheckJobPublishable(job models.Job) error {
+	if job.RequisitionStatus != "" && job.RequisitionStatus != models.RequisitionApproved {
+		return ErrRequisitionNotApproved
+	}
+	return nil
+}
+
+// NextJobApproval returns the first requisition approval step still waiting for a decision
+func NextJobApproval(job models.Job) (*models.JobApproval, bool) {
+	var next *models.JobApproval
+	for i := range job.Approvals {
+		approval := &job.Approvals[i]
+		if approval.Status != models.JobApprovalPending {
+			continue
+		}
+		if next == nil || approval.Step < next.Step {
+			next = approval
+		}
+	}
+	return next, next != nil
+}
+
+// LoadRequisitionApprovers returns the approvers in the given order. All must be admins of the company
+// with the approver role, and none may appear twice.
+func LoadRequisitionApprovers(companyID string, ids []string) ([]models.Admin, error) {
+	approvers := []models.Admin{}
+	if len(ids) == 0 {
+		return approvers, nil
+	}
+
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, fmt.Errorf("invalid approver id %q", id)
+		}
+		if seen[id] {
+			return nil, errors.New("an approver can only appear once in the approval chain")
+		}
+		seen[id] = true
+	}
+
+	var admins []models.Admin
+	if err := config.DB.Where("id IN ? AND company_id = ?", ids, companyID).Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	byID := map[string]models.Admin{}
+	for _, admin := range admins {
+		byID[admin.ID.String()] = admin
+	}
+	for _, id := range ids {
+		admin, ok := byID[id]
+		if !ok {
+			return nil, errors.New("every approver must be an admin of your company")
+		}
+		if !admin.IsApprover {
+			return nil, fmt.Errorf("%s doesn't have the approver role", admin.Name)
+		}
+		approvers = append(approvers, admin)
+	}
+	return approvers, nil
+}
+
+// CompanyRequisitionApproverIDs returns the company's default requisition approval chain
+func CompanyRequisitionApproverIDs(company models.Company) []string {
+	ids := []string{}
+	if company.RequisitionApproverIDs != nil {
+		if err := json.Unmarshal([]byte(*company.RequisitionApproverIDs), &ids); err != nil {
+			log.Printf("ERROR: Invalid requisition approval chain of company %s: %v", company.ID, err)
+		}
+	}
+	return ids
+}
+
+// JobApprovalsFor builds the approval chain of a job requisition
+func JobApprovalsFor(jobID uuid.UUID, approvers []models.Admin) []models.JobApproval {
+	approvals := []models.JobApproval{}
+	for i, approver := range approvers {
+		approvals = append(approvals, models.JobApproval{
+			JobID:      jobID,
+			ApproverID: approver.ID,
+			Step:       i + 1,
+			Status:     models.JobApprovalPending,
+		})
+	}
+	return approvals
+}
+
+// NotifyNextRequisitionApprover asks the approver of the next pending step to review a requisition
+func NotifyNextRequisitionApprover(job models.Job) {
+	next, ok := NextJobApproval(job)
+	if !ok {
+		return
+	}
+	if err := CreateNotification(job.CompanyID, next.ApproverID, "requisition_approval_requested",
+		"Job requisition awaiting your approval",
+		fmt.Sprintf("%s (headcount %d) needs your approval before it can be published", job.Title, job.Headcount),
+		"job", &job.ID); err != nil {
+		log.Printf("ERROR: Failed to notify approver %s of job %s: %v", next.ApproverID, job.ID, err)
+	}
+}
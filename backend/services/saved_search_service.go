@@ -0,0 +1,139 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+)
+
+// SavedSearchAlertMatch is a newly matched candidate included in an alert digest
+type SavedSearchAlertMatch struct {
+	Application models.Application
+	MatchScore  int
+}
+
+// ProcessSavedSearchAlerts evaluates every alerting saved search against applications whose CVs
+// were parsed since the search last ran, and notifies the owner about candidates not alerted before.
+// This runs from the scheduler.
+func ProcessSavedSearchAlerts() error {
+	var searches []models.SavedSearch
+	if err := config.DB.Where("alerts_enabled = true").Preload("Admin").Find(&searches).Error; err != nil {
+		return err
+	}
+
+	log.Printf("Evaluating %d saved search(es) for new matches", len(searches))
+
+	for _, search := range searches {
+		if err := evaluateSavedSearch(search); err != nil {
+			log.Printf("ERROR: Failed to evaluate saved search %s (%s): %v", search.ID.String(), search.Name, err)
+		}
+	}
+	return nil
+}
+
+// evaluateSavedSearch runs a single saved search against newly parsed applications
+func evaluateSavedSearch(search models.SavedSearch) error {
+	var criteria CandidateSearchCriteria
+	if err := json.Unmarshal([]byte(search.Criteria), &criteria); err != nil {
+		return fmt.Errorf("invalid saved criteria: %w", err)
+	}
+
+	runStartedAt := time.Now()
+	since := search.CreatedAt
+	if search.LastRunAt != nil {
+		since = *search.LastRunAt
+	}
+
+	// The owner is only alerted about candidates of jobs they may see
+	scope, err := LoadJobScope(search.AdminID)
+	if err != nil {
+		return fmt.Errorf("failed to load the owner's job scope: %w", err)
+	}
+
+	// Only CVs parsed since the last run can be new matches
+	var applications []models.Application
+	err = scope.Apply(config.DB.Table("applications"), "applications.job_id").
+		Select("applications.*").
+		Joins("LEFT JOIN jobs ON jobs.id = applications.job_id").
+		Where("applications.deleted_at IS NULL AND (applications.company_id = ? OR jobs.company_id = ?)", search.CompanyID, search.CompanyID).
+		Where("applications.cv_parsed_at > ?", since).
+		Where("NOT EXISTS (SELECT 1 FROM saved_search_matches WHERE saved_search_matches.saved_search_id = ? AND "+
+			"(saved_search_matches.application_id = applications.id OR saved_search_matches.candidate_email = LOWER(applications.email)))", search.ID).
+		Order("applications.applied_at ASC").
+		Preload("Job").
+		Find(&applications).Error
+	if err != nil {
+		return err
+	}
+
+	matches := []SavedSearchAlertMatch{}
+	alerted := map[string]bool{}
+	for _, app := range applications {
+		email := NormalizeCandidateEmail(app.Email)
+		if alerted[email] || !PassesCandidateFilters(app, criteria) {
+			continue
+		}
+		cvText := ""
+		if app.ParsedCVText != nil {
+			cvText = *app.ParsedCVText
+		}
+		match, ok := MatchCandidate(app, cvText, criteria)
+		if !ok {
+			continue
+		}
+
+		// Candidates are told apart by email, so applying to another job doesn't alert again
+		record := models.SavedSearchMatch{
+			SavedSearchID:  search.ID,
+			ApplicationID:  app.ID,
+			CandidateEmail: email,
+			MatchScore:     match.MatchScore,
+			Channel:        search.AlertChannel,
+			CreatedAt:      time.Now(),
+		}
+		if err := config.DB.Create(&record).Error; err != nil {
+			log.Printf("Skipping already alerted candidate %s for saved search %s: %v", app.ID.String(), search.ID.String(), err)
+			continue
+		}
+		alerted[email] = true
+		matches = append(matches, SavedSearchAlertMatch{Application: app, MatchScore: match.MatchScore})
+	}
+
+	if len(matches) > 0 {
+		notifySavedSearchOwner(search, matches)
+	}
+
+	return config.DB.Model(&models.SavedSearch{}).
+		Where("id = ?", search.ID).
+		Update("last_run_at", runStartedAt).Error
+}
+
+// notifySavedSearchOwner sends the new-match digest on the saved search's alert channel
+func notifySavedSearchOwner(search models.SavedSearch, matches []SavedSearchAlertMatch) {
+	title := fmt.Sprintf("%d new candidate(s) match \"%s\"", len(matches), search.Name)
+
+	switch search.AlertChannel {
+	case "in_app":
+		message := ""
+		for i, match := range matches {
+			if i > 0 {
+				message += ", "
+			}
+			message += fmt.Sprintf("%s (%d%%)", match.Application.FullName, match.MatchScore)
+		}
+		CreateNotification(search.CompanyID, search.AdminID, "saved_search_match", title, message, "saved_search", &search.ID)
+	default:
+		if search.Admin.Email == "" {
+			log.Printf("WARNING: Saved search %s has no owner email, skipping alert", search.ID.String())
+			return
+		}
+		if err := SendSavedSearchDigestEmail(search.Admin.Email, search.Admin.Name, search.Name, search.ID.String(), matches); err != nil {
+			log.Printf("ERROR: Failed to send saved search digest to %s: %v", search.Admin.Email, err)
+		} else {
+			log.Printf("SUCCESS: Saved search digest sent to %s (%d new matches)", search.Admin.Email, len(matches))
+		}
+	}
+}
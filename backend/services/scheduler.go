@@ -0,0 +1,62 @@
+package services
+
+import (
+	"log"
+	"time"
+)
+
+// scheduledJob is a background task run at a fixed interval
+type scheduledJob struct {
+	Name     string
+	Interval time.Duration
+	Run      func() error
+}
+
+// scheduledJobs lists every background job started by StartScheduler
+var scheduledJobs = []scheduledJob{
+	{Name: "saved_search_alerts", Interval: time.Hour, Run: ProcessSavedSearchAlerts},
+	{Name: "offer_expiry", Interval: time.Hour, Run: ExpireOffers},
+	{Name: "export_cleanup", Interval: 6 * time.Hour, Run: PurgeExpiredExports},
+	{Name: "job_lifecycle", Interval: 15 * time.Minute, Run: RunJobLifecycle},
+	{Name: "trash_purge", Interval: 6 * time.Hour, Run: PurgeTrash},
+	{Name: "retention_policies", Interval: 24 * time.Hour, Run: ApplyRetentionPolicies},
+	{Name: "candidate_magic_link_cleanup", Interval: 6 * time.Hour, Run: PurgeCandidateMagicLinks},
+}
+
+// StartScheduler starts all background jobs, each in its own goroutine.
+// Jobs run once shortly after startup and then at their configured interval.
+func StartScheduler() {
+	for _, job := range scheduledJobs {
+		go runScheduledJob(job)
+	}
+	log.Printf("Scheduler started with %d background job(s)", len(scheduledJobs))
+}
+
+func runScheduledJob(job scheduledJob) {
+	// Give the server a moment to finish starting up
+	time.Sleep(30 * time.Second)
+
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		runJobSafely(job)
+		<-ticker.C
+	}
+}
+
+// runJobSafely runs a job and recovers from panics so one failing job can't stop the scheduler
+func runJobSafely(job scheduledJob) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("ERROR: Scheduled job %s panicked: %v", job.Name, r)
+		}
+	}()
+
+	started := time.Now()
+	if err := job.Run(); err != nil {
+		log.Printf("ERROR: Scheduled job %s failed: %v", job.Name, err)
+		return
+	}
+	log.Printf("Scheduled job %s completed in %s", job.Name, time.Since(started).Round(time.Millisecond))
+}
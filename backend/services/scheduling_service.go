@@ -0,0 +1,210 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// TimeSlot is a bookable interview time
+type TimeSlot struct {
+	StartTime time.Time `json:"start_time"`
+	EndTime   time.Time `json:"end_time"`
+}
+
+// schedulingMinNotice is how far ahead of now a slot must start to be offered
+const schedulingMinNotice = 2 * time.Hour
+
+// ErrSlotUnavailable is returned when a candidate tries to book a slot that isn't free (anymore)
+var ErrSlotUnavailable = errors.New("this time slot is no longer available")
+
+// ErrSchedulingLinkClosed is returned when a link was already used, cancelled or has expired
+var ErrSchedulingLinkClosed = errors.New("this scheduling link is no longer active")
+
+// SchedulingLinkInterviewerIDs decodes the interviewer IDs stored on a scheduling link
+func SchedulingLinkInterviewerIDs(link models.SchedulingLink) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	if err := json.Unmarshal([]byte(link.InterviewerIDs), &ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// SchedulingLinkURL returns the candidate-facing URL for a scheduling link
+func SchedulingLinkURL(token string) string {
+	return fmt.Sprintf("%s/schedule/%s", config.GetEnv("FRONTEND_URL", "http://localhost:3000"), token)
+}
+
+// IsSchedulingLinkActive reports whether a candidate can still book through the link
+func IsSchedulingLinkActive(link models.SchedulingLink) bool {
+	return link.Status == models.SchedulingLinkOpen && time.Now().Before(link.ExpiresAt)
+}
+
+// ComputeFreeSlots lists the slots of a scheduling link that none of its interviewers are booked in,
+// keeping BufferMinutes free before and after their existing interviews
+func ComputeFreeSlots(link models.SchedulingLink, now time.Time) ([]TimeSlot, error) {
+	slots := []TimeSlot{}
+	if len(link.Windows) == 0 || link.DurationMinutes <= 0 {
+		return slots, nil
+	}
+
+	interviewerIDs, err := SchedulingLinkInterviewerIDs(link)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(link.DurationMinutes) * time.Minute
+	buffer := time.Duration(link.BufferMinutes) * time.Minute
+
+	// Load every booking that could collide with any window in one query
+	rangeStart, rangeEnd := link.Windows[0].StartTime, link.Windows[0].EndTime
+	for _, window := range link.Windows {
+		if window.StartTime.Before(rangeStart) {
+			rangeStart = window.StartTime
+		}
+		if window.EndTime.After(rangeEnd) {
+			rangeEnd = window.EndTime
+		}
+	}
+	var booked []models.InterviewSlot
+	if len(interviewerIDs) > 0 {
+		if err := config.DB.Where("admin_id IN ? AND status = ? AND start_time < ? AND end_time > ?",
+			interviewerIDs, models.InterviewStatusScheduled, rangeEnd.Add(buffer), rangeStart.Add(-buffer)).
+			Find(&booked).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	earliest := now.Add(schedulingMinNotice)
+	for _, window := range link.Windows {
+		for start := window.StartTime; !start.Add(duration).After(window.EndTime); start = start.Add(duration) {
+			end := start.Add(duration)
+			if start.Before(earliest) {
+				continue
+			}
+			free := true
+			for _, booking := range booked {
+				if booking.StartTime.Add(-buffer).Before(end) && booking.EndTime.Add(buffer).After(start) {
+					free = false
+					break
+				}
+			}
+			if free {
+				slots = append(slots, TimeSlot{StartTime: start.UTC(), EndTime: end.UTC()})
+			}
+		}
+	}
+	return slots, nil
+}
+
+// BookSchedulingLink books the slot starting at start for the link's candidate.
+// The interview and the link update are written in one transaction; the slot is re-checked inside it.
+func BookSchedulingLink(link *models.SchedulingLink, start time.Time) (*models.Interview, error) {
+	if !IsSchedulingLinkActive(*link) {
+		return nil, ErrSchedulingLinkClosed
+	}
+
+	freeSlots, err := ComputeFreeSlots(*link, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	var chosen *TimeSlot
+	for i := range freeSlots {
+		if freeSlots[i].StartTime.Equal(start) {
+			chosen = &freeSlots[i]
+			break
+		}
+	}
+	if chosen == nil {
+		return nil, ErrSlotUnavailable
+	}
+
+	interviewerIDs, err := SchedulingLinkInterviewerIDs(*link)
+	if err != nil {
+		return nil, err
+	}
+
+	var application models.Application
+	if err := config.DB.Select("id, job_id, company_id, status").First(&application, "id = ?", link.ApplicationID).Error; err != nil {
+		return nil, err
+	}
+	stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		return nil, err
+	}
+	if IsClosedStatus(stages, application.Status) {
+		return nil, ErrSchedulingLinkClosed
+	}
+
+	now := time.Now()
+	interview := models.Interview{
+		ID:            uuid.New(),
+		CompanyID:     link.CompanyID,
+		ApplicationID: link.ApplicationID,
+		JobID:         application.JobID,
+		RoundName:     link.RoundName,
+		StartTime:     chosen.StartTime,
+		EndTime:       chosen.EndTime,
+		TimeZone:      link.TimeZone,
+		Location:      link.Location,
+		VideoLink:     link.VideoLink,
+		Status:        models.InterviewStatusScheduled,
+		CalendarUID:   NewInterviewCalendarUID(),
+		CreatedBy:     &link.CreatedBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	for _, adminID := range interviewerIDs {
+		interview.Slots = append(interview.Slots, models.InterviewSlot{
+			InterviewID: interview.ID,
+			AdminID:     adminID,
+			StartTime:   interview.StartTime,
+			EndTime:     interview.EndTime,
+			Status:      models.InterviewStatusScheduled,
+			CreatedAt:   now,
+		})
+	}
+
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Claim the link first so two bookings through the same link can't both succeed
+		result := tx.Model(&models.SchedulingLink{}).
+			Where("id = ? AND status = ?", link.ID, models.SchedulingLinkOpen).
+			Updates(map[string]interface{}{
+				"status":       models.SchedulingLinkBooked,
+				"interview_id": interview.ID,
+				"updated_at":   now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSchedulingLinkClosed
+		}
+
+		// Another booking may have taken the interviewers in the meantime; keep the link's buffer around them too
+		buffer := time.Duration(link.BufferMinutes) * time.Minute
+		err := LockInterviewerConflicts(tx, interviewerIDs, interview.StartTime.Add(-buffer), interview.EndTime.Add(buffer), nil)
+		var conflictErr *InterviewConflictError
+		if errors.As(err, &conflictErr) {
+			return ErrSlotUnavailable
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&interview).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	link.Status = models.SchedulingLinkBooked
+	link.InterviewID = &interview.ID
+	return &interview, nil
+}
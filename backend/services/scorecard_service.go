@@ -0,0 +1,170 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"math"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// recommendationValues maps recommendations onto a -2..2 scale for aggregation
+var recommendationValues = map[string]int{
+	models.RecommendationStrongNo:  -2,
+	models.RecommendationNo:        -1,
+	models.RecommendationYes:       1,
+	models.RecommendationStrongYes: 2,
+}
+
+// IsValidRecommendation reports whether a scorecard recommendation is known
+func IsValidRecommendation(recommendation string) bool {
+	_, ok := recommendationValues[recommendation]
+	return ok
+}
+
+// CompetencyAggregate summarises the ratings of one competency across scorecards
+type CompetencyAggregate struct {
+	Name    string  `json:"name"`
+	Count   int     `json:"count"`
+	Average float64 `json:"average"`
+	Min     int     `json:"min"`
+	Max     int     `json:"max"`
+	StdDev  float64 `json:"std_dev"` // Spread between interviewers
+}
+
+// ScorecardAggregate is the combined view of all scorecards of an application
+type ScorecardAggregate struct {
+	ScorecardCount  int                   `json:"scorecard_count"`
+	OverallAverage  float64               `json:"overall_average"` // Average of all ratings
+	Competencies    []CompetencyAggregate `json:"competencies"`
+	Recommendations map[string]int        `json:"recommendations"`      // Count per recommendation
+	Recommendation  string                `json:"recommendation"`       // hire, no_hire, mixed or none
+	Score           float64               `json:"recommendation_score"` // Average on a -2 (strong no) to 2 (strong yes) scale
+}
+
+// ApplicationScorecards is what an admin gets to see of an application's scorecards
+type ApplicationScorecards struct {
+	Hidden            bool                `json:"hidden"` // True until the viewer submits their own pending scorecards
+	PendingInterviews []uuid.UUID         `json:"pending_interviews,omitempty"`
+	SubmittedCount    int                 `json:"submitted_count"`
+	Aggregate         *ScorecardAggregate `json:"aggregate,omitempty"`
+	Scorecards        []models.Scorecard  `json:"scorecards,omitempty"`
+}
+
+// round2 rounds to two decimals for display
+func round2(value float64) float64 {
+	return math.Round(value*100) / 100
+}
+
+// BuildScorecardAggregate computes averages, spread and an overall recommendation
+func BuildScorecardAggregate(scorecards []models.Scorecard) ScorecardAggregate {
+	aggregate := ScorecardAggregate{
+		ScorecardCount:  len(scorecards),
+		Competencies:    []CompetencyAggregate{},
+		Recommendations: map[string]int{},
+		Recommendation:  "none",
+	}
+	if len(scorecards) == 0 {
+		return aggregate
+	}
+
+	ratingsByCompetency := map[string][]int{}
+	order := []string{}
+	totalRatings, ratingSum := 0, 0
+	recommendationSum := 0
+	for _, scorecard := range scorecards {
+		aggregate.Recommendations[scorecard.Recommendation]++
+		recommendationSum += recommendationValues[scorecard.Recommendation]
+		for _, rating := range scorecard.Ratings {
+			if _, seen := ratingsByCompetency[rating.CompetencyName]; !seen {
+				order = append(order, rating.CompetencyName)
+			}
+			ratingsByCompetency[rating.CompetencyName] = append(ratingsByCompetency[rating.CompetencyName], rating.Rating)
+			ratingSum += rating.Rating
+			totalRatings++
+		}
+	}
+
+	for _, name := range order {
+		ratings := ratingsByCompetency[name]
+		sort.Ints(ratings)
+		sum := 0
+		for _, rating := range ratings {
+			sum += rating
+		}
+		mean := float64(sum) / float64(len(ratings))
+		variance := 0.0
+		for _, rating := range ratings {
+			variance += (float64(rating) - mean) * (float64(rating) - mean)
+		}
+		aggregate.Competencies = append(aggregate.Competencies, CompetencyAggregate{
+			Name:    name,
+			Count:   len(ratings),
+			Average: round2(mean),
+			Min:     ratings[0],
+			Max:     ratings[len(ratings)-1],
+			StdDev:  round2(math.Sqrt(variance / float64(len(ratings)))),
+		})
+	}
+
+	if totalRatings > 0 {
+		aggregate.OverallAverage = round2(float64(ratingSum) / float64(totalRatings))
+	}
+
+	aggregate.Score = round2(float64(recommendationSum) / float64(len(scorecards)))
+	switch {
+	case aggregate.Score >= 0.5:
+		aggregate.Recommendation = "hire"
+	case aggregate.Score <= -0.5:
+		aggregate.Recommendation = "no_hire"
+	default:
+		aggregate.Recommendation = "mixed"
+	}
+	return aggregate
+}
+
+// PendingScorecardInterviews returns the interviews of an application the admin interviews on
+// (or did) but hasn't submitted a scorecard for yet
+func PendingScorecardInterviews(applicationID, adminID uuid.UUID) ([]uuid.UUID, error) {
+	pending := []uuid.UUID{}
+	err := config.DB.Model(&models.InterviewSlot{}).
+		Joins("JOIN interviews ON interviews.id = interview_slots.interview_id").
+		Where("interviews.application_id = ? AND interview_slots.admin_id = ? AND interviews.status IN ?",
+			applicationID, adminID, []string{models.InterviewStatusScheduled, models.InterviewStatusCompleted}).
+		Where("NOT EXISTS (SELECT 1 FROM scorecards WHERE scorecards.interview_id = interviews.id AND scorecards.admin_id = ?)", adminID).
+		Pluck("interviews.id", &pending).Error
+	return pending, err
+}
+
+// GetApplicationScorecards returns the scorecards of an application as the viewer may see them.
+// Interviewers with a scorecard still to submit only see how many were submitted, to avoid anchoring.
+func GetApplicationScorecards(applicationID, viewerID uuid.UUID) (*ApplicationScorecards, error) {
+	var scorecards []models.Scorecard
+	if err := config.DB.Where("application_id = ?", applicationID).
+		Preload("Ratings").
+		Preload("Admin").
+		Order("submitted_at ASC").
+		Find(&scorecards).Error; err != nil {
+		return nil, err
+	}
+
+	pending, err := PendingScorecardInterviews(applicationID, viewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ApplicationScorecards{
+		SubmittedCount:    len(scorecards),
+		PendingInterviews: pending,
+	}
+	if len(pending) > 0 {
+		result.Hidden = true
+		return result, nil
+	}
+
+	aggregate := BuildScorecardAggregate(scorecards)
+	result.Aggregate = &aggregate
+	result.Scorecards = scorecards
+	return result, nil
+}
@@ -9,11 +9,23 @@ import (
 	"mime/multipart"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 )
 
 // UploadFileToSupabase uploads a file to Supabase Storage and returns the public URL
 func UploadFileToSupabase(file multipart.File, filename string, bucketName string) (string, error) {
+	// Read file content
+	fileBytes, err := io.ReadAll(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read file: %w", err)
+	}
+	return UploadBytesToSupabase(fileBytes, filename, bucketName)
+}
+
+// UploadBytesToSupabase uploads file content that is already in memory (e.g. extracted from a ZIP)
+// to Supabase Storage and returns the public URL
+func UploadBytesToSupabase(fileBytes []byte, filename string, bucketName string) (string, error) {
 	supabaseURL := os.Getenv("SUPABASE_URL")
 	supabaseKey := os.Getenv("SUPABASE_ANON_KEY")
 	
@@ -21,12 +33,6 @@ func UploadFileToSupabase(file multipart.File, filename string, bucketName strin
 		return "", fmt.Errorf("SUPABASE_URL and SUPABASE_ANON_KEY must be set")
 	}
 
-	// Read file content
-	fileBytes, err := io.ReadAll(file)
-	if err != nil {
-		return "", fmt.Errorf("failed to read file: %w", err)
-	}
-
 	// Generate unique filename with timestamp
 	name := fmt.Sprintf("%d_%s", time.Now().Unix(), filename)
 	
@@ -139,3 +145,38 @@ func CreateBucketIfNotExists(bucketName string, isPublic bool) error {
 	return nil
 }
 
+
+// DeleteFromSupabase permanently deletes a file stored in Supabase Storage, given its public URL.
+// Files stored elsewhere are left alone.
+func DeleteFromSupabase(publicURL string) error {
+	supabaseURL := os.Getenv("SUPABASE_URL")
+	prefix := fmt.Sprintf("%s/storage/v1/object/public/", supabaseURL)
+	if supabaseURL == "" || !strings.HasPrefix(publicURL, prefix) {
+		return nil
+	}
+	supabaseKey := os.Getenv("SUPABASE_SERVICE_ROLE_KEY")
+	if supabaseKey == "" {
+		supabaseKey = os.Getenv("SUPABASE_ANON_KEY")
+	}
+
+	// The object path is the bucket followed by the file name
+	deleteURL := fmt.Sprintf("%s/storage/v1/object/%s", supabaseURL, strings.TrimPrefix(publicURL, prefix))
+	req, err := http.NewRequest("DELETE", deleteURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+supabaseKey)
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to delete file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("delete failed with status %d: %s", resp.StatusCode, string(body))
+	}
+	return nil
+}
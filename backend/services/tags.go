@@ -0,0 +1,17 @@
+package services
+
+import "strings"
+
+// MaxTagLength is the longest tag an application can have
+const MaxTagLength = 50
+
+// NormalizeTags lowercases and trims tags, dropping empty ones and duplicates
+func NormalizeTags(tags []string) []string {
+	normalized := []string{}
+	for _, tag := range tags {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" && !containsString(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
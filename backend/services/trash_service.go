@@ -0,0 +1,294 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DefaultTrashRetentionDays is how long trashed jobs and applications are kept when TRASH_RETENTION_DAYS isn't set
+const DefaultTrashRetentionDays = 30
+
+// TrashRetention returns how long trashed jobs and applications are kept before they are purged
+func TrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = DefaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// PurgeDate returns when something trashed at deletedAt is permanently deleted
+func PurgeDate(deletedAt time.Time) time.Time {
+	return deletedAt.Add(TrashRetention())
+}
+
+// TrashJob moves a job to the trash. Its applications stay where they are and show as those of a deleted job
+// until the job is restored or purged.
+func TrashJob(job *models.Job, adminID uuid.UUID) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(job).UpdateColumn("deleted_by", adminID).Error; err != nil {
+			return err
+		}
+		return tx.Delete(job).Error
+	})
+}
+
+// TrashApplication moves an application to the trash. In the same transaction a hire comes off its
+// job's hired count, and its upcoming interviews, offers in progress and scheduling links are cancelled;
+// the interview cancellations are emailed once it's committed.
+func TrashApplication(application *models.Application, adminID uuid.UUID) error {
+	stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		return err
+	}
+
+	var cancelledInterviews []uuid.UUID
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		// Locked so a concurrent stage move can't change whether it still counts as a hire
+		var current models.Application
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, status").
+			First(&current, "id = ?", application.ID).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(application).UpdateColumn("deleted_by", adminID).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(application).Error; err != nil {
+			return err
+		}
+		if isHiredStatus(stages, current.Status) {
+			if err := changeHiredCount(tx, application.JobID, -1); err != nil {
+				return err
+			}
+		}
+		var err error
+		cancelledInterviews, err = closeApplicationFollowUpsTx(tx, application.ID)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	sendInterviewCancellations(cancelledInterviews)
+	return nil
+}
+
+// RestoreJob takes a job out of the trash
+func RestoreJob(job *models.Job) error {
+	if err := config.DB.Unscoped().Model(job).Updates(map[string]interface{}{
+		"deleted_at": nil,
+		"deleted_by": nil,
+		"updated_at": time.Now(),
+	}).Error; err != nil {
+		return err
+	}
+	job.DeletedAt = gorm.DeletedAt{}
+	job.DeletedBy = nil
+	return nil
+}
+
+// RestoreApplication takes an application out of the trash. A hire counts towards its job's hired
+// count again, which may close the job now that it's filled.
+func RestoreApplication(application *models.Application) error {
+	stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		return err
+	}
+
+	hired := false
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		var current models.Application
+		if err := tx.Unscoped().Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id, status, deleted_at").
+			First(&current, "id = ?", application.ID).Error; err != nil {
+			return err
+		}
+		if !current.DeletedAt.Valid {
+			return nil
+		}
+		if err := tx.Unscoped().Model(application).Updates(map[string]interface{}{
+			"deleted_at": nil,
+			"deleted_by": nil,
+		}).Error; err != nil {
+			return err
+		}
+		hired = isHiredStatus(stages, current.Status)
+		if !hired {
+			return nil
+		}
+		return changeHiredCount(tx, application.JobID, 1)
+	})
+	if err != nil {
+		return err
+	}
+
+	application.DeletedAt = gorm.DeletedAt{}
+	application.DeletedBy = nil
+	if hired && application.JobID != nil {
+		go CloseJobIfFilled(*application.JobID)
+	}
+	return nil
+}
+
+// applicationRows are the tables holding data of one application, children before their parents.
+// Each condition takes the application ID.
+var applicationRows = []struct {
+	Model interface{}
+	Where string
+}{
+	{&models.Notification{}, "entity_type = 'interview' AND entity_id IN (SELECT id FROM interviews WHERE application_id = ?)"},
+	{&models.Notification{}, "entity_type = 'offer' AND entity_id IN (SELECT id FROM offers WHERE application_id = ?)"},
+	{&models.OfferApproval{}, "offer_id IN (SELECT id FROM offers WHERE application_id = ?)"},
+	{&models.Offer{}, "application_id = ?"},
+	{&models.ScorecardRating{}, "scorecard_id IN (SELECT id FROM scorecards WHERE application_id = ?)"},
+	{&models.Scorecard{}, "application_id = ?"},
+	{&models.AvailabilityWindow{}, "scheduling_link_id IN (SELECT id FROM scheduling_links WHERE application_id = ?)"},
+	{&models.SchedulingLink{}, "application_id = ?"},
+	{&models.InterviewSlot{}, "interview_id IN (SELECT id FROM interviews WHERE application_id = ?)"},
+	{&models.Interview{}, "application_id = ?"},
+	{&models.ApplicationAnswer{}, "application_id = ?"},
+	{&models.CandidateNote{}, "application_id = ?"},
+	{&models.Message{}, "application_id = ?"},
+	{&models.ApplicationStatusChange{}, "application_id = ?"},
+	{&models.NurtureCampaign{}, "application_id = ?"},
+	{&models.NurturePreference{}, "application_id = ?"},
+	{&models.SavedSearchMatch{}, "application_id = ?"},
+	{&models.EmailLog{}, "application_id = ?"},
+	{&models.Notification{}, "entity_type = 'application' AND entity_id = ?"},
+}
+
+// applicationFiles returns the URLs of the files uploaded with an application
+func applicationFiles(tx *gorm.DB, application models.Application) []string {
+	files := []string{}
+	for _, url := range []string{application.ResumeURL, application.PortfolioURL} {
+		if url != "" {
+			files = append(files, url)
+		}
+	}
+	var answers []models.ApplicationAnswer
+	if err := tx.Select("value").Where("application_id = ? AND type = ?", application.ID, "file").Find(&answers).Error; err != nil {
+		log.Printf("ERROR: Failed to load file answers of application %s: %v", application.ID, err)
+	}
+	for _, answer := range answers {
+		if answer.Value != "" {
+			files = append(files, answer.Value)
+		}
+	}
+	return files
+}
+
+// PurgeApplication permanently deletes an application with everything recorded about it,
+// and removes its files from storage unless another application still uses them
+func PurgeApplication(application models.Application) error {
+	files := applicationFiles(config.DB, application)
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		for _, rows := range applicationRows {
+			if err := tx.Where(rows.Where, application.ID).Delete(rows.Model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&models.Application{}, "id = ?", application.ID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	deleteApplicationFiles(application.ID, files)
+	return nil
+}
+
+// deleteApplicationFiles removes files of an application from storage unless another application still uses them
+func deleteApplicationFiles(applicationID uuid.UUID, files []string) {
+	for _, file := range files {
+		var users int64
+		config.DB.Unscoped().Model(&models.Application{}).
+			Where("resume_url = ? OR portfolio_url = ?", file, file).
+			Count(&users)
+		if users > 0 {
+			continue
+		}
+		if err := DeleteFromSupabase(file); err != nil {
+			log.Printf("ERROR: Failed to delete file %s of application %s: %v", file, applicationID, err)
+		}
+	}
+}
+
+// PurgeJob permanently deletes a job. Its applications, offers, interviews and campaigns
+// are kept without the job, like those of jobs deleted before the trash existed.
+func PurgeJob(job models.Job) error {
+	return config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Application{}).Where("job_id = ?", job.ID).UpdateColumn("job_id", nil).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&models.Offer{}, &models.Interview{}, &models.NurtureCampaign{}} {
+			if err := tx.Model(model).Where("job_id = ?", job.ID).UpdateColumn("job_id", nil).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("form_id IN (SELECT id FROM application_forms WHERE job_id = ?)", job.ID).Delete(&models.FormQuestion{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("template_id IN (SELECT id FROM scorecard_templates WHERE job_id = ?)", job.ID).Delete(&models.ScorecardCompetency{}).Error; err != nil {
+			return err
+		}
+		for _, model := range []interface{}{&models.ApplicationForm{}, &models.ScorecardTemplate{}, &models.JobApproval{}, &models.JobMember{}} {
+			if err := tx.Where("job_id = ?", job.ID).Delete(model).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Unscoped().Delete(&models.Job{}, "id = ?", job.ID).Error
+	})
+}
+
+// PurgeTrash permanently deletes the jobs and applications that have been in the trash
+// longer than the retention period
+func PurgeTrash() error {
+	cutoff := time.Now().Add(-TrashRetention())
+
+	var applications []models.Application
+	if err := config.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", cutoff).
+		Find(&applications).Error; err != nil {
+		return err
+	}
+	purgedApplications := 0
+	for _, application := range applications {
+		if err := PurgeApplication(application); err != nil {
+			log.Printf("ERROR: Failed to purge application %s: %v", application.ID, err)
+			continue
+		}
+		purgedApplications++
+		LogApplicationPurged(application.CompanyID, nil, application.ID, application.FullName, "retention_expired")
+	}
+
+	var jobs []models.Job
+	if err := config.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", cutoff).
+		Find(&jobs).Error; err != nil {
+		return err
+	}
+	purgedJobs := 0
+	for _, job := range jobs {
+		if err := PurgeJob(job); err != nil {
+			log.Printf("ERROR: Failed to purge job %s: %v", job.ID, err)
+			continue
+		}
+		purgedJobs++
+		LogJobPurged(job.CompanyID, job.ID, job.Title)
+	}
+
+	if purgedApplications > 0 || purgedJobs > 0 {
+		log.Printf("SUCCESS: Purged %d job(s) and %d application(s) from the trash", purgedJobs, purgedApplications)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"ats-backend/config"
+	"ats-backend/models"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ErrApplicationClosed is returned when a candidate tries to withdraw an application that has already left the process
+var ErrApplicationClosed = errors.New("this application is already closed")
+
+// WithdrawApplication moves an application to its pipeline's withdrawn stage on the candidate's behalf.
+// In the same transaction its upcoming interviews are cancelled, its open offers withdrawn and its
+// scheduling links closed; the interview cancellations are emailed once it's committed.
+func WithdrawApplication(application *models.Application, reason string) error {
+	stages, err := GetPipelineStages(application.CompanyID, application.JobID)
+	if err != nil {
+		return err
+	}
+	if IsClosedStatus(stages, application.Status) {
+		return ErrApplicationClosed
+	}
+	withdrawn, found := FirstStageOfType(stages, models.StageTypeWithdrawn)
+	if !found {
+		return errors.New("pipeline has no withdrawn stage")
+	}
+
+	historyReason := "Withdrawn by the candidate"
+	if reason != "" {
+		historyReason += ": " + reason
+	}
+
+	fromStage := application.Status
+	now := time.Now()
+	application.Status = withdrawn.Key
+	application.LastStatusUpdate = &now
+
+	var cancelledInterviews []uuid.UUID
+	err = config.DB.Transaction(func(tx *gorm.DB) error {
+		if _, err := saveApplicationStatusTx(tx, application, fromStage, StatusChangeActor{Type: models.StatusActorCandidate}, historyReason, false); err != nil {
+			return err
+		}
+		var err error
+		cancelledInterviews, err = closeApplicationFollowUpsTx(tx, application.ID)
+		return err
+	})
+	if err != nil {
+		application.Status = fromStage
+		var transitionErr *StageTransitionError
+		if errors.As(err, &transitionErr) {
+			return ErrApplicationClosed
+		}
+		return err
+	}
+
+	sendInterviewCancellations(cancelledInterviews)
+	return nil
+}
+
+// closeApplicationFollowUpsTx cancels what's still planned for an application that leaves the process:
+// its upcoming interviews, its offers in progress and its open scheduling links. It returns the
+// cancelled interviews, whose cancellations the caller sends with sendInterviewCancellations once tx is committed.
+func closeApplicationFollowUpsTx(tx *gorm.DB, applicationID uuid.UUID) ([]uuid.UUID, error) {
+	cancelled, err := CancelUpcomingInterviewsTx(tx, applicationID)
+	if err != nil {
+		return nil, err
+	}
+	if err := WithdrawOpenOffersTx(tx, applicationID); err != nil {
+		return nil, err
+	}
+	err = tx.Model(&models.SchedulingLink{}).
+		Where("application_id = ? AND status = ?", applicationID, models.SchedulingLinkOpen).
+		Updates(map[string]interface{}{"status": models.SchedulingLinkCancelled, "updated_at": time.Now()}).Error
+	return cancelled, err
+}
+
+// sendInterviewCancellations emails the METHOD:CANCEL invitations of cancelled interviews in the background
+func sendInterviewCancellations(interviewIDs []uuid.UUID) {
+	for _, id := range interviewIDs {
+		go SendInterviewInvitations(id, "cancelled")
+	}
+}
+
+// ApplicationRecruiters returns the admins who have worked on an application: whoever reviewed it,
+// viewed its CV, moved it through the pipeline or interviewed the candidate.
+// Falls back to every admin of the company when nobody has touched it yet.
+func ApplicationRecruiters(application models.Application) []uuid.UUID {
+	candidates := []uuid.UUID{}
+	if application.ReviewedBy != nil {
+		candidates = append(candidates, *application.ReviewedBy)
+	}
+	if application.CVViewedBy != nil {
+		candidates = append(candidates, *application.CVViewedBy)
+	}
+
+	var actorIDs []uuid.UUID
+	config.DB.Model(&models.ApplicationStatusChange{}).
+		Where("application_id = ? AND actor_id IS NOT NULL", application.ID).
+		Distinct().
+		Pluck("actor_id", &actorIDs)
+	candidates = append(candidates, actorIDs...)
+
+	var interviewerIDs []uuid.UUID
+	config.DB.Model(&models.InterviewSlot{}).
+		Joins("JOIN interviews ON interviews.id = interview_slots.interview_id").
+		Where("interviews.application_id = ?", application.ID).
+		Distinct().
+		Pluck("interview_slots.admin_id", &interviewerIDs)
+	candidates = append(candidates, interviewerIDs...)
+
+	if len(candidates) == 0 {
+		config.DB.Model(&models.Admin{}).Where("company_id = ?", application.CompanyID).Pluck("id", &candidates)
+	}
+
+	recruiters := []uuid.UUID{}
+	seen := map[uuid.UUID]bool{}
+	for _, id := range candidates {
+		if !seen[id] {
+			seen[id] = true
+			recruiters = append(recruiters, id)
+		}
+	}
+	return recruiters
+}
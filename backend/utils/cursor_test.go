@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+type testCursor struct {
+	Score     int       `json:"s"`
+	AppliedAt time.Time `json:"a"`
+	ID        string    `json:"i"`
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		name     string
+		position testCursor
+	}{
+		{"zero value", testCursor{}},
+		{"full position", testCursor{Score: 87, AppliedAt: time.Date(2026, 3, 14, 9, 30, 0, 0, time.UTC), ID: "5f0c1e8a-3b7d-4d8e-9a41-0c2b6f1d7e90"}},
+		{"characters that need escaping", testCursor{Score: -1, ID: "a/b+c=d?&"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := EncodeCursor(tt.position)
+			if err != nil {
+				t.Fatalf("EncodeCursor: %v", err)
+			}
+			for _, r := range cursor {
+				if r == '+' || r == '/' || r == '=' {
+					t.Fatalf("cursor %q isn't URL safe", cursor)
+				}
+			}
+
+			var decoded testCursor
+			if err := DecodeCursor(cursor, &decoded); err != nil {
+				t.Fatalf("DecodeCursor: %v", err)
+			}
+			if !decoded.AppliedAt.Equal(tt.position.AppliedAt) || decoded.Score != tt.position.Score || decoded.ID != tt.position.ID {
+				t.Errorf("decoded %+v, want %+v", decoded, tt.position)
+			}
+		})
+	}
+}
+
+func TestDecodeCursorRejectsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"not JSON", "bm90IGpzb24"},
+		{"wrong shape", "WzEsMiwzXQ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var decoded testCursor
+			if err := DecodeCursor(tt.cursor, &decoded); err == nil {
+				t.Errorf("DecodeCursor(%q) = nil error, want invalid cursor", tt.cursor)
+			}
+		})
+	}
+}
@@ -21,6 +21,19 @@ type SuperAdminClaims struct {
 	jwt.RegisteredClaims
 }
 
+// Candidate token purposes. Magic link tokens are only exchanged for a session; sessions call the candidate API.
+const (
+	CandidateMagicLinkPurpose = "candidate_magic_link"
+	CandidateSessionPurpose   = "candidate_session"
+)
+
+// CandidateClaims identify a candidate by the email address they applied with
+type CandidateClaims struct {
+	CandidateEmail string `json:"candidate_email"`
+	Purpose        string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
 func GenerateJWT(adminID, companyID string) (string, error) {
 	secret := os.Getenv("JWT_SECRET")
 	if secret == "" {
@@ -112,3 +125,49 @@ func VerifySuperAdminJWT(tokenString string) (*SuperAdminClaims, error) {
 
 	return nil, errors.New("invalid token")
 }
+
+// GenerateCandidateJWT signs a candidate token for purpose that expires after ttl. tokenID is the
+// token's unique ID (jti), used to make magic links single use.
+func GenerateCandidateJWT(email, purpose, tokenID string, ttl time.Duration) (string, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "your-secret-key-change-in-production"
+	}
+
+	claims := CandidateClaims{
+		CandidateEmail: email,
+		Purpose:        purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        tokenID,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString([]byte(secret))
+}
+
+// VerifyCandidateJWT checks a candidate token and that it was issued for purpose
+func VerifyCandidateJWT(tokenString, purpose string) (*CandidateClaims, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		secret = "your-secret-key-change-in-production"
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &CandidateClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, errors.New("unexpected signing method")
+		}
+		return []byte(secret), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*CandidateClaims)
+	if !ok || !token.Valid || claims.Purpose != purpose || claims.CandidateEmail == "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
@@ -147,7 +147,10 @@ export default function ApplicationsPage() {
       return;
 
     try {
-      const response = await applicationAPI.bulkDelete(status);
+      // Rejected covers every rejected stage, whatever a custom pipeline calls it
+      const response = await applicationAPI.bulkDelete(
+        status === "rejected" ? { stage_type: "rejected" } : { status }
+      );
       toast.success(
         response.data.message ||
           `Deleted ${response.data.deleted_count} ${label} application(s)`
@@ -3,11 +3,15 @@
 import { useEffect, useState } from "react";
 import { candidatePortalAPI, ApplicationStatus } from "@/lib/api";
 import { toast } from "@/components/Toast";
-import { useSearchParams } from "next/navigation";
+import { useRouter, useSearchParams } from "next/navigation";
 
 export default function ApplicationStatusPage() {
+  const router = useRouter();
   const searchParams = useSearchParams();
   const [email, setEmail] = useState("");
+  const [linkSent, setLinkSent] = useState(false);
+  const [sessionEmail, setSessionEmail] = useState<string | null>(null);
+  const [applications, setApplications] = useState<ApplicationStatus[]>([]);
   const [applicationId, setApplicationId] = useState("");
   const [application, setApplication] = useState<ApplicationStatus | null>(
     null
@@ -19,46 +23,122 @@ export default function ApplicationStatusPage() {
   // const [messagesLoading, setMessagesLoading] = useState(false);
   // const [showMessages, setShowMessages] = useState(false);
 
-  // Pre-fill email and application ID from URL parameters
+  // Sign in with the magic link token, or resume the session of this tab
   useEffect(() => {
     const emailParam = searchParams?.get("email");
     const applicationIdParam = searchParams?.get("applicationId");
+    const tokenParam = searchParams?.get("token");
     if (emailParam) setEmail(emailParam);
     if (applicationIdParam) setApplicationId(applicationIdParam);
 
-    // Auto-check status if both are provided
-    if (emailParam && applicationIdParam) {
-      handleCheckStatus(emailParam, applicationIdParam);
+    if (tokenParam) {
+      handleSignIn(tokenParam);
+    } else if (sessionStorage.getItem("candidate_token")) {
+      setSessionEmail(sessionStorage.getItem("candidate_email"));
+      loadApplications(applicationIdParam || undefined);
     }
     // eslint-disable-next-line react-hooks/exhaustive-deps
   }, [searchParams]);
 
-  const handleCheckStatus = async (
-    emailParam?: string,
-    applicationIdParam?: string
-  ) => {
-    const emailToUse = emailParam || email;
-    const applicationIdToUse = applicationIdParam || applicationId;
+  const signOut = () => {
+    sessionStorage.removeItem("candidate_token");
+    sessionStorage.removeItem("candidate_email");
+    setSessionEmail(null);
+    setApplications([]);
+    setApplication(null);
+    setLinkSent(false);
+  };
 
-    if (!emailToUse || !applicationIdToUse) {
-      toast.error("Please enter both email and application ID");
+  const handleRequestLink = async () => {
+    if (!email) {
+      toast.error("Please enter your email");
       return;
     }
 
     setLoading(true);
     try {
-      const response = await candidatePortalAPI.checkStatus(
-        emailToUse,
-        applicationIdToUse
+      const response = await candidatePortalAPI.requestLink(email);
+      setLinkSent(true);
+      toast.success(response.data.message);
+    } catch (error: any) {
+      console.error("Failed to request sign-in link:", error);
+      toast.error(
+        error.response?.data?.error ||
+          "Failed to send sign-in link. Please try again."
+      );
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleSignIn = async (token: string) => {
+    setLoading(true);
+    try {
+      const response = await candidatePortalAPI.createSession(token);
+      sessionStorage.setItem("candidate_token", response.data.token);
+      sessionStorage.setItem("candidate_email", response.data.email);
+      setSessionEmail(response.data.email);
+      // Don't leave the used link in the address bar or history
+      window.history.replaceState(null, "", window.location.pathname);
+
+      // Go back to the page that asked the candidate to sign in
+      const returnTo = localStorage.getItem("candidate_return_to");
+      localStorage.removeItem("candidate_return_to");
+      if (returnTo && returnTo.startsWith("/") && !returnTo.startsWith("//")) {
+        router.replace(returnTo);
+        return;
+      }
+      await loadApplications();
+    } catch (error: any) {
+      console.error("Failed to sign in:", error);
+      toast.error(
+        error.response?.data?.error ||
+          "This sign-in link is invalid or has expired, please request a new one."
       );
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const loadApplications = async (selectId?: string) => {
+    try {
+      const response = await candidatePortalAPI.getApplications();
+      const list = response.data.applications;
+      setApplications(list);
+      const selected = selectId
+        ? list.find((app) => app.id === selectId)
+        : list.length === 1
+        ? list[0]
+        : undefined;
+      if (selected) {
+        handleCheckStatus(selected.id);
+      }
+    } catch (error: any) {
+      console.error("Failed to load applications:", error);
+      if (error.response?.status === 401) {
+        signOut();
+        toast.error("Your session has expired. Please request a new sign-in link.");
+      } else {
+        toast.error(error.response?.data?.error || "Failed to load applications");
+      }
+    }
+  };
+
+  const handleCheckStatus = async (applicationIdToUse: string) => {
+    setApplicationId(applicationIdToUse);
+    setLoading(true);
+    try {
+      const response = await candidatePortalAPI.checkStatus(applicationIdToUse);
       setApplication(response.data.application);
       // Messaging functionality commented out for now
       // loadMessages();
     } catch (error: any) {
       console.error("Failed to check status:", error);
+      if (error.response?.status === 401) {
+        signOut();
+      }
       toast.error(
-        error.response?.data?.error ||
-          "Failed to load application status. Please check your email and application ID."
+        error.response?.data?.error || "Failed to load application status."
       );
       setApplication(null);
     } finally {
@@ -68,10 +148,10 @@ export default function ApplicationStatusPage() {
 
   // Messaging functionality commented out for now
   // const loadMessages = async () => {
-  //   if (!email || !applicationId) return;
+  //   if (!applicationId) return;
   //   setMessagesLoading(true);
   //   try {
-  //     const response = await candidatePortalAPI.getMessages(applicationId, email);
+  //     const response = await candidatePortalAPI.getMessages(applicationId);
   //     setMessages(response.data.messages);
   //   } catch (error: any) {
   //     console.error("Failed to load messages:", error);
@@ -81,17 +161,17 @@ export default function ApplicationStatusPage() {
   // };
 
   // const handleSendMessage = async () => {
-  //   if (!newMessage.trim() || !email || !applicationId) {
+  //   if (!newMessage.trim() || !applicationId) {
   //     toast.error("Please enter a message");
   //     return;
   //   }
   //   try {
-  //     await candidatePortalAPI.sendMessage(applicationId, email, newMessage.trim());
+  //     await candidatePortalAPI.sendMessage(applicationId, newMessage.trim());
   //     setNewMessage("");
   //     toast.success("Message sent successfully!");
   //     loadMessages();
   //     if (application) {
-  //       const response = await candidatePortalAPI.checkStatus(email, applicationId);
+  //       const response = await candidatePortalAPI.checkStatus(applicationId);
   //       setApplication(response.data.application);
   //     }
   //   } catch (error: any) {
@@ -139,42 +219,104 @@ export default function ApplicationStatusPage() {
       <div className="max-w-4xl mx-auto">
         <div className="bg-white rounded-lg shadow-lg p-6 mb-6">
           <h1 className="text-3xl font-bold mb-2">Application Status Portal</h1>
-          <p className="text-gray-600 mb-6">
-            Enter your email and application ID to check your application status
-          </p>
+          {!sessionEmail ? (
+            <>
+              <p className="text-gray-600 mb-6">
+                Enter the email you applied with and we&apos;ll send you a link
+                to sign in and check your applications
+              </p>
 
-          <div className="grid grid-cols-1 md:grid-cols-2 gap-4 mb-4">
-            <div>
-              <label className="block text-sm font-medium mb-2">Email</label>
-              <input
-                type="email"
-                className="w-full px-4 py-2 border rounded-lg"
-                placeholder="your.email@example.com"
-                value={email}
-                onChange={(e) => setEmail(e.target.value)}
-              />
-            </div>
-            <div>
-              <label className="block text-sm font-medium mb-2">
-                Application ID
-              </label>
-              <input
-                type="text"
-                className="w-full px-4 py-2 border rounded-lg"
-                placeholder="Application ID"
-                value={applicationId}
-                onChange={(e) => setApplicationId(e.target.value)}
-              />
-            </div>
-          </div>
+              <div className="mb-4">
+                <label className="block text-sm font-medium mb-2">Email</label>
+                <input
+                  type="email"
+                  className="w-full px-4 py-2 border rounded-lg"
+                  placeholder="your.email@example.com"
+                  value={email}
+                  onChange={(e) => setEmail(e.target.value)}
+                  onKeyDown={(e) => {
+                    if (e.key === "Enter") {
+                      handleRequestLink();
+                    }
+                  }}
+                />
+              </div>
 
-          <button
-            onClick={() => handleCheckStatus()}
-            disabled={loading}
-            className="w-full bg-blue-600 text-white px-6 py-3 rounded-lg hover:bg-blue-700 disabled:bg-blue-300 font-semibold"
-          >
-            {loading ? "Loading..." : "Check Status"}
-          </button>
+              {linkSent && (
+                <div className="bg-blue-50 border border-blue-200 rounded-lg p-4 mb-4">
+                  <p className="text-blue-800">
+                    Check your inbox. If you have applied with this email,
+                    you&apos;ll find a sign-in link that is valid for 15
+                    minutes.
+                  </p>
+                </div>
+              )}
+
+              <button
+                onClick={handleRequestLink}
+                disabled={loading}
+                className="w-full bg-blue-600 text-white px-6 py-3 rounded-lg hover:bg-blue-700 disabled:bg-blue-300 font-semibold"
+              >
+                {loading
+                  ? "Loading..."
+                  : linkSent
+                  ? "Send Another Link"
+                  : "Email Me a Sign-In Link"}
+              </button>
+            </>
+          ) : (
+            <>
+              <div className="flex items-center justify-between mb-6">
+                <p className="text-gray-600">
+                  Signed in as <strong>{sessionEmail}</strong>
+                </p>
+                <button
+                  onClick={signOut}
+                  className="text-blue-600 hover:text-blue-800 text-sm font-medium"
+                >
+                  Sign Out
+                </button>
+              </div>
+
+              {applications.length === 0 ? (
+                <p className="text-gray-500">
+                  {loading ? "Loading..." : "No applications found."}
+                </p>
+              ) : (
+                <div className="space-y-2">
+                  {applications.map((app) => (
+                    <button
+                      key={app.id}
+                      onClick={() => handleCheckStatus(app.id)}
+                      disabled={loading}
+                      className={`w-full flex items-center justify-between px-4 py-3 border rounded-lg text-left hover:bg-gray-50 ${
+                        app.id === applicationId
+                          ? "border-blue-500 bg-blue-50"
+                          : ""
+                      }`}
+                    >
+                      <div>
+                        <p className="font-medium">
+                          {app.job.title || "Position no longer listed"}
+                        </p>
+                        <p className="text-sm text-gray-500">
+                          Applied on{" "}
+                          {new Date(app.applied_at).toLocaleDateString()}
+                        </p>
+                      </div>
+                      <span
+                        className={`px-3 py-1 rounded-full text-xs font-semibold border ${getStatusColor(
+                          app.status
+                        )}`}
+                      >
+                        {app.status_label || app.status}
+                      </span>
+                    </button>
+                  ))}
+                </div>
+              )}
+            </>
+          )}
         </div>
 
         {application && (
@@ -1,14 +1,17 @@
 "use client";
 
 import { useEffect, useState, useRef } from "react";
-import { useParams, useRouter } from "next/navigation";
+import { useParams, useRouter, useSearchParams } from "next/navigation";
 import { applicationAPI, jobAPI, uploadAPI, Job } from "@/lib/api";
 import { toast } from "@/components/Toast";
 
 export default function ApplyPage() {
   const params = useParams();
   const router = useRouter();
+  const searchParams = useSearchParams();
   const jobId = params.jobId as string;
+  // Job board feeds link here with ?source=<board>, recorded as the referral source
+  const source = searchParams?.get("source") || undefined;
   const [job, setJob] = useState<Job | null>(null);
   const [loading, setLoading] = useState(false);
   const [uploadingCV, setUploadingCV] = useState(false);
@@ -142,7 +145,7 @@ export default function ApplyPage() {
 
     setLoading(true);
     try {
-      const response = await applicationAPI.submit(formData);
+      const response = await applicationAPI.submit({ ...formData, referral_source: source });
       const applicationId = response.data.application?.id;
 
       toast.success(
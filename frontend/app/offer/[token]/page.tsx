@@ -0,0 +1,236 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { useParams } from "next/navigation";
+import { candidatePortalAPI, CandidateOffer } from "@/lib/api";
+import { toast } from "@/components/Toast";
+import CandidateSignIn from "@/components/CandidateSignIn";
+
+export default function OfferPage() {
+  const params = useParams();
+  const token = params.token as string;
+  const [signedIn, setSignedIn] = useState(true);
+  const [offer, setOffer] = useState<CandidateOffer | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [responding, setResponding] = useState(false);
+  const [notFound, setNotFound] = useState(false);
+  const [showDecline, setShowDecline] = useState(false);
+  const [declineReason, setDeclineReason] = useState("");
+
+  useEffect(() => {
+    if (!sessionStorage.getItem("candidate_token")) {
+      setSignedIn(false);
+      setLoading(false);
+      return;
+    }
+    fetchOffer();
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [token]);
+
+  const fetchOffer = async () => {
+    setLoading(true);
+    try {
+      const response = await candidatePortalAPI.getOffer(token);
+      setOffer(response.data.offer);
+    } catch (error: any) {
+      console.error("Failed to load offer:", error);
+      if (error.response?.status === 401) {
+        sessionStorage.removeItem("candidate_token");
+        sessionStorage.removeItem("candidate_email");
+        setSignedIn(false);
+      } else {
+        setNotFound(true);
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleRespond = async (accept: boolean) => {
+    if (
+      accept &&
+      !confirm("Accept this offer? You can't change your answer afterwards.")
+    ) {
+      return;
+    }
+
+    setResponding(true);
+    try {
+      const response = accept
+        ? await candidatePortalAPI.acceptOffer(token)
+        : await candidatePortalAPI.declineOffer(token, declineReason.trim());
+      setOffer(response.data.offer);
+      setShowDecline(false);
+      toast.success(response.data.message);
+    } catch (error: any) {
+      console.error("Failed to respond to offer:", error);
+      toast.error(
+        error.response?.data?.error ||
+          "Failed to record your response. Please try again."
+      );
+      if (error.response?.status === 409) {
+        fetchOffer();
+      }
+    } finally {
+      setResponding(false);
+    }
+  };
+
+  const formatMoney = (amount: number, currency: string) => {
+    try {
+      return new Intl.NumberFormat(undefined, {
+        style: "currency",
+        currency,
+      }).format(amount);
+    } catch {
+      return `${amount} ${currency}`;
+    }
+  };
+
+  const statusMessage = (status: string) => {
+    switch (status) {
+      case "accepted":
+        return "You accepted this offer.";
+      case "declined":
+        return "You declined this offer.";
+      case "expired":
+        return "This offer has expired. Please contact the hiring team.";
+      default:
+        return "This offer is no longer open. Please contact the hiring team.";
+    }
+  };
+
+  return (
+    <div className="min-h-screen bg-gray-50 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-3xl mx-auto">
+        {!signedIn ? (
+          <CandidateSignIn
+            returnTo={`/offer/${token}`}
+            message="Enter the email you applied with and we'll send you a link to sign in and view your offer"
+          />
+        ) : loading ? (
+          <div className="bg-white rounded-lg shadow-lg p-6 text-center text-gray-500">
+            Loading...
+          </div>
+        ) : notFound || !offer ? (
+          <div className="bg-white rounded-lg shadow-lg p-6">
+            <h1 className="text-2xl font-bold mb-2">Offer Not Found</h1>
+            <p className="text-gray-600">
+              This offer doesn&apos;t exist or isn&apos;t for the email you
+              signed in with.
+            </p>
+          </div>
+        ) : (
+          <div className="bg-white rounded-lg shadow-lg p-6">
+            <h1 className="text-2xl font-bold mb-2">{offer.job_title}</h1>
+            <p className="text-gray-600 mb-6">
+              Offer from {offer.company_name} for {offer.candidate_name}
+            </p>
+
+            <div className="grid grid-cols-1 sm:grid-cols-2 gap-4 mb-6">
+              <div>
+                <p className="text-sm text-gray-500">Salary</p>
+                <p className="font-semibold">
+                  {formatMoney(offer.salary, offer.currency)}
+                </p>
+              </div>
+              {offer.bonus > 0 && (
+                <div>
+                  <p className="text-sm text-gray-500">Bonus</p>
+                  <p className="font-semibold">
+                    {formatMoney(offer.bonus, offer.currency)}
+                  </p>
+                </div>
+              )}
+              {offer.start_date && (
+                <div>
+                  <p className="text-sm text-gray-500">Start Date</p>
+                  <p className="font-semibold">
+                    {new Date(offer.start_date).toLocaleDateString()}
+                  </p>
+                </div>
+              )}
+              <div>
+                <p className="text-sm text-gray-500">Respond By</p>
+                <p className="font-semibold">
+                  {new Date(offer.expires_at).toLocaleString()}
+                </p>
+              </div>
+            </div>
+
+            {offer.terms && (
+              <div className="mb-6">
+                <h3 className="font-semibold mb-2">Terms</h3>
+                <p className="text-gray-700 whitespace-pre-wrap border rounded-lg p-4 bg-gray-50">
+                  {offer.terms}
+                </p>
+              </div>
+            )}
+
+            {!offer.can_respond ? (
+              <div
+                className={`rounded-lg p-4 border ${
+                  offer.status === "accepted"
+                    ? "bg-green-50 border-green-200 text-green-800"
+                    : "bg-gray-50 border-gray-200 text-gray-700"
+                }`}
+              >
+                <p className="font-semibold">{statusMessage(offer.status)}</p>
+                {offer.responded_at && (
+                  <p className="text-sm mt-1">
+                    Answered on {new Date(offer.responded_at).toLocaleString()}
+                  </p>
+                )}
+              </div>
+            ) : showDecline ? (
+              <div>
+                <label className="block text-sm font-medium mb-2">
+                  Would you like to tell us why? (optional)
+                </label>
+                <textarea
+                  value={declineReason}
+                  onChange={(e) => setDeclineReason(e.target.value)}
+                  rows={3}
+                  className="w-full px-4 py-2 border rounded-lg focus:ring-2 focus:ring-blue-500 mb-4"
+                />
+                <div className="flex gap-3">
+                  <button
+                    onClick={() => handleRespond(false)}
+                    disabled={responding}
+                    className="flex-1 bg-red-600 text-white px-6 py-3 rounded-lg hover:bg-red-700 disabled:bg-red-300 font-semibold"
+                  >
+                    {responding ? "Sending..." : "Decline Offer"}
+                  </button>
+                  <button
+                    onClick={() => setShowDecline(false)}
+                    disabled={responding}
+                    className="flex-1 border px-6 py-3 rounded-lg hover:bg-gray-50 font-semibold"
+                  >
+                    Back
+                  </button>
+                </div>
+              </div>
+            ) : (
+              <div className="flex gap-3">
+                <button
+                  onClick={() => handleRespond(true)}
+                  disabled={responding}
+                  className="flex-1 bg-blue-600 text-white px-6 py-3 rounded-lg hover:bg-blue-700 disabled:bg-blue-300 font-semibold"
+                >
+                  {responding ? "Sending..." : "Accept Offer"}
+                </button>
+                <button
+                  onClick={() => setShowDecline(true)}
+                  disabled={responding}
+                  className="flex-1 border px-6 py-3 rounded-lg hover:bg-gray-50 font-semibold"
+                >
+                  Decline
+                </button>
+              </div>
+            )}
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
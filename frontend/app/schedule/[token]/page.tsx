@@ -0,0 +1,197 @@
+"use client";
+
+import { useEffect, useState } from "react";
+import { useParams } from "next/navigation";
+import {
+  candidatePortalAPI,
+  CandidateInterview,
+  CandidateSchedulingLink,
+  SchedulingSlot,
+} from "@/lib/api";
+import { toast } from "@/components/Toast";
+import CandidateSignIn from "@/components/CandidateSignIn";
+
+export default function SchedulePage() {
+  const params = useParams();
+  const token = params.token as string;
+  const [signedIn, setSignedIn] = useState(true);
+  const [link, setLink] = useState<CandidateSchedulingLink | null>(null);
+  const [booked, setBooked] = useState<CandidateInterview | null>(null);
+  const [selected, setSelected] = useState<string | null>(null);
+  const [loading, setLoading] = useState(true);
+  const [booking, setBooking] = useState(false);
+  const [notFound, setNotFound] = useState(false);
+
+  useEffect(() => {
+    if (!sessionStorage.getItem("candidate_token")) {
+      setSignedIn(false);
+      setLoading(false);
+      return;
+    }
+    fetchSlots();
+    // eslint-disable-next-line react-hooks/exhaustive-deps
+  }, [token]);
+
+  const fetchSlots = async () => {
+    setLoading(true);
+    try {
+      const response = await candidatePortalAPI.getSchedulingSlots(token);
+      setLink(response.data);
+      setBooked(response.data.booked_interview || null);
+    } catch (error: any) {
+      console.error("Failed to load scheduling link:", error);
+      if (error.response?.status === 401) {
+        sessionStorage.removeItem("candidate_token");
+        sessionStorage.removeItem("candidate_email");
+        setSignedIn(false);
+      } else {
+        setNotFound(true);
+      }
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const handleBook = async () => {
+    if (!selected) {
+      toast.error("Please choose a time");
+      return;
+    }
+
+    setBooking(true);
+    try {
+      const response = await candidatePortalAPI.bookSchedulingSlot(
+        token,
+        selected
+      );
+      setBooked(response.data.interview);
+      toast.success(response.data.message);
+    } catch (error: any) {
+      console.error("Failed to book interview:", error);
+      toast.error(
+        error.response?.data?.error ||
+          "Failed to book the interview. Please try again."
+      );
+      if (error.response?.status === 409) {
+        setSelected(null);
+        fetchSlots();
+      }
+    } finally {
+      setBooking(false);
+    }
+  };
+
+  // Group the slots by day in the candidate's own time zone
+  const slotsByDay = (slots: SchedulingSlot[]) => {
+    const days: { [day: string]: SchedulingSlot[] } = {};
+    slots.forEach((slot) => {
+      const day = new Date(slot.start_time).toLocaleDateString(undefined, {
+        weekday: "long",
+        month: "long",
+        day: "numeric",
+      });
+      days[day] = [...(days[day] || []), slot];
+    });
+    return days;
+  };
+
+  return (
+    <div className="min-h-screen bg-gray-50 py-12 px-4 sm:px-6 lg:px-8">
+      <div className="max-w-3xl mx-auto">
+        {!signedIn ? (
+          <CandidateSignIn
+            returnTo={`/schedule/${token}`}
+            message="Enter the email you applied with and we'll send you a link to sign in and pick your interview time"
+          />
+        ) : loading ? (
+          <div className="bg-white rounded-lg shadow-lg p-6 text-center text-gray-500">
+            Loading...
+          </div>
+        ) : notFound || !link ? (
+          <div className="bg-white rounded-lg shadow-lg p-6">
+            <h1 className="text-2xl font-bold mb-2">Link Not Found</h1>
+            <p className="text-gray-600">
+              This scheduling link doesn&apos;t exist or isn&apos;t for the
+              email you signed in with.
+            </p>
+          </div>
+        ) : (
+          <div className="bg-white rounded-lg shadow-lg p-6">
+            <h1 className="text-2xl font-bold mb-2">{link.round_name}</h1>
+            <p className="text-gray-600 mb-6">
+              {link.job.title} &middot; {link.duration_minutes} minutes
+              {link.location ? ` · ${link.location}` : ""}
+            </p>
+
+            {booked ? (
+              <div className="bg-green-50 border border-green-200 rounded-lg p-4">
+                <p className="text-green-800 font-semibold">
+                  ✓ Your interview is booked for {booked.when}
+                </p>
+                {booked.video_link && (
+                  <p className="text-green-700 text-sm mt-1">
+                    Join:{" "}
+                    <a href={booked.video_link} className="underline">
+                      {booked.video_link}
+                    </a>
+                  </p>
+                )}
+                <p className="text-green-700 text-sm mt-1">
+                  A calendar invitation is on its way to your inbox.
+                </p>
+              </div>
+            ) : !link.active ? (
+              <p className="text-gray-600">
+                This scheduling link is no longer active. Please contact the
+                hiring team if you still need to book a time.
+              </p>
+            ) : link.slots.length === 0 ? (
+              <p className="text-gray-600">
+                There are no free times left. Please contact the hiring team.
+              </p>
+            ) : (
+              <>
+                <p className="text-sm text-gray-500 mb-4">
+                  Times are shown in your time zone. Please book by{" "}
+                  {new Date(link.expires_at).toLocaleString()}.
+                </p>
+                <div className="space-y-4 mb-6">
+                  {Object.entries(slotsByDay(link.slots)).map(([day, slots]) => (
+                    <div key={day}>
+                      <h3 className="font-semibold mb-2">{day}</h3>
+                      <div className="flex flex-wrap gap-2">
+                        {slots.map((slot) => (
+                          <button
+                            key={slot.start_time}
+                            onClick={() => setSelected(slot.start_time)}
+                            className={`px-3 py-2 border rounded-lg text-sm ${
+                              selected === slot.start_time
+                                ? "bg-blue-600 text-white border-blue-600"
+                                : "hover:bg-gray-50"
+                            }`}
+                          >
+                            {new Date(slot.start_time).toLocaleTimeString([], {
+                              hour: "2-digit",
+                              minute: "2-digit",
+                            })}
+                          </button>
+                        ))}
+                      </div>
+                    </div>
+                  ))}
+                </div>
+                <button
+                  onClick={handleBook}
+                  disabled={!selected || booking}
+                  className="w-full bg-blue-600 text-white px-6 py-3 rounded-lg hover:bg-blue-700 disabled:bg-blue-300 font-semibold"
+                >
+                  {booking ? "Booking..." : "Book This Time"}
+                </button>
+              </>
+            )}
+          </div>
+        )}
+      </div>
+    </div>
+  );
+}
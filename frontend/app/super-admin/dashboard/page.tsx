@@ -79,14 +79,14 @@ export default function SuperAdminDashboardPage() {
             icon="📝"
           />
           <StatsCard
-            title="Pending"
-            value={stats?.pending_applications || 0}
+            title="In Process"
+            value={stats?.active_applications || 0}
             color="yellow"
             icon="⏳"
           />
           <StatsCard
-            title="Shortlisted"
-            value={stats?.shortlisted_applications || 0}
+            title="Hired"
+            value={stats?.hired_applications || 0}
             color="green"
             icon="⭐"
           />
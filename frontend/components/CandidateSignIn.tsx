@@ -0,0 +1,87 @@
+"use client";
+
+import { useState } from "react";
+import { candidatePortalAPI } from "@/lib/api";
+import { toast } from "@/components/Toast";
+
+// Asks a candidate to sign in with a magic link. The page they were on is remembered,
+// and the application status page sends them back to it once they've signed in.
+export default function CandidateSignIn({
+  returnTo,
+  message,
+}: {
+  returnTo: string;
+  message: string;
+}) {
+  const [email, setEmail] = useState("");
+  const [loading, setLoading] = useState(false);
+  const [linkSent, setLinkSent] = useState(false);
+
+  const handleRequestLink = async () => {
+    if (!email) {
+      toast.error("Please enter your email");
+      return;
+    }
+
+    setLoading(true);
+    try {
+      localStorage.setItem("candidate_return_to", returnTo);
+      const response = await candidatePortalAPI.requestLink(email);
+      setLinkSent(true);
+      toast.success(response.data.message);
+    } catch (error: any) {
+      console.error("Failed to request sign-in link:", error);
+      toast.error(
+        error.response?.data?.error ||
+          "Failed to send sign-in link. Please try again."
+      );
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  return (
+    <div className="bg-white rounded-lg shadow-lg p-6">
+      <h1 className="text-2xl font-bold mb-2">Sign In</h1>
+      <p className="text-gray-600 mb-6">{message}</p>
+
+      <div className="mb-4">
+        <label className="block text-sm font-medium mb-2">Email</label>
+        <input
+          type="email"
+          className="w-full px-4 py-2 border rounded-lg"
+          placeholder="your.email@example.com"
+          value={email}
+          onChange={(e) => setEmail(e.target.value)}
+          onKeyDown={(e) => {
+            if (e.key === "Enter") {
+              handleRequestLink();
+            }
+          }}
+        />
+      </div>
+
+      {linkSent && (
+        <div className="bg-blue-50 border border-blue-200 rounded-lg p-4 mb-4">
+          <p className="text-blue-800">
+            Check your inbox. If you have applied with this email, you&apos;ll
+            find a sign-in link that is valid for 15 minutes. It brings you
+            back here.
+          </p>
+        </div>
+      )}
+
+      <button
+        onClick={handleRequestLink}
+        disabled={loading}
+        className="w-full bg-blue-600 text-white px-6 py-3 rounded-lg hover:bg-blue-700 disabled:bg-blue-300 font-semibold"
+      >
+        {loading
+          ? "Loading..."
+          : linkSent
+          ? "Send Another Link"
+          : "Email Me a Sign-In Link"}
+      </button>
+    </div>
+  );
+}
@@ -73,9 +73,14 @@ export interface Job {
   requirements?: string;
   location?: string;
   job_type?: string;
+  department?: string;
+  slug?: string;
   salary_range?: string;
   deadline: string;
   status: string;
+  requisition_status?: string; // Set when the company requires approval before jobs go live
+  headcount?: number; // Positions to fill, 0 = not limited
+  hired_count?: number;
   auto_shortlist: boolean;
   created_at: string;
   updated_at: string;
@@ -106,6 +111,11 @@ export interface Application {
   referred_by_phone?: string;
   in_talent_pool?: boolean;
   talent_pool_added_at?: string;
+  // Consent given when applying
+  privacy_notice_version?: number;
+  consented_at?: string;
+  talent_pool_consent?: boolean;
+  marketing_consent?: boolean;
   job?: Job;
 }
 
@@ -135,8 +145,8 @@ export interface SuperAdminStats {
   total_jobs: number;
   open_jobs: number;
   total_applications: number;
-  pending_applications: number;
-  shortlisted_applications: number;
+  active_applications: number;
+  hired_applications: number;
   total_admins: number;
 }
 
@@ -290,10 +300,10 @@ export const applicationAPI = {
     api.put<{ message: string }>(`/applications/${id}/reject`),
   delete: (id: string) =>
     api.delete<{ message: string }>(`/applications/${id}`),
-  bulkDelete: (status: string) =>
+  bulkDelete: (filter: { status?: string; stage_type?: string }) =>
     api.post<{ message: string; deleted_count: number; total_found: number }>(
       "/applications/bulk-delete",
-      { status }
+      filter
     ),
   trackCVView: (id: string) =>
     api.post<{ message: string; application: Application }>(
@@ -434,40 +444,126 @@ export interface Message {
   created_at: string;
 }
 
-// Candidate Portal APIs (public, no auth - use separate axios instance)
-const publicApi = axios.create({
+export interface CandidateInterview {
+  id: string;
+  round_name: string;
+  start_time: string;
+  end_time: string;
+  time_zone: string;
+  when: string;
+  location?: string;
+  video_link?: string;
+  status: string;
+}
+
+export interface SchedulingSlot {
+  start_time: string;
+  end_time: string;
+}
+
+export interface CandidateSchedulingLink {
+  round_name: string;
+  duration_minutes: number;
+  time_zone: string;
+  location?: string;
+  status: string;
+  expires_at: string;
+  candidate_name: string;
+  job: {
+    id: string;
+    title: string;
+  };
+  slots: SchedulingSlot[];
+  active: boolean;
+  booked_interview?: CandidateInterview;
+}
+
+export interface CandidateOffer {
+  job_title: string;
+  company_name: string;
+  candidate_name: string;
+  salary: number;
+  currency: string;
+  bonus: number;
+  start_date?: string;
+  expires_at: string;
+  terms: string;
+  status: string;
+  responded_at?: string;
+  can_respond: boolean;
+}
+
+export interface CandidateSession {
+  token: string;
+  email: string;
+  expires_at: string;
+}
+
+// Candidate Portal APIs (candidate session, separate from the admin token)
+const candidateApi = axios.create({
   baseURL: API_BASE_URL,
-  // No auth interceptor for public endpoints
 });
 
-// Candidate Portal APIs (public, no auth)
+// Add the candidate session token to requests if available
+candidateApi.interceptors.request.use((config) => {
+  if (typeof window !== "undefined") {
+    const token = sessionStorage.getItem("candidate_token");
+    if (token) {
+      config.headers.Authorization = `Bearer ${token}`;
+    }
+  }
+  return config;
+});
+
+// Candidate Portal APIs (sign in with a magic link, then scoped to the candidate's email)
 export const candidatePortalAPI = {
-  checkStatus: (email: string, applicationId: string) =>
-    publicApi.post<{ application: ApplicationStatus }>("/candidate/status", {
+  requestLink: (email: string) =>
+    candidateApi.post<{ message: string }>("/candidate/auth/request-link", {
       email,
-      application_id: applicationId,
     }),
-  getByEmail: (email: string) =>
-    publicApi.get<{ applications: ApplicationStatus[]; count: number }>(
-      "/candidate/applications",
-      { params: { email } }
+  createSession: (token: string) =>
+    candidateApi.post<CandidateSession>("/candidate/auth/session", { token }),
+  getApplications: () =>
+    candidateApi.get<{ applications: ApplicationStatus[]; count: number }>(
+      "/candidate/applications"
     ),
-  sendMessage: (applicationId: string, email: string, message: string) =>
-    publicApi.post<{ message: string; data: Message }>(
+  checkStatus: (applicationId: string) =>
+    candidateApi.post<{ application: ApplicationStatus }>("/candidate/status", {
+      application_id: applicationId,
+    }),
+  sendMessage: (applicationId: string, message: string) =>
+    candidateApi.post<{ message: string; data: Message }>(
       "/candidate/messages/send",
       {
         application_id: applicationId,
-        sender_email: email,
         message: message,
       }
     ),
-  getMessages: (applicationId: string, email: string) =>
-    publicApi.get<{ messages: Message[]; count: number }>(
+  getMessages: (applicationId: string) =>
+    candidateApi.get<{ messages: Message[]; count: number }>(
       "/candidate/messages",
       {
-        params: { application_id: applicationId, email },
+        params: { application_id: applicationId },
       }
     ),
+  getSchedulingSlots: (token: string) =>
+    candidateApi.get<CandidateSchedulingLink>(`/candidate/scheduling/${token}`),
+  bookSchedulingSlot: (token: string, startTime: string) =>
+    candidateApi.post<{ message: string; interview: CandidateInterview }>(
+      `/candidate/scheduling/${token}/book`,
+      { start_time: startTime }
+    ),
+  getOffer: (token: string) =>
+    candidateApi.get<{ offer: CandidateOffer }>(`/candidate/offers/${token}`),
+  acceptOffer: (token: string) =>
+    candidateApi.post<{ message: string; offer: CandidateOffer }>(
+      `/candidate/offers/${token}/accept`
+    ),
+  declineOffer: (token: string, reason: string) =>
+    candidateApi.post<{ message: string; offer: CandidateOffer }>(
+      `/candidate/offers/${token}/decline`,
+      { reason }
+    ),
 };
 
 // CV Matching Types (Local matching, no AI required)